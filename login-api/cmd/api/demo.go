@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"login-api/internal/config"
+	"login-api/internal/handler"
+	"login-api/internal/model"
+	"login-api/internal/router"
+	"login-api/internal/seed"
+	"login-api/internal/service"
+	"login-api/internal/storage"
+	"login-api/internal/storage/memory"
+	"login-api/internal/storage/sqlite"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// liteUserStore adalah store pengguna yang dibutuhkan oleh mode ringan.
+type liteUserStore interface {
+	storage.UserStore
+	storage.UserPreferenceStore
+}
+
+// runDemo menjalankan API tanpa database dengan data contoh di memori.
+func runDemo(cfg *config.Config) {
+	loc := defaultLocation(cfg)
+	users, payments := demoData(loc)
+	log.Warn().Msg("Mode demo aktif: data disimpan di memori dan hilang saat server berhenti")
+	serveLite(cfg, loc, memory.NewMemoryUserStore(users...), memory.NewMemoryPaymentStore(payments))
+}
+
+// runSQLite menjalankan API dengan database SQLite. Pada mode demo, data contoh
+// ditulis ke database bila belum ada pembayaran sama sekali.
+func runSQLite(cfg *config.Config) {
+	ctx := context.Background()
+	loc := defaultLocation(cfg)
+
+	db, err := sqlite.Open(cfg.SQLitePath, cfg.DBQueryTimeout)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Tidak dapat membuka database SQLite")
+	}
+	defer db.Close()
+	log.Info().Str("path", cfg.SQLitePath).Msg("Database SQLite berhasil dibuka!")
+
+	userStore := sqlite.NewSQLiteUserStore(db)
+	paymentStore := sqlite.NewSQLitePaymentStore(db)
+
+	if cfg.DemoMode {
+		count, err := paymentStore.CountPayments(ctx)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Gagal memeriksa data SQLite")
+		}
+		if count == 0 {
+			users, payments := demoData(loc)
+			for _, u := range users {
+				if _, err := userStore.GetUser(ctx, u.Email); err == nil {
+					continue
+				}
+				if err := userStore.CreateUser(ctx, u); err != nil {
+					log.Fatal().Err(err).Msg("Gagal menyimpan pengguna contoh")
+				}
+			}
+			if _, err := paymentStore.InsertPayments(ctx, payments); err != nil {
+				log.Fatal().Err(err).Msg("Gagal menyimpan pembayaran contoh")
+			}
+			log.Info().Int("payments", len(payments)).Msg("Data contoh ditulis ke database SQLite")
+		}
+	}
+
+	serveLite(cfg, loc, userStore, paymentStore)
+}
+
+// serveLite menjalankan API hanya dengan store pengguna dan pembayaran: route
+// autentikasi, pembayaran, dashboard, dan preferensi. Pekerja latar belakang
+// tidak dijalankan dan route fitur lain tidak didaftarkan.
+func serveLite(cfg *config.Config, loc *time.Location, users liteUserStore, payments storage.PaymentStore) {
+	jwtKey := []byte(cfg.JWTSecretKey)
+	authService := service.NewAuthService(users, jwtKey)
+	preferenceService := service.NewPreferenceService(users, loc)
+	dashboardService := service.NewDashboardService(payments, cfg.DashboardCacheTTL)
+
+	r := router.NewRouter(router.Handlers{
+		Auth:       handler.NewAuthHandler(authService, jwtKey),
+		Payment:    handler.NewPaymentHandler(payments),
+		Dashboard:  handler.NewDashboardHandler(dashboardService, preferenceService),
+		Preference: handler.NewPreferenceHandler(preferenceService),
+	})
+	serve(cfg.ServerAddress, cfg.MetricsAddress, r, func() {})
+}
+
+func defaultLocation(cfg *config.Config) *time.Location {
+	loc, err := service.LoadTimezone(cfg.DefaultTimezone)
+	if err != nil {
+		log.Fatal().Err(err).Msg("DEFAULT_TIMEZONE tidak valid")
+	}
+	return loc
+}
+
+// demoData membentuk pengguna dan pembayaran contoh dengan seed.Generate dan
+// opsi bawaan, sehingga datanya identik setiap kali dibuat pada hari yang sama.
+// Tanpa tabel pelanggan, ID pelanggan adalah urutannya di data contoh.
+func demoData(loc *time.Location) ([]model.User, []model.Payment) {
+	opts := seed.DefaultOptions
+	tomorrow := time.Now().In(loc).AddDate(0, 0, 1)
+	opts.End = time.Date(tomorrow.Year(), tomorrow.Month(), tomorrow.Day(), 0, 0, 0, 0, loc)
+	ds := seed.Generate(opts)
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(seed.DemoPassword), bcrypt.DefaultCost)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Gagal membuat hash kata sandi demo")
+	}
+	users := make([]model.User, len(ds.Users))
+	for i, email := range ds.Users {
+		users[i] = model.User{Email: email, PasswordHash: string(hash), Role: model.RoleUser}
+		if i == 0 {
+			users[i].Role = model.RoleAdmin
+		}
+	}
+
+	payments := make([]model.Payment, len(ds.Payments))
+	for i, p := range ds.Payments {
+		customerID := p.Customer + 1
+		dueDate := p.DueDate
+		payments[i] = model.Payment{
+			CustomerID:   &customerID,
+			CustomerName: ds.Customers[p.Customer].Name,
+			Amount:       p.Amount,
+			Status:       p.Status,
+			PaymentDate:  p.PaymentDate,
+			DueDate:      &dueDate,
+		}
+	}
+
+	log.Info().
+		Int("payments", len(payments)).
+		Strs("users", ds.Users).
+		Str("password", seed.DemoPassword).
+		Msg("Data contoh dibuat")
+	return users, payments
+}
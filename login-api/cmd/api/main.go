@@ -3,8 +3,14 @@ package main
 import (
 	"context"
 	"errors"
+	"login-api/internal/cli"
 	"login-api/internal/config"
+	"login-api/internal/events"
 	"login-api/internal/handler"
+	"login-api/internal/logging"
+	"login-api/internal/mailer"
+	"login-api/internal/metrics"
+	"login-api/internal/model"
 	"login-api/internal/router"
 	"login-api/internal/service"
 	"login-api/internal/storage/postgres"
@@ -20,10 +26,37 @@ import (
 )
 
 func main() {
-	zerolog.TimeFieldFormat = zerolog.TimeFormatUnix
-	log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr})
-
+	// Logger sementara untuk pesan saat memuat konfigurasi, lalu diganti sesuai
+	// LOG_FORMAT dan LOG_LEVEL.
+	logging.Setup(logging.FormatConsole, zerolog.InfoLevel)
 	cfg := config.New()
+	logging.Setup(cfg.LogFormat, cfg.LogLevel)
+
+	// Subcommand "migrate" hanya mengelola skema PostgreSQL lalu keluar tanpa
+	// menjalankan server. Subcommand dibaca lebih dulu agar tidak diabaikan diam-diam
+	// oleh backend lain.
+	var command string
+	if len(os.Args) > 1 {
+		command = os.Args[1]
+	}
+	switch {
+	case command != "" && command != "migrate":
+		log.Fatal().Str("command", command).Msg("Subcommand tidak dikenal; yang tersedia hanya migrate")
+	case command == "migrate" && cfg.DatabaseDriver == config.DatabaseSQLite:
+		log.Fatal().Msg("Subcommand migrate hanya untuk PostgreSQL; skema SQLite dibuat otomatis saat server dijalankan")
+	case command == "migrate" && cfg.DemoMode:
+		log.Fatal().Msg("Subcommand migrate tidak tersedia pada mode demo karena data disimpan di memori")
+	}
+
+	// SQLite dan mode demo hanya menjalankan fitur pengguna, pembayaran, dan dashboard.
+	switch {
+	case cfg.DatabaseDriver == config.DatabaseSQLite:
+		runSQLite(cfg)
+		return
+	case cfg.DemoMode:
+		runDemo(cfg)
+		return
+	}
 
 	dbpool, err := pgxpool.New(context.Background(), cfg.DatabaseURL)
 	if err != nil {
@@ -35,28 +68,149 @@ func main() {
 		log.Fatal().Err(err).Msg("Tidak dapat melakukan ping ke database")
 	}
 	log.Info().Msg("Database berhasil terhubung!")
+	metrics.Registry.MustRegister(metrics.NewPoolCollector(dbpool))
+
+	if command == "migrate" {
+		if err := cli.Migrate(context.Background(), dbpool, os.Args[2:], os.Stdout); err != nil {
+			log.Fatal().Err(err).Msg("Migrasi database gagal")
+		}
+		return
+	}
+	if cfg.MigrateOnStart {
+		migrator, err := postgres.NewMigrator(dbpool)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Tidak dapat memuat migrasi database")
+		}
+		applied, err := migrator.Up(context.Background())
+		if err != nil {
+			log.Fatal().Err(err).Msg("Migrasi database gagal")
+		}
+		log.Info().Int("applied", len(applied)).Msg("Skema database mutakhir")
+	}
+
+	defaultLocation, err := service.LoadTimezone(cfg.DefaultTimezone)
+	if err != nil {
+		log.Fatal().Err(err).Msg("DEFAULT_TIMEZONE tidak valid")
+	}
 
 	// Inisialisasi lapisan penyimpanan (storage)
-	userStore := postgres.NewPostgresUserStore(dbpool)
-	paymentStore := postgres.NewPostgresPaymentStore(dbpool)
+	db := postgres.NewDB(dbpool, cfg.DBQueryTimeout)
+	userStore := postgres.NewPostgresUserStore(db)
+	paymentStore := postgres.NewPostgresPaymentStore(db)
+	invoiceStore := postgres.NewPostgresInvoiceStore(db, cfg.InvoicePrefix, defaultLocation)
+	customerStore := postgres.NewPostgresCustomerStore(db)
+	subscriptionStore := postgres.NewPostgresSubscriptionStore(db)
+	reminderStore := postgres.NewPostgresReminderStore(db)
+	gatewayEventStore := postgres.NewPostgresGatewayEventStore(db)
+	webhookStore := postgres.NewPostgresWebhookStore(db)
+	outboxStore := postgres.NewPostgresOutboxStore(db)
+	reconciliationStore := postgres.NewPostgresReconciliationStore(db)
+	paymentListener := postgres.NewPostgresPaymentListener(dbpool)
+	alertStore := postgres.NewPostgresAlertStore(db)
+	reportStore := postgres.NewPostgresReportStore(db)
+
+	var mail mailer.Mailer = mailer.LogMailer{}
+	if cfg.SMTPHost != "" {
+		mail = mailer.NewSMTPMailer(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.SMTPFrom)
+	}
+	company := model.Company{
+		Name:    cfg.CompanyName,
+		Address: cfg.CompanyAddress,
+		Email:   cfg.CompanyEmail,
+		Phone:   cfg.CompanyPhone,
+	}
 
 	jwtKey := []byte(cfg.JWTSecretKey)
 	addr := cfg.ServerAddress
 
 	// Inisialisasi lapisan layanan (service)
+	webhookService := service.NewWebhookService(webhookStore, cfg.OrganizationID)
 	authService := service.NewAuthService(userStore, jwtKey)
+	subscriptionService := service.NewSubscriptionService(subscriptionStore, defaultLocation)
+	receivableService := service.NewReceivableService(paymentStore, reminderStore, mail, company, cfg.ReminderSchedule)
+	gatewayService := service.NewGatewayService(gatewayEventStore, []byte(cfg.GatewayWebhookSecret), cfg.GatewayWebhookTolerance)
+	preferenceService := service.NewPreferenceService(userStore, defaultLocation)
+	dashboardService := service.NewDashboardService(paymentStore, cfg.DashboardCacheTTL)
+	liveService := service.NewLiveService(paymentListener, dashboardService, cfg.OrganizationID, cfg.LiveHeartbeatInterval)
+	alertService := service.NewAlertService(alertStore, paymentStore, mail, cfg.AlertEmailRecipients, cfg.OrganizationID, defaultLocation)
+	reportLock := postgres.NewAdvisoryLock(dbpool, postgres.ReportSchedulerLockKey)
+	reportService := service.NewReportService(reportStore, paymentStore, preferenceService, reportLock, mail, company)
+	reconciliationService := service.NewReconciliationService(reconciliationStore, cfg.OrganizationID, cfg.ReconciliationDateWindow)
+
+	// Event ditulis ke outbox oleh store lalu diterbitkan relay ke sink yang dipilih.
+	// Cache dashboard selalu menjadi sink agar dikosongkan saat pembayaran berubah.
+	sinks := []events.Sink{dashboardService}
+	for _, name := range cfg.OutboxSinks {
+		switch name {
+		case "webhook":
+			sinks = append(sinks, webhookService)
+		case "log":
+			sinks = append(sinks, events.LogSink{})
+		case "nats":
+			natsSink := events.NewNATSSink(cfg.NATSURL, cfg.NATSSubjectPrefix)
+			defer natsSink.Close()
+			sinks = append(sinks, natsSink)
+		default:
+			log.Fatal().Msgf("Sink outbox %q tidak dikenali", name)
+		}
+	}
+	outboxRelay := service.NewOutboxRelay(outboxStore, sinks...)
+
+	// Jalankan pekerja latar belakang; semuanya berhenti saat server dimatikan.
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	go subscriptionService.RunScheduler(workerCtx, cfg.SubscriptionSchedulerInterval)
+	go receivableService.RunJobs(workerCtx, cfg.ReceivableJobInterval)
+	go webhookService.RunDeliveryWorker(workerCtx, cfg.WebhookDeliveryInterval)
+	go outboxRelay.Run(workerCtx, cfg.OutboxRelayInterval)
+	go liveService.Run(workerCtx)
+	go alertService.RunDetector(workerCtx, cfg.AlertJobInterval)
+	go reportService.RunScheduler(workerCtx, cfg.ReportSchedulerInterval)
 
 	// Suntikkan service ke dalam handler, bukan store langsung
 	authHandler := handler.NewAuthHandler(authService, jwtKey)
 	paymentHandler := handler.NewPaymentHandler(paymentStore)
-	dashboardHandler := handler.NewDashboardHandler(paymentStore)
+	dashboardHandler := handler.NewDashboardHandler(dashboardService, preferenceService)
+	invoiceHandler := handler.NewInvoiceHandler(paymentStore, invoiceStore, cfg.OrganizationID, company)
+	customerHandler := handler.NewCustomerHandler(customerStore, paymentStore)
+	subscriptionHandler := handler.NewSubscriptionHandler(subscriptionService, preferenceService)
+	receivableHandler := handler.NewReceivableHandler(receivableService)
+	gatewayHandler := handler.NewGatewayHandler(gatewayService)
+	webhookHandler := handler.NewWebhookHandler(webhookService)
+	reconciliationHandler := handler.NewReconciliationHandler(reconciliationService)
+	preferenceHandler := handler.NewPreferenceHandler(preferenceService)
+	liveHandler := handler.NewLiveHandler(liveService)
+	alertHandler := handler.NewAlertHandler(alertService)
+	reportHandler := handler.NewReportHandler(reportService)
 
 	// Buat router dengan handler yang sudah diinisialisasi
-	r := router.NewRouter(authHandler, paymentHandler, dashboardHandler)
+	r := router.NewRouter(router.Handlers{
+		Auth:           authHandler,
+		Payment:        paymentHandler,
+		Dashboard:      dashboardHandler,
+		Invoice:        invoiceHandler,
+		Customer:       customerHandler,
+		Subscription:   subscriptionHandler,
+		Receivable:     receivableHandler,
+		Gateway:        gatewayHandler,
+		Webhook:        webhookHandler,
+		Reconciliation: reconciliationHandler,
+		Preference:     preferenceHandler,
+		Live:           liveHandler,
+		Alert:          alertHandler,
+		Report:         reportHandler,
+	})
+
+	serve(addr, cfg.MetricsAddress, r, stopWorkers)
+}
 
+// serve menjalankan server HTTP sampai menerima SIGINT atau SIGTERM, lalu
+// memanggil stopWorkers dan mematikan server secara graceful. Bila metricsAddr
+// tidak kosong, /metrics disajikan di listener admin terpisah pada alamat itu.
+func serve(addr, metricsAddr string, h http.Handler, stopWorkers func()) {
 	srv := &http.Server{
 		Addr:    addr,
-		Handler: r,
+		Handler: h,
 	}
 
 	// Jalankan server dalam goroutine agar tidak memblokir
@@ -67,11 +221,25 @@ func main() {
 		}
 	}()
 
+	var adminSrv *http.Server
+	if metricsAddr != "" {
+		adminMux := http.NewServeMux()
+		adminMux.Handle("GET /metrics", metrics.Handler())
+		adminSrv = &http.Server{Addr: metricsAddr, Handler: adminMux}
+		go func() {
+			log.Info().Msgf("Metrik Prometheus tersedia di http://localhost%s/metrics", metricsAddr)
+			if err := adminSrv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Fatal().Err(err).Msg("Listener admin gagal memulai")
+			}
+		}()
+	}
+
 	// Menunggu sinyal interupsi untuk mematikan server secara graceful
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 	log.Info().Msg("Server sedang dimatikan...")
+	stopWorkers()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -79,6 +247,11 @@ func main() {
 	if err := srv.Shutdown(ctx); err != nil {
 		log.Fatal().Err(err).Msg("Server gagal dimatikan secara graceful")
 	}
+	if adminSrv != nil {
+		if err := adminSrv.Shutdown(ctx); err != nil {
+			log.Error().Err(err).Msg("Listener admin gagal dimatikan secara graceful")
+		}
+	}
 
 	log.Info().Msg("Server berhasil dimatikan.")
-}
\ No newline at end of file
+}
@@ -0,0 +1,225 @@
+// Command loginctl adalah alat administrasi untuk operator: mengelola akun
+// pengguna, mengisi data contoh, dan menjalankan migrasi tanpa SQL manual.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"login-api/internal/cli"
+	"login-api/internal/config"
+	"login-api/internal/logging"
+	"login-api/internal/seed"
+	"login-api/internal/service"
+	"login-api/internal/storage/postgres"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+const usage = `penggunaan: loginctl <perintah> [argumen]
+
+Perintah:
+  user list
+  user create -email EMAIL [-password SANDI] [-role PERAN]
+  user reset-password -email EMAIL [-password SANDI]
+  user set-role -email EMAIL -role PERAN
+  user disable -email EMAIL
+  user enable -email EMAIL
+  user revoke-sessions -email EMAIL
+  seed [-customers N] [-payments N] [-users N] [-months N] [-seed N] [-end YYYY-MM-DD]
+  migrate [up | down [jumlah] | status | baseline <versi>]
+
+Kata sandi yang tidak diisi dibuat acak dan ditampilkan sekali.
+Data contoh dengan seed dan tanggal akhir yang sama selalu identik, dan mengisi
+ulang dengan opsi yang sama tidak menambah data.
+DATABASE_URL dan DEFAULT_TIMEZONE dibaca dari environment atau file .env.
+`
+
+// errUsage menandakan argumen tidak sesuai; pesan penggunaan sudah ditampilkan.
+var errUsage = errors.New("argumen tidak sesuai")
+
+func main() {
+	logging.Setup(logging.FormatConsole, zerolog.InfoLevel)
+
+	args := os.Args[1:]
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	ctx := context.Background()
+	dbpool, err := pgxpool.New(ctx, config.DatabaseURL())
+	if err != nil {
+		log.Fatal().Err(err).Msg("Tidak dapat membuat koneksi pool")
+	}
+	defer dbpool.Close()
+
+	if err := run(ctx, dbpool, args, os.Stdout); err != nil {
+		if errors.Is(err, errUsage) {
+			fmt.Fprint(os.Stderr, usage)
+			dbpool.Close()
+			os.Exit(2)
+		}
+		fmt.Fprintln(os.Stderr, "Gagal:", err)
+		dbpool.Close()
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, db *pgxpool.Pool, args []string, out io.Writer) error {
+	switch args[0] {
+	case "user":
+		if len(args) < 2 {
+			return errUsage
+		}
+		return runUser(ctx, db, args[1], args[2:], out)
+	case "seed":
+		return runSeed(ctx, db, args[1:], out)
+	case "migrate":
+		return cli.Migrate(ctx, db, args[1:], out)
+	default:
+		return errUsage
+	}
+}
+
+func runUser(ctx context.Context, db *pgxpool.Pool, action string, args []string, out io.Writer) error {
+	admin := service.NewUserAdminService(postgres.NewPostgresUserStore(postgres.NewDB(db, 0)))
+
+	if action == "list" {
+		list, err := admin.ListUsers(ctx)
+		if err != nil {
+			return err
+		}
+		tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "EMAIL\tPERAN\tSTATUS\tDIBUAT")
+		for _, u := range list {
+			status := "aktif"
+			if u.DisabledAt != nil {
+				status = "nonaktif sejak " + u.DisabledAt.Local().Format("2006-01-02")
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", u.Email, u.Role, status, u.CreatedAt.Local().Format("2006-01-02"))
+		}
+		return tw.Flush()
+	}
+
+	fs := flag.NewFlagSet("user "+action, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	email := fs.String("email", "", "email pengguna")
+	password := fs.String("password", "", "kata sandi")
+	role := fs.String("role", "", "peran pengguna")
+	if err := fs.Parse(args); err != nil || *email == "" {
+		return errUsage
+	}
+
+	switch action {
+	case "create":
+		generated, err := admin.CreateUser(ctx, *email, *password, *role)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Pengguna %s dibuat.\n", *email)
+		printGeneratedPassword(out, *password, generated)
+	case "reset-password":
+		generated, err := admin.ResetPassword(ctx, *email, *password)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Kata sandi %s direset dan semua sesinya dicabut.\n", *email)
+		printGeneratedPassword(out, *password, generated)
+	case "set-role":
+		if *role == "" {
+			return errUsage
+		}
+		u, err := admin.SetRole(ctx, *email, *role)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Peran %s sekarang %s. Peran baru berlaku saat token diperbarui.\n", u.Email, u.Role)
+	case "disable":
+		if _, err := admin.SetDisabled(ctx, *email, true); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Akun %s dinonaktifkan dan semua sesinya dicabut.\n", *email)
+	case "enable":
+		if _, err := admin.SetDisabled(ctx, *email, false); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Akun %s diaktifkan kembali.\n", *email)
+	case "revoke-sessions":
+		if _, err := admin.RevokeSessions(ctx, *email); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Semua sesi %s dicabut. Access token yang tersisa berlaku paling lama 15 menit.\n", *email)
+	default:
+		return errUsage
+	}
+	return nil
+}
+
+func printGeneratedPassword(out io.Writer, requested, generated string) {
+	if requested == "" {
+		fmt.Fprintf(out, "Kata sandi sementara: %s\n", generated)
+	}
+}
+
+func runSeed(ctx context.Context, db *pgxpool.Pool, args []string, out io.Writer) error {
+	opts := seed.DefaultOptions
+	fs := flag.NewFlagSet("seed", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.IntVar(&opts.Customers, "customers", opts.Customers, "jumlah pelanggan")
+	fs.IntVar(&opts.Payments, "payments", opts.Payments, "jumlah pembayaran")
+	fs.IntVar(&opts.Users, "users", opts.Users, "jumlah pengguna")
+	fs.IntVar(&opts.Months, "months", opts.Months, "rentang bulan ke belakang")
+	fs.Int64Var(&opts.Seed, "seed", opts.Seed, "seed pembangkit acak")
+	end := fs.String("end", "", "tanggal akhir sebaran pembayaran (bawaan: besok)")
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+	if opts.Customers < 0 || opts.Payments < 0 || opts.Users < 0 || opts.Months <= 0 {
+		return errors.New("jumlah data tidak boleh negatif dan months harus positif")
+	}
+	if opts.Payments > 0 && opts.Customers == 0 {
+		return errors.New("pembayaran contoh membutuhkan minimal satu pelanggan")
+	}
+
+	// Tanggal mengikuti DEFAULT_TIMEZONE seperti data contoh mode demo, bukan
+	// zona waktu mesin yang menjalankan loginctl.
+	loc, err := service.LoadTimezone(config.DefaultTimezone())
+	if err != nil {
+		return fmt.Errorf("DEFAULT_TIMEZONE tidak valid: %w", err)
+	}
+	// Bawaan besok agar pembayaran hari ini ikut terisi.
+	opts.End = time.Now().In(loc).AddDate(0, 0, 1)
+	if *end != "" {
+		t, err := time.ParseInLocation("2006-01-02", *end, loc)
+		if err != nil {
+			return errors.New("format -end harus YYYY-MM-DD")
+		}
+		opts.End = t
+	}
+	opts.End = time.Date(opts.End.Year(), opts.End.Month(), opts.End.Day(), 0, 0, 0, 0, loc)
+
+	res, err := seed.Run(ctx, db, opts)
+	if err != nil {
+		return err
+	}
+	if res.Skipped {
+		fmt.Fprintln(out, "Data contoh dengan opsi yang sama sudah pernah diisi; tidak ada yang ditambahkan.")
+		return nil
+	}
+	if res.Replaced > 0 {
+		fmt.Fprintf(out, "%d pembayaran contoh dari pengisian sebelumnya diganti.\n", res.Replaced)
+	}
+	fmt.Fprintf(out, "Data contoh ditambahkan: %d pelanggan, %d pembayaran, %d pengguna.\n", res.Customers, res.Payments, res.Users)
+	if res.Users > 0 {
+		fmt.Fprintf(out, "Pengguna contoh (admin@demo.local, user1@demo.local, ...) memakai kata sandi %s\n", seed.DemoPassword)
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+// Package anomaly mendeteksi pola aktivitas pembayaran yang tidak biasa
+// dibandingkan dengan baseline historisnya.
+package anomaly
+
+import (
+	"fmt"
+	"login-api/internal/model"
+	"math"
+)
+
+// Thresholds mengatur kepekaan deteksi. Deviasi dinyatakan dalam simpangan baku
+// baseline; deviasi dua kali ambang dianggap kritis.
+type Thresholds struct {
+	// Sigma adalah deviasi minimum pendapatan harian atau jumlah pembayaran belum
+	// lunas agar dianggap anomali.
+	Sigma float64
+	// MinBaselineDays adalah jumlah hari baseline minimum sebelum deteksi harian berjalan.
+	MinBaselineDays int
+	// MinUnsettled adalah jumlah pembayaran belum lunas minimum agar lonjakan dilaporkan.
+	MinUnsettled int64
+	// LargePaymentSigma adalah deviasi minimum nominal satu pembayaran.
+	LargePaymentSigma float64
+	// MinSamples adalah jumlah pembayaran baseline minimum untuk deteksi nominal besar.
+	MinSamples int64
+}
+
+// DefaultThresholds adalah ambang yang cukup konservatif agar peringatan jarang
+// berbunyi tanpa alasan.
+var DefaultThresholds = Thresholds{Sigma: 3, MinBaselineDays: 14, MinUnsettled: 5, LargePaymentSigma: 4, MinSamples: 20}
+
+// Finding adalah satu anomali yang ditemukan.
+type Finding struct {
+	Kind        string
+	Severity    string
+	Fingerprint string
+	Message     string
+	Details     map[string]interface{}
+}
+
+// RevenueDrop melaporkan bila pendapatan latest jauh di bawah rata-rata baseline
+// dan kurang dari separuhnya.
+func RevenueDrop(baseline []model.DailyActivity, latest model.DailyActivity, t Thresholds) *Finding {
+	if len(baseline) == 0 || len(baseline) < t.MinBaselineDays {
+		return nil
+	}
+	values := make([]float64, len(baseline))
+	for i, d := range baseline {
+		values[i] = d.Revenue
+	}
+	mean, std := meanStd(values)
+	if mean <= 0 || latest.Revenue >= mean/2 {
+		return nil
+	}
+	z := deviation(mean-latest.Revenue, std)
+	if z < t.Sigma {
+		return nil
+	}
+
+	day := latest.Date.Format("2006-01-02")
+	return &Finding{
+		Kind:        model.AlertRevenueDrop,
+		Severity:    severity(z, t.Sigma),
+		Fingerprint: model.AlertRevenueDrop + ":" + day,
+		Message: fmt.Sprintf("Pendapatan %s sebesar %.0f, turun %.0f%% dari rata-rata %d hari sebelumnya (%.0f).",
+			day, latest.Revenue, (1-latest.Revenue/mean)*100, len(baseline), mean),
+		Details: map[string]interface{}{
+			"date": day, "revenue": latest.Revenue, "baseline_mean": round2(mean), "baseline_stddev": round2(std), "baseline_days": len(baseline),
+		},
+	}
+}
+
+// UnsettledSpike melaporkan lonjakan jumlah pembayaran yang tertunda, terlambat,
+// atau dibatalkan pada latest dibandingkan baseline.
+func UnsettledSpike(baseline []model.DailyActivity, latest model.DailyActivity, t Thresholds) *Finding {
+	if len(baseline) == 0 || len(baseline) < t.MinBaselineDays || latest.Unsettled < t.MinUnsettled {
+		return nil
+	}
+	values := make([]float64, len(baseline))
+	for i, d := range baseline {
+		values[i] = float64(d.Unsettled)
+	}
+	mean, std := meanStd(values)
+	count := float64(latest.Unsettled)
+	if count < 2*mean {
+		return nil
+	}
+	z := deviation(count-mean, std)
+	if z < t.Sigma {
+		return nil
+	}
+
+	day := latest.Date.Format("2006-01-02")
+	return &Finding{
+		Kind:        model.AlertUnsettledSpike,
+		Severity:    severity(z, t.Sigma),
+		Fingerprint: model.AlertUnsettledSpike + ":" + day,
+		Message: fmt.Sprintf("%d pembayaran tertunda, terlambat, atau dibatalkan pada %s, dibandingkan rata-rata %.1f per hari.",
+			latest.Unsettled, day, mean),
+		Details: map[string]interface{}{
+			"date": day, "count": latest.Unsettled, "baseline_mean": round2(mean), "baseline_stddev": round2(std), "baseline_days": len(baseline),
+		},
+	}
+}
+
+// LargePayments melaporkan setiap pembayaran yang nominalnya jauh di atas
+// rata-rata baseline.
+func LargePayments(stats model.AmountStats, recent []model.Payment, t Thresholds) []Finding {
+	if stats.Count < t.MinSamples || stats.Mean <= 0 {
+		return nil
+	}
+
+	var findings []Finding
+	for _, p := range recent {
+		z := deviation(p.Amount-stats.Mean, stats.StdDev)
+		if p.Amount <= stats.Mean || z < t.LargePaymentSigma {
+			continue
+		}
+		findings = append(findings, Finding{
+			Kind:        model.AlertLargePayment,
+			Severity:    severity(z, t.LargePaymentSigma),
+			Fingerprint: fmt.Sprintf("%s:%d", model.AlertLargePayment, p.ID),
+			Message: fmt.Sprintf("Pembayaran #%d dari %s sebesar %.0f, %.1f kali rata-rata pembayaran (%.0f).",
+				p.ID, p.CustomerName, p.Amount, p.Amount/stats.Mean, stats.Mean),
+			Details: map[string]interface{}{
+				"payment_id": p.ID, "customer_name": p.CustomerName, "amount": p.Amount, "status": p.Status,
+				"baseline_mean": round2(stats.Mean), "baseline_stddev": round2(stats.StdDev),
+			},
+		})
+	}
+	return findings
+}
+
+func meanStd(values []float64) (float64, float64) {
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	mean := sum / float64(len(values))
+
+	var ss float64
+	for _, v := range values {
+		ss += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(ss / float64(len(values)))
+}
+
+// deviation menyatakan diff dalam simpangan baku. Baseline yang datar (std nol)
+// membuat setiap selisih positif dianggap sangat menyimpang.
+func deviation(diff, std float64) float64 {
+	if std == 0 {
+		if diff > 0 {
+			return math.Inf(1)
+		}
+		return 0
+	}
+	return diff / std
+}
+
+func severity(z, threshold float64) string {
+	if z >= 2*threshold {
+		return model.AlertSeverityCritical
+	}
+	return model.AlertSeverityWarning
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,179 @@
+package anomaly
+
+import (
+	"login-api/internal/model"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var latestDate = time.Date(2026, time.March, 15, 0, 0, 0, 0, time.UTC)
+
+// baseline membuat n hari aktivitas sebelum latestDate dengan nilai bergantian
+// dari values.
+func baseline(n int, revenue []float64, unsettled []int64) []model.DailyActivity {
+	days := make([]model.DailyActivity, n)
+	for i := range days {
+		days[i] = model.DailyActivity{
+			Date:      latestDate.AddDate(0, 0, i-n),
+			Revenue:   revenue[i%len(revenue)],
+			Unsettled: unsettled[i%len(unsettled)],
+		}
+	}
+	return days
+}
+
+func TestRevenueDrop(t *testing.T) {
+	// Rata-rata 1000 dengan simpangan baku 100.
+	steady := baseline(14, []float64{900, 1100}, []int64{0})
+
+	tests := []struct {
+		name       string
+		baseline   []model.DailyActivity
+		revenue    float64
+		thresholds Thresholds
+		want       string
+	}{
+		{"turun tajam", steady, 400, DefaultThresholds, model.AlertSeverityCritical},
+		{"turun melewati ambang", steady, 450, DefaultThresholds, model.AlertSeverityWarning},
+		{"tepat pada ambang", steady, 500 - 1e-9, Thresholds{Sigma: 5, MinBaselineDays: 14}, model.AlertSeverityWarning},
+		{"tidak sampai separuh rata-rata", steady, 600, DefaultThresholds, ""},
+		{"baseline terlalu bervariasi", baseline(14, []float64{200, 1800}, []int64{0}), 0, DefaultThresholds, ""},
+		{"baseline datar", baseline(14, []float64{1000}, []int64{0}), 100, DefaultThresholds, model.AlertSeverityCritical},
+		{"baseline nol", baseline(14, []float64{0}, []int64{0}), 0, DefaultThresholds, ""},
+		{"baseline terlalu pendek", steady[1:], 0, DefaultThresholds, ""},
+		{"tanpa baseline", nil, 0, Thresholds{Sigma: 3}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := RevenueDrop(tt.baseline, model.DailyActivity{Date: latestDate, Revenue: tt.revenue}, tt.thresholds)
+			switch {
+			case tt.want == "" && got != nil:
+				t.Errorf("RevenueDrop() = %+v, want nil", got)
+			case tt.want != "" && got == nil:
+				t.Errorf("RevenueDrop() = nil, want %s", tt.want)
+			case got != nil && got.Severity != tt.want:
+				t.Errorf("RevenueDrop().Severity = %s, want %s", got.Severity, tt.want)
+			}
+		})
+	}
+}
+
+func TestRevenueDropFinding(t *testing.T) {
+	got := RevenueDrop(baseline(14, []float64{900, 1100}, []int64{0}), model.DailyActivity{Date: latestDate, Revenue: 400}, DefaultThresholds)
+	want := &Finding{
+		Kind:        model.AlertRevenueDrop,
+		Severity:    model.AlertSeverityCritical,
+		Fingerprint: "revenue_drop:2026-03-15",
+		Message:     "Pendapatan 2026-03-15 sebesar 400, turun 60% dari rata-rata 14 hari sebelumnya (1000).",
+		Details: map[string]interface{}{
+			"date": "2026-03-15", "revenue": 400.0, "baseline_mean": 1000.0, "baseline_stddev": 100.0, "baseline_days": 14,
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("RevenueDrop() = %+v, want %+v", got, want)
+	}
+}
+
+func TestUnsettledSpike(t *testing.T) {
+	// Rata-rata 2 dengan simpangan baku 1.
+	steady := baseline(14, []float64{0}, []int64{1, 3})
+
+	tests := []struct {
+		name     string
+		baseline []model.DailyActivity
+		count    int64
+		want     string
+	}{
+		{"lonjakan tajam", steady, 8, model.AlertSeverityCritical},
+		{"lonjakan melewati ambang", steady, 6, model.AlertSeverityWarning},
+		{"tepat pada ambang", steady, 5, model.AlertSeverityWarning},
+		{"di bawah jumlah minimum", baseline(14, []float64{0}, []int64{0, 1}), 4, ""},
+		{"kurang dari dua kali rata-rata", baseline(14, []float64{0}, []int64{3}), 5, ""},
+		{"baseline nol", baseline(14, []float64{0}, []int64{0}), 5, model.AlertSeverityCritical},
+		{"baseline terlalu pendek", steady[1:], 8, ""},
+		{"tanpa baseline", nil, 8, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			thresholds := DefaultThresholds
+			if tt.baseline == nil {
+				thresholds.MinBaselineDays = 0
+			}
+			got := UnsettledSpike(tt.baseline, model.DailyActivity{Date: latestDate, Unsettled: tt.count}, thresholds)
+			switch {
+			case tt.want == "" && got != nil:
+				t.Errorf("UnsettledSpike() = %+v, want nil", got)
+			case tt.want != "" && got == nil:
+				t.Errorf("UnsettledSpike() = nil, want %s", tt.want)
+			case got != nil && got.Severity != tt.want:
+				t.Errorf("UnsettledSpike().Severity = %s, want %s", got.Severity, tt.want)
+			}
+		})
+	}
+
+	got := UnsettledSpike(steady, model.DailyActivity{Date: latestDate, Unsettled: 8}, DefaultThresholds)
+	if got.Fingerprint != "unsettled_spike:2026-03-15" || got.Details["count"] != int64(8) || got.Details["baseline_mean"] != 2.0 {
+		t.Errorf("UnsettledSpike() = %+v", got)
+	}
+}
+
+func TestLargePayments(t *testing.T) {
+	stats := model.AmountStats{Mean: 100, StdDev: 50, Count: 20}
+	recent := []model.Payment{
+		{ID: 1, CustomerName: "Andi", Amount: 50},
+		{ID: 2, CustomerName: "Budi", Amount: 250},
+		{ID: 3, CustomerName: "Citra", Amount: 300},
+		{ID: 4, CustomerName: "Dewi", Amount: 500},
+	}
+
+	got := LargePayments(stats, recent, DefaultThresholds)
+	if len(got) != 2 {
+		t.Fatalf("LargePayments() = %+v, want 2 temuan", got)
+	}
+	if got[0].Fingerprint != "large_payment:3" || got[0].Severity != model.AlertSeverityWarning {
+		t.Errorf("temuan pertama = %+v, want pembayaran 3 warning", got[0])
+	}
+	if got[1].Fingerprint != "large_payment:4" || got[1].Severity != model.AlertSeverityCritical {
+		t.Errorf("temuan kedua = %+v, want pembayaran 4 critical", got[1])
+	}
+	if want := "Pembayaran #3 dari Citra sebesar 300, 3.0 kali rata-rata pembayaran (100)."; got[0].Message != want {
+		t.Errorf("Message = %q, want %q", got[0].Message, want)
+	}
+
+	tests := []struct {
+		name  string
+		stats model.AmountStats
+		want  int
+	}{
+		{"sampel terlalu sedikit", model.AmountStats{Mean: 100, StdDev: 50, Count: 19}, 0},
+		{"rata-rata nol", model.AmountStats{Count: 20}, 0},
+		// Simpangan baku nol: setiap pembayaran di atas rata-rata menyimpang.
+		{"baseline datar", model.AmountStats{Mean: 50, Count: 20}, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := LargePayments(tt.stats, recent, DefaultThresholds); len(got) != tt.want {
+				t.Errorf("LargePayments() = %+v, want %d temuan", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeviation(t *testing.T) {
+	tests := []struct {
+		diff, std, want float64
+	}{
+		{300, 100, 3},
+		{-300, 100, -3},
+		{1, 0, math.Inf(1)},
+		{0, 0, 0},
+		{-1, 0, 0},
+	}
+	for _, tt := range tests {
+		if got := deviation(tt.diff, tt.std); got != tt.want {
+			t.Errorf("deviation(%v, %v) = %v, want %v", tt.diff, tt.std, got, tt.want)
+		}
+	}
+}
@@ -7,13 +7,18 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
-// GenerateTokens membuat access token dan refresh token.
-func GenerateTokens(email string, jwtKey []byte) (string, string, error) {
+// GenerateTokens membuat access token dan refresh token. Waktu terbit dicatat
+// agar refresh token dapat dicabut lewat sessions_revoked_at pengguna; seperti
+// semua NumericDate JWT, presisinya hanya sampai detik.
+func GenerateTokens(email, role string, jwtKey []byte) (string, string, error) {
+	issuedAt := jwt.NewNumericDate(time.Now())
 	accessTokenExpirationTime := time.Now().Add(15 * time.Minute)
 	accessClaims := &model.Claims{
 		Email: email,
+		Role:  role,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(accessTokenExpirationTime),
+			IssuedAt:  issuedAt,
 		},
 	}
 	accessToken := jwt.NewWithClaims(jwt.SigningMethodHS256, accessClaims)
@@ -25,8 +30,10 @@ func GenerateTokens(email string, jwtKey []byte) (string, string, error) {
 	refreshTokenExpirationTime := time.Now().Add(24 * 7 * time.Hour)
 	refreshClaims := &model.Claims{
 		Email: email,
+		Role:  role,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(refreshTokenExpirationTime),
+			IssuedAt:  issuedAt,
 		},
 	}
 	refreshToken := jwt.NewWithClaims(jwt.SigningMethodHS256, refreshClaims)
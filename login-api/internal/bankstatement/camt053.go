@@ -0,0 +1,111 @@
+package bankstatement
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// camtDocument memetakan bagian dokumen ISO 20022 camt.053 yang dibutuhkan.
+// Namespace diabaikan agar versi 001.02 hingga 001.08 sama-sama terbaca.
+type camtDocument struct {
+	Statements []struct {
+		Account struct {
+			IBAN  string `xml:"Id>IBAN"`
+			Other string `xml:"Id>Othr>Id"`
+		} `xml:"Acct"`
+		Entries []struct {
+			Amount          string `xml:"Amt"`
+			CreditDebit     string `xml:"CdtDbtInd"`
+			Reversal        bool   `xml:"RvslInd"`
+			BookingDate     string `xml:"BookgDt>Dt"`
+			BookingDateTime string `xml:"BookgDt>DtTm"`
+			ValueDate       string `xml:"ValDt>Dt"`
+			ServicerRef     string `xml:"AcctSvcrRef"`
+			AdditionalInfo  string `xml:"AddtlNtryInf"`
+			Transactions    []struct {
+				EndToEndID   string   `xml:"Refs>EndToEndId"`
+				Unstructured []string `xml:"RmtInf>Ustrd"`
+				Structured   string   `xml:"RmtInf>Strd>CdtrRefInf>Ref"`
+			} `xml:"NtryDtls>TxDtls"`
+		} `xml:"Ntry"`
+	} `xml:"BkToCstmrStmt>Stmt"`
+}
+
+// parseCAMT053 membaca mutasi ISO 20022 camt.053. Satu entri menjadi satu baris;
+// referensi diambil dari referensi terstruktur, lalu EndToEndId, lalu referensi bank.
+func parseCAMT053(data []byte) (Statement, error) {
+	var doc camtDocument
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return Statement{}, fmt.Errorf("XML camt.053 tidak valid: %w", err)
+	}
+	if len(doc.Statements) == 0 {
+		return Statement{}, errors.New("camt.053 tidak berisi elemen Stmt")
+	}
+
+	var stmt Statement
+	for _, s := range doc.Statements {
+		if stmt.AccountID == "" {
+			stmt.AccountID = firstNonEmpty(s.Account.IBAN, s.Account.Other)
+		}
+
+		for i, e := range s.Entries {
+			date, err := parseDate(firstNonEmpty(e.BookingDate, e.BookingDateTime, e.ValueDate))
+			if err != nil {
+				return Statement{}, fmt.Errorf("entri %d: %w", i+1, err)
+			}
+			amount, err := parseAmount(e.Amount)
+			if err != nil {
+				return Statement{}, fmt.Errorf("entri %d: %w", i+1, err)
+			}
+			debit := e.CreditDebit == "DBIT"
+			if e.Reversal {
+				debit = !debit
+			}
+			if debit {
+				amount = -amount
+			}
+
+			var reference string
+			var description []string
+			for _, tx := range e.Transactions {
+				if reference == "" {
+					reference = firstNonEmpty(tx.Structured, notNotProvided(tx.EndToEndID))
+				}
+				description = append(description, tx.Unstructured...)
+			}
+			if reference == "" {
+				reference = e.ServicerRef
+			}
+			if len(description) == 0 && e.AdditionalInfo != "" {
+				description = append(description, e.AdditionalInfo)
+			}
+
+			stmt.Lines = append(stmt.Lines, Line{
+				BookingDate: date,
+				Amount:      amount,
+				Reference:   strings.TrimSpace(reference),
+				Description: strings.TrimSpace(strings.Join(description, " ")),
+			})
+		}
+	}
+	return stmt, nil
+}
+
+// notNotProvided mengosongkan nilai "NOTPROVIDED" yang lazim diisi bank pada EndToEndId.
+func notNotProvided(s string) string {
+	if strings.EqualFold(strings.TrimSpace(s), "NOTPROVIDED") {
+		return ""
+	}
+	return s
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,86 @@
+package bankstatement
+
+import "testing"
+
+func TestParseCAMT053(t *testing.T) {
+	data := `<?xml version="1.0" encoding="UTF-8"?>
+<Document xmlns="urn:iso:std:iso:20022:tech:xsd:camt.053.001.02">
+  <BkToCstmrStmt>
+    <Stmt>
+      <Acct><Id><IBAN>ID12BANK000123</IBAN></Id></Acct>
+      <Ntry>
+        <Amt Ccy="IDR">1500000.00</Amt>
+        <CdtDbtInd>CRDT</CdtDbtInd>
+        <BookgDt><Dt>2025-03-05</Dt></BookgDt>
+        <AcctSvcrRef>BANKREF1</AcctSvcrRef>
+        <NtryDtls>
+          <TxDtls>
+            <Refs><EndToEndId>NOTPROVIDED</EndToEndId></Refs>
+            <RmtInf>
+              <Ustrd>Pembayaran</Ustrd>
+              <Ustrd>INV/2025/000042</Ustrd>
+              <Strd><CdtrRefInf><Ref>RF18INV42</Ref></CdtrRefInf></Strd>
+            </RmtInf>
+          </TxDtls>
+        </NtryDtls>
+      </Ntry>
+      <Ntry>
+        <Amt Ccy="IDR">6500.00</Amt>
+        <CdtDbtInd>DBIT</CdtDbtInd>
+        <BookgDt><DtTm>2025-03-06T10:15:00</DtTm></BookgDt>
+        <AcctSvcrRef>BANKREF2</AcctSvcrRef>
+        <AddtlNtryInf>Biaya admin</AddtlNtryInf>
+        <NtryDtls>
+          <TxDtls><Refs><EndToEndId>E2E-77</EndToEndId></Refs></TxDtls>
+        </NtryDtls>
+      </Ntry>
+      <Ntry>
+        <Amt Ccy="IDR">250</Amt>
+        <CdtDbtInd>CRDT</CdtDbtInd>
+        <RvslInd>true</RvslInd>
+        <ValDt><Dt>2025-03-07</Dt></ValDt>
+        <AcctSvcrRef>BANKREF3</AcctSvcrRef>
+      </Ntry>
+    </Stmt>
+    <Stmt>
+      <Acct><Id><Othr><Id>9999</Id></Othr></Id></Acct>
+      <Ntry>
+        <Amt Ccy="IDR">1.25</Amt>
+        <CdtDbtInd>DBIT</CdtDbtInd>
+        <RvslInd>true</RvslInd>
+        <BookgDt><Dt>2025-03-08</Dt></BookgDt>
+      </Ntry>
+    </Stmt>
+  </BkToCstmrStmt>
+</Document>`
+
+	assertStatement(t, data, Statement{
+		Format:    FormatCAMT053,
+		AccountID: "ID12BANK000123",
+		Lines: []Line{
+			{BookingDate: date(2025, 3, 5), Amount: 1500000, Reference: "RF18INV42", Description: "Pembayaran INV/2025/000042"},
+			{BookingDate: date(2025, 3, 6), Amount: -6500, Reference: "E2E-77", Description: "Biaya admin"},
+			{BookingDate: date(2025, 3, 7), Amount: -250, Reference: "BANKREF3"},
+			{BookingDate: date(2025, 3, 8), Amount: 1.25},
+		},
+	})
+}
+
+func TestParseCAMT053Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"xml rusak", `<Document><BkToCstmrStmt><Stmt>`},
+		{"tanpa Stmt", `<Document><BkToCstmrStmt></BkToCstmrStmt></Document>`},
+		{"tanpa Ntry", `<Document><BkToCstmrStmt><Stmt></Stmt></BkToCstmrStmt></Document>`},
+		{"nominal tidak valid", `<Document><BkToCstmrStmt><Stmt><Ntry><Amt>abc</Amt><BookgDt><Dt>2025-03-05</Dt></BookgDt></Ntry></Stmt></BkToCstmrStmt></Document>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse([]byte(tt.data), FormatCAMT053); err == nil {
+				t.Error("Parse() error = nil, want error")
+			}
+		})
+	}
+}
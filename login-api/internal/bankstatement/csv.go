@@ -0,0 +1,127 @@
+package bankstatement
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Nama kolom CSV yang dikenali, dalam huruf kecil. Ekspor internet banking
+// berbeda-beda, jadi beberapa sinonim diterima.
+var (
+	csvDateColumns        = []string{"date", "tanggal", "tgl", "booking date", "tanggal transaksi", "value date"}
+	csvAmountColumns      = []string{"amount", "jumlah", "nominal", "mutasi"}
+	csvCreditColumns      = []string{"credit", "kredit", "cr"}
+	csvDebitColumns       = []string{"debit", "debet", "db"}
+	csvReferenceColumns   = []string{"reference", "referensi", "ref", "no. referensi", "berita"}
+	csvDescriptionColumns = []string{"description", "keterangan", "deskripsi", "uraian", "remark"}
+)
+
+// parseCSV membaca CSV dengan baris judul. Nominal diambil dari satu kolom
+// bertanda atau dari pasangan kolom kredit/debit. Pemisah koma dan titik koma
+// sama-sama diterima.
+func parseCSV(data []byte) (Statement, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+	if firstLine, _, _ := bytes.Cut(data, []byte("\n")); bytes.Count(firstLine, []byte(";")) > bytes.Count(firstLine, []byte(",")) {
+		r.Comma = ';'
+	}
+
+	records, err := r.ReadAll()
+	if err != nil {
+		return Statement{}, fmt.Errorf("CSV tidak valid: %w", err)
+	}
+	if len(records) < 2 {
+		return Statement{}, errors.New("CSV harus memiliki baris judul dan minimal satu transaksi")
+	}
+
+	header := records[0]
+	dateCol := findColumn(header, csvDateColumns)
+	amountCol := findColumn(header, csvAmountColumns)
+	creditCol := findColumn(header, csvCreditColumns)
+	debitCol := findColumn(header, csvDebitColumns)
+	refCol := findColumn(header, csvReferenceColumns)
+	descCol := findColumn(header, csvDescriptionColumns)
+
+	if dateCol < 0 {
+		return Statement{}, errors.New("CSV tidak memiliki kolom tanggal")
+	}
+	if amountCol < 0 && creditCol < 0 {
+		return Statement{}, errors.New("CSV tidak memiliki kolom jumlah atau kredit")
+	}
+
+	var stmt Statement
+	for i, rec := range records[1:] {
+		if isBlank(rec) {
+			continue
+		}
+		rowNo := i + 2
+
+		date, err := parseDate(field(rec, dateCol))
+		if err != nil {
+			return Statement{}, fmt.Errorf("baris %d: %w", rowNo, err)
+		}
+
+		var amount float64
+		if amountCol >= 0 {
+			if amount, err = parseAmount(field(rec, amountCol)); err != nil {
+				return Statement{}, fmt.Errorf("baris %d: %w", rowNo, err)
+			}
+		} else {
+			// Kolom yang tidak dipakai sering berisi "0,00" alih-alih kosong,
+			// jadi kredit baru dipakai bila nilainya bukan nol.
+			if credit := strings.TrimSpace(field(rec, creditCol)); credit != "" {
+				if amount, err = parseAmount(credit); err != nil {
+					return Statement{}, fmt.Errorf("baris %d: %w", rowNo, err)
+				}
+			}
+			if debit := strings.TrimSpace(field(rec, debitCol)); amount == 0 && debit != "" {
+				if amount, err = parseAmount(debit); err != nil {
+					return Statement{}, fmt.Errorf("baris %d: %w", rowNo, err)
+				}
+				amount = -amount
+			}
+		}
+
+		stmt.Lines = append(stmt.Lines, Line{
+			BookingDate: date,
+			Amount:      amount,
+			Reference:   strings.TrimSpace(field(rec, refCol)),
+			Description: strings.TrimSpace(field(rec, descCol)),
+		})
+	}
+	return stmt, nil
+}
+
+func findColumn(header []string, names []string) int {
+	for i, h := range header {
+		h = strings.ToLower(strings.TrimSpace(h))
+		for _, name := range names {
+			if h == name {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func field(rec []string, col int) string {
+	if col < 0 || col >= len(rec) {
+		return ""
+	}
+	return rec[col]
+}
+
+func isBlank(rec []string) bool {
+	for _, f := range rec {
+		if strings.TrimSpace(f) != "" {
+			return false
+		}
+	}
+	return true
+}
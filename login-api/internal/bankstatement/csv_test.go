@@ -0,0 +1,78 @@
+package bankstatement
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseCSV(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want []Line
+	}{
+		{
+			name: "kolom jumlah bertanda dengan titik koma",
+			data: "Tanggal;Keterangan;Jumlah;Referensi\n" +
+				"05/03/2025;Transfer dari PT Maju;1.500.000,00;INV/2025/000042\n" +
+				"06/03/2025;Biaya admin;-6.500,00;\n" +
+				";;;\n",
+			want: []Line{
+				{BookingDate: date(2025, 3, 5), Amount: 1500000, Reference: "INV/2025/000042", Description: "Transfer dari PT Maju"},
+				{BookingDate: date(2025, 3, 6), Amount: -6500, Description: "Biaya admin"},
+			},
+		},
+		{
+			// Kolom yang tidak dipakai berisi nol, bukan kosong.
+			name: "kolom debit dan kredit berisi nol",
+			data: "Tanggal;Uraian;Debet;Kredit\n" +
+				"2025-03-05;Transfer masuk;0,00;250.000,00\n" +
+				"2025-03-06;Bayar vendor;75.000,00;0,00\n" +
+				"2025-03-07;Tarik tunai;50.000;0.00\n" +
+				"2025-03-08;Setoran; 0 ;1.000\n",
+			want: []Line{
+				{BookingDate: date(2025, 3, 5), Amount: 250000, Description: "Transfer masuk"},
+				{BookingDate: date(2025, 3, 6), Amount: -75000, Description: "Bayar vendor"},
+				{BookingDate: date(2025, 3, 7), Amount: -50000, Description: "Tarik tunai"},
+				{BookingDate: date(2025, 3, 8), Amount: 1000, Description: "Setoran"},
+			},
+		},
+		{
+			name: "kolom debit dan kredit kosong dengan koma dan BOM",
+			data: "\xef\xbb\xbfDate,Description,Debit,Credit,Ref\n" +
+				"2025-03-05 08:15,Incoming transfer,,\"1,250.50\",PAY-12\n" +
+				"2025-03-06,Card payment,99.90,,\n",
+			want: []Line{
+				{BookingDate: date(2025, 3, 5), Amount: 1250.5, Reference: "PAY-12", Description: "Incoming transfer"},
+				{BookingDate: date(2025, 3, 6), Amount: -99.9, Description: "Card payment"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertStatement(t, tt.data, Statement{Format: FormatCSV, Lines: tt.want})
+		})
+	}
+}
+
+func TestParseCSVInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{"tanpa transaksi", "Tanggal;Jumlah\n", "minimal satu transaksi"},
+		{"tanpa kolom tanggal", "Keterangan;Jumlah\nA;1\n", "kolom tanggal"},
+		{"tanpa kolom nominal", "Tanggal;Keterangan\n2025-03-05;A\n", "kolom jumlah atau kredit"},
+		{"tanggal tidak valid", "Tanggal;Jumlah\n2025-03-05;1\n31/31/2025;1\n", "baris 3"},
+		{"kredit tidak valid", "Tanggal;Debet;Kredit\n2025-03-05;;abc\n", "baris 2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.data), FormatCSV)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Parse() error = %v, want mengandung %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package bankstatement
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Candidate adalah pembayaran yang nominal dan tanggalnya sesuai dengan satu
+// baris mutasi. InvoiceNumber kosong bila pembayaran belum memiliki faktur.
+type Candidate struct {
+	PaymentID     int
+	PaymentDate   time.Time
+	CustomerName  string
+	InvoiceNumber string
+}
+
+// Decision adalah hasil pencocokan otomatis satu baris mutasi.
+type Decision struct {
+	// Match diisi bila tepat satu pembayaran dapat dipastikan cocok.
+	Match *Candidate
+	// Review berisi kandidat yang perlu dipilih manual bila pencocokan ambigu.
+	Review []Candidate
+}
+
+// Decide memilih pembayaran untuk line dari kandidat yang nominalnya sama dan
+// tanggalnya masih dalam jendela pencocokan:
+//
+//  1. kandidat yang nomor faktur atau ID pembayarannya disebut pada referensi
+//     atau deskripsi mutasi diutamakan; bila tepat satu, langsung dicocokkan;
+//  2. tanpa kecocokan referensi, satu-satunya kandidat dicocokkan;
+//  3. selebihnya dimasukkan ke antrean tinjauan.
+func Decide(line Line, candidates []Candidate) Decision {
+	if len(candidates) == 0 {
+		return Decision{}
+	}
+
+	text := strings.ToUpper(line.Reference + " " + line.Description)
+	var byReference []Candidate
+	for _, c := range candidates {
+		if mentionsCandidate(text, c) {
+			byReference = append(byReference, c)
+		}
+	}
+
+	switch {
+	case len(byReference) == 1:
+		return Decision{Match: &byReference[0]}
+	case len(byReference) > 1:
+		return Decision{Review: byReference}
+	case len(candidates) == 1:
+		return Decision{Match: &candidates[0]}
+	default:
+		return Decision{Review: candidates}
+	}
+}
+
+// AmountsEqual membandingkan nominal hingga ketelitian sen.
+func AmountsEqual(a, b float64) bool {
+	return math.Abs(a-b) < 0.005
+}
+
+var nonAlphanumeric = regexp.MustCompile(`[^A-Z0-9]+`)
+
+// mentionsCandidate melaporkan apakah text menyebut nomor faktur kandidat
+// (pemisah diabaikan, karena bank sering menghapus "/" dari berita transfer)
+// atau ID pembayarannya dalam bentuk "#123" atau "PAY123".
+func mentionsCandidate(text string, c Candidate) bool {
+	if c.InvoiceNumber != "" {
+		invoice := strings.ToUpper(c.InvoiceNumber)
+		if strings.Contains(text, invoice) {
+			return true
+		}
+		compactText := nonAlphanumeric.ReplaceAllString(text, "")
+		if strings.Contains(compactText, nonAlphanumeric.ReplaceAllString(invoice, "")) {
+			return true
+		}
+	}
+
+	id := strconv.Itoa(c.PaymentID)
+	for _, prefix := range []string{"#", "PAY", "PAY-", "PAYMENT "} {
+		needle := prefix + id
+		for i := strings.Index(text, needle); i >= 0; {
+			end := i + len(needle)
+			if end == len(text) || text[end] < '0' || text[end] > '9' {
+				return true
+			}
+			next := strings.Index(text[end:], needle)
+			if next < 0 {
+				break
+			}
+			i = end + next
+		}
+	}
+	return false
+}
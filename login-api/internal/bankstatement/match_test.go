@@ -0,0 +1,99 @@
+package bankstatement
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDecide(t *testing.T) {
+	andi := Candidate{PaymentID: 12, CustomerName: "Andi", InvoiceNumber: "INV/2025/000042"}
+	budi := Candidate{PaymentID: 120, CustomerName: "Budi", InvoiceNumber: "INV/2025/000043"}
+	citra := Candidate{PaymentID: 7, CustomerName: "Citra"}
+
+	tests := []struct {
+		name       string
+		line       Line
+		candidates []Candidate
+		want       Decision
+	}{
+		{
+			name: "tanpa kandidat",
+			line: Line{Reference: "INV/2025/000042"},
+			want: Decision{},
+		},
+		{
+			name:       "satu kandidat tanpa referensi",
+			line:       Line{Description: "Transfer masuk"},
+			candidates: []Candidate{citra},
+			want:       Decision{Match: &citra},
+		},
+		{
+			name:       "banyak kandidat tanpa referensi",
+			line:       Line{Description: "Transfer masuk"},
+			candidates: []Candidate{andi, budi, citra},
+			want:       Decision{Review: []Candidate{andi, budi, citra}},
+		},
+		{
+			name:       "nomor faktur pada referensi",
+			line:       Line{Reference: "inv/2025/000043"},
+			candidates: []Candidate{andi, budi, citra},
+			want:       Decision{Match: &budi},
+		},
+		{
+			// Bank menghapus "/" dari berita transfer.
+			name:       "nomor faktur tanpa pemisah",
+			line:       Line{Description: "TRF INV2025000042 ANDI"},
+			candidates: []Candidate{andi, budi},
+			want:       Decision{Match: &andi},
+		},
+		{
+			// PAY-12 tidak boleh cocok dengan ID 120, begitu pula sebaliknya.
+			name:       "ID pembayaran",
+			line:       Line{Description: "pembayaran PAY-12"},
+			candidates: []Candidate{budi, andi},
+			want:       Decision{Match: &andi},
+		},
+		{
+			name:       "ID pembayaran dengan awalan angka serupa",
+			line:       Line{Reference: "#120"},
+			candidates: []Candidate{andi, budi},
+			want:       Decision{Match: &budi},
+		},
+		{
+			name:       "referensi menyebut satu kandidat dari satu-satunya",
+			line:       Line{Reference: "PAYMENT 7"},
+			candidates: []Candidate{citra},
+			want:       Decision{Match: &citra},
+		},
+		{
+			name:       "referensi menyebut beberapa kandidat",
+			line:       Line{Description: "INV/2025/000042 dan INV/2025/000043"},
+			candidates: []Candidate{andi, budi, citra},
+			want:       Decision{Review: []Candidate{andi, budi}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Decide(tt.line, tt.candidates); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Decide() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAmountsEqual(t *testing.T) {
+	tests := []struct {
+		a, b float64
+		want bool
+	}{
+		{1500000, 1500000.004, true},
+		{0.1 + 0.2, 0.3, true},
+		{100, 100.01, false},
+		{-250, 250, false},
+	}
+	for _, tt := range tests {
+		if got := AmountsEqual(tt.a, tt.b); got != tt.want {
+			t.Errorf("AmountsEqual(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
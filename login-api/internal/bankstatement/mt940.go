@@ -0,0 +1,122 @@
+package bankstatement
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// mt940Line61 mengurai tag :61: (baris transaksi), contoh:
+// "2503050305C1500000,00NTRFINV/2025/000042//BANKREF1".
+// Bagian: tanggal valuta YYMMDD, tanggal pembukuan MMDD opsional, tanda
+// C/D/RC/RD, kode dana opsional, nominal berkoma desimal, kode transaksi
+// (N/F/S + 3 karakter), referensi nasabah yang boleh memuat "/", dan referensi
+// bank opsional setelah "//".
+var mt940Line61 = regexp.MustCompile(`^(\d{6})(\d{4})?(RC|RD|C|D)[A-Z]?(\d+,\d{0,2})([NFS][A-Z0-9]{3})?(.*?)(?://(.*))?$`)
+
+// parseMT940 membaca mutasi SWIFT MT940. Deskripsi diambil dari tag :86: yang
+// mengikuti setiap :61:.
+func parseMT940(data []byte) (Statement, error) {
+	var (
+		stmt    Statement
+		current *Line
+		tag     string
+	)
+
+	flush := func() {
+		if current != nil {
+			current.Description = strings.TrimSpace(current.Description)
+			stmt.Lines = append(stmt.Lines, *current)
+			current = nil
+		}
+	}
+
+	for _, raw := range strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n") {
+		line := strings.TrimRight(raw, " \r")
+		if line == "" || line == "-" || strings.HasPrefix(line, "-}") || strings.HasPrefix(line, "{") {
+			continue
+		}
+
+		if strings.HasPrefix(line, ":") {
+			end := strings.Index(line[1:], ":")
+			if end < 0 {
+				continue
+			}
+			tag = line[1 : end+1]
+			value := line[end+2:]
+
+			switch tag {
+			case "25":
+				stmt.AccountID = strings.TrimSpace(value)
+			case "61":
+				flush()
+				l, err := parseMT940Transaction(value)
+				if err != nil {
+					return Statement{}, err
+				}
+				current = &l
+			case "86":
+				if current != nil {
+					current.Description = value
+				}
+			}
+			continue
+		}
+
+		// Baris lanjutan dari tag sebelumnya.
+		if tag == "86" && current != nil {
+			current.Description += " " + strings.TrimSpace(line)
+		}
+	}
+	flush()
+
+	if len(stmt.Lines) == 0 {
+		return Statement{}, errors.New("MT940 tidak berisi tag :61:")
+	}
+	return stmt, nil
+}
+
+func parseMT940Transaction(value string) (Line, error) {
+	m := mt940Line61.FindStringSubmatch(value)
+	if m == nil {
+		return Line{}, fmt.Errorf("baris :61: %q tidak valid", value)
+	}
+
+	valueDate, err := time.Parse("060102", m[1])
+	if err != nil {
+		return Line{}, fmt.Errorf("tanggal valuta %q tidak valid", m[1])
+	}
+	bookingDate := valueDate
+	if m[2] != "" {
+		// Tanggal pembukuan tidak memuat tahun; sesuaikan bila melewati akhir tahun.
+		if t, err := time.Parse("0102", m[2]); err == nil {
+			bookingDate = time.Date(valueDate.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+			if bookingDate.Sub(valueDate) > 180*24*time.Hour {
+				bookingDate = bookingDate.AddDate(-1, 0, 0)
+			} else if valueDate.Sub(bookingDate) > 180*24*time.Hour {
+				bookingDate = bookingDate.AddDate(1, 0, 0)
+			}
+		}
+	}
+
+	amount, err := parseAmount(m[4])
+	if err != nil {
+		return Line{}, err
+	}
+	// D (debit) dan RC (pembatalan kredit) mengurangi saldo.
+	if m[3] == "D" || m[3] == "RC" {
+		amount = -amount
+	}
+
+	reference := strings.TrimSpace(m[6])
+	if reference == "NONREF" {
+		reference = ""
+	}
+	if reference == "" {
+		reference = strings.TrimSpace(m[7])
+	}
+
+	return Line{BookingDate: bookingDate, Amount: amount, Reference: reference}, nil
+}
@@ -0,0 +1,50 @@
+package bankstatement
+
+import "testing"
+
+func TestParseMT940(t *testing.T) {
+	data := "{1:F01BANKIDJAXXXX0000000000}{4:\r\n" +
+		":20:STMT250305\r\n" +
+		":25:1234567890\r\n" +
+		":28C:1/1\r\n" +
+		":60F:C250301IDR0,00\r\n" +
+		":61:2503050305C1500000,00NTRFINV/2025/000042//BANKREF1\r\n" +
+		":86:Transfer dari PT Maju\r\n" +
+		" Jaya untuk INV/2025/000042\r\n" +
+		":61:2503060306D6500,00NCHGNONREF//BANKREF2\r\n" +
+		":86:Biaya admin\r\n" +
+		// Pembatalan kredit tanpa referensi maupun :86:.
+		":61:2412311231RC250,NTRFNONREF\r\n" +
+		// Tanggal pembukuan 2 Januari untuk valuta 31 Desember jatuh di tahun berikutnya.
+		":61:2412310102C100,5NTRFREF3\r\n" +
+		":62F:C250306IDR1493750,00\r\n" +
+		"-}\r\n"
+
+	assertStatement(t, data, Statement{
+		Format:    FormatMT940,
+		AccountID: "1234567890",
+		Lines: []Line{
+			{BookingDate: date(2025, 3, 5), Amount: 1500000, Reference: "INV/2025/000042", Description: "Transfer dari PT Maju Jaya untuk INV/2025/000042"},
+			{BookingDate: date(2025, 3, 6), Amount: -6500, Reference: "BANKREF2", Description: "Biaya admin"},
+			{BookingDate: date(2024, 12, 31), Amount: -250},
+			{BookingDate: date(2025, 1, 2), Amount: 100.5, Reference: "REF3"},
+		},
+	})
+}
+
+func TestParseMT940Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"tanpa :61:", ":20:STMT\n:25:123\n:62F:C250306IDR0,00\n"},
+		{"baris :61: rusak", ":20:STMT\n:61:25030X0305C1,00NTRF\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse([]byte(tt.data), FormatMT940); err == nil {
+				t.Error("Parse() error = nil, want error")
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+// Package bankstatement membaca mutasi rekening bank dalam format CSV, MT940, dan
+// CAMT.053 serta menentukan pembayaran yang cocok untuk setiap baris mutasi.
+package bankstatement
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Format mutasi rekening yang didukung.
+const (
+	FormatCSV     = "csv"
+	FormatMT940   = "mt940"
+	FormatCAMT053 = "camt053"
+)
+
+// ErrUnknownFormat dikembalikan saat format berkas tidak dapat dikenali.
+var ErrUnknownFormat = errors.New("format mutasi rekening tidak dikenali")
+
+// Line adalah satu baris mutasi. Amount positif untuk dana masuk (kredit) dan
+// negatif untuk dana keluar (debit).
+type Line struct {
+	BookingDate time.Time
+	Amount      float64
+	Reference   string
+	Description string
+}
+
+// Statement adalah hasil pembacaan satu berkas mutasi rekening.
+type Statement struct {
+	Format    string
+	AccountID string
+	Lines     []Line
+}
+
+// Parse membaca mutasi rekening dari data. format boleh kosong; dalam hal itu
+// format ditebak dari isi berkas.
+func Parse(data []byte, format string) (Statement, error) {
+	if format == "" {
+		format = DetectFormat(data)
+	}
+
+	var (
+		stmt Statement
+		err  error
+	)
+	switch format {
+	case FormatCSV:
+		stmt, err = parseCSV(data)
+	case FormatMT940:
+		stmt, err = parseMT940(data)
+	case FormatCAMT053:
+		stmt, err = parseCAMT053(data)
+	default:
+		return Statement{}, ErrUnknownFormat
+	}
+	if err != nil {
+		return Statement{}, err
+	}
+	if len(stmt.Lines) == 0 {
+		return Statement{}, errors.New("mutasi rekening tidak berisi transaksi")
+	}
+	stmt.Format = format
+	return stmt, nil
+}
+
+// DetectFormat menebak format mutasi rekening dari isinya.
+func DetectFormat(data []byte) string {
+	trimmed := bytes.TrimSpace(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")))
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("<")):
+		if bytes.Contains(trimmed, []byte("BkToCstmrStmt")) {
+			return FormatCAMT053
+		}
+		return ""
+	case bytes.Contains(trimmed, []byte(":61:")) && (bytes.Contains(trimmed, []byte(":20:")) || bytes.Contains(trimmed, []byte(":25:"))):
+		return FormatMT940
+	default:
+		return FormatCSV
+	}
+}
+
+// parseAmount membaca nominal dalam format Indonesia ("1.500.000,00"), format
+// internasional ("1,500,000.00"), atau angka polos.
+func parseAmount(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "Rp"), "IDR")
+	s = strings.ReplaceAll(strings.TrimSpace(s), " ", "")
+
+	negative := false
+	if strings.HasPrefix(s, "(") && strings.HasSuffix(s, ")") {
+		negative, s = true, s[1:len(s)-1]
+	}
+	if strings.HasPrefix(s, "-") {
+		negative, s = true, s[1:]
+	}
+
+	lastDot, lastComma := strings.LastIndex(s, "."), strings.LastIndex(s, ",")
+	switch {
+	case lastDot < 0 && lastComma >= 0 && (strings.Count(s, ",") > 1 || len(s)-lastComma-1 == 3):
+		// "1,500,000" atau "1,500": koma sebagai pemisah ribuan.
+		s = strings.ReplaceAll(s, ",", "")
+	case lastComma > lastDot:
+		// Koma sebagai pemisah desimal, titik sebagai pemisah ribuan.
+		s = strings.ReplaceAll(s, ".", "")
+		s = strings.Replace(s, ",", ".", 1)
+	case lastDot > lastComma && lastComma >= 0:
+		s = strings.ReplaceAll(s, ",", "")
+	case lastDot >= 0 && strings.Count(s, ".") > 1:
+		// "1.500.000" tanpa bagian desimal.
+		s = strings.ReplaceAll(s, ".", "")
+	case lastDot >= 0 && len(s)-lastDot-1 == 3:
+		// "1.500" lebih mungkin ribuan daripada desimal tiga digit.
+		s = strings.ReplaceAll(s, ".", "")
+	}
+
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("nominal %q tidak valid", s)
+	}
+	if negative {
+		v = -v
+	}
+	return v, nil
+}
+
+var dateLayouts = []string{"2006-01-02", "02/01/2006", "02-01-2006", "2/1/2006", "02/01/06", "20060102"}
+
+func parseDate(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if i := strings.IndexAny(s, " T"); i > 0 {
+		s = s[:i]
+	}
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("tanggal %q tidak valid", s)
+}
@@ -0,0 +1,96 @@
+package bankstatement
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		in   string
+		want float64
+	}{
+		{"1.234,56", 1234.56},
+		{"1,234.56", 1234.56},
+		{"1.500.000", 1500000},
+		{"1,500,000", 1500000},
+		{"1.500", 1500},
+		{"1,500", 1500},
+		{"1,5", 1.5},
+		{"12.50", 12.5},
+		{"1500000", 1500000},
+		{"Rp 1.500.000,00", 1500000},
+		{"IDR 250,000", 250000},
+		{"-1.234,56", -1234.56},
+		{"(2.000,00)", -2000},
+		{"- 75.000", -75000},
+		{"0", 0},
+		{"0,00", 0},
+		{"0.00", 0},
+		{" 0 ", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := parseAmount(tt.in)
+			if err != nil {
+				t.Fatalf("parseAmount(%q) error = %v", tt.in, err)
+			}
+			if got != tt.want {
+				t.Errorf("parseAmount(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseAmountInvalid(t *testing.T) {
+	for _, in := range []string{"", "Rp", "abc", "1.2.3,4,5"} {
+		if got, err := parseAmount(in); err == nil {
+			t.Errorf("parseAmount(%q) = %v, want error", in, got)
+		}
+	}
+}
+
+func TestDetectFormat(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"csv", "Tanggal;Jumlah\n2025-03-05;1.000\n", FormatCSV},
+		{"csv dengan BOM", "\xef\xbb\xbfDate,Amount\n", FormatCSV},
+		{"mt940", ":20:STMT\n:25:123\n:61:2503050305C1,00NTRFREF\n", FormatMT940},
+		{"camt.053", `<?xml version="1.0"?><Document><BkToCstmrStmt></BkToCstmrStmt></Document>`, FormatCAMT053},
+		{"xml lain", `<?xml version="1.0"?><Document><CstmrCdtTrfInitn/></Document>`, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectFormat([]byte(tt.data)); got != tt.want {
+				t.Errorf("DetectFormat() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseUnknownFormat(t *testing.T) {
+	if _, err := Parse([]byte("<Document/>"), ""); err != ErrUnknownFormat {
+		t.Errorf("Parse() error = %v, want %v", err, ErrUnknownFormat)
+	}
+}
+
+// assertStatement membandingkan hasil Parse dengan want, termasuk format yang
+// ditebak dari isi berkas.
+func assertStatement(t *testing.T, data string, want Statement) {
+	t.Helper()
+	got, err := Parse([]byte(data), "")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Parse() =\n%+v\nwant\n%+v", got, want)
+	}
+}
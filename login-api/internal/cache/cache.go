@@ -0,0 +1,83 @@
+// Package cache menyediakan cache in-process sederhana dengan TTL.
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+type entry struct {
+	value     interface{}
+	expiresAt time.Time
+}
+
+// Cache menyimpan nilai per kunci selama TTL. Invalidate mengosongkan seluruh
+// isi sekaligus, misalnya saat data sumbernya berubah. Cache aman dipakai
+// bersamaan oleh beberapa goroutine.
+type Cache struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	maxEntries int
+	entries    map[string]entry
+	// generation bertambah setiap Invalidate sehingga hasil muat yang dimulai
+	// sebelum invalidasi tidak disimpan.
+	generation uint64
+}
+
+// New membuat Cache dengan TTL dan jumlah kunci maksimum. TTL nol atau negatif
+// menonaktifkan cache: GetOrLoad selalu memanggil load.
+func New(ttl time.Duration, maxEntries int) *Cache {
+	return &Cache{ttl: ttl, maxEntries: maxEntries, entries: make(map[string]entry)}
+}
+
+// GetOrLoad mengembalikan nilai untuk key dari cache, atau memanggil load lalu
+// menyimpan hasilnya bila belum ada atau sudah kedaluwarsa. Error dari load tidak
+// disimpan.
+func (c *Cache) GetOrLoad(key string, load func() (interface{}, error)) (interface{}, error) {
+	if c.ttl <= 0 {
+		return load()
+	}
+
+	c.mu.Lock()
+	e, ok := c.entries[key]
+	generation := c.generation
+	c.mu.Unlock()
+	if ok && time.Now().Before(e.expiresAt) {
+		return e.value, nil
+	}
+
+	value, err := load()
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.generation == generation {
+		if len(c.entries) >= c.maxEntries {
+			c.evictExpired()
+		}
+		if len(c.entries) >= c.maxEntries {
+			c.entries = make(map[string]entry)
+		}
+		c.entries[key] = entry{value: value, expiresAt: time.Now().Add(c.ttl)}
+	}
+	return value, nil
+}
+
+// Invalidate mengosongkan seluruh isi cache.
+func (c *Cache) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]entry)
+	c.generation++
+}
+
+func (c *Cache) evictExpired() {
+	now := time.Now()
+	for k, e := range c.entries {
+		if !now.Before(e.expiresAt) {
+			delete(c.entries, k)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+// Package cli berisi perintah yang dipakai bersama oleh binary api dan loginctl.
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"login-api/internal/storage/postgres"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const migrateUsage = "penggunaan: migrate [up | down [jumlah] | status | baseline <versi>]"
+
+// Migrate menjalankan perintah migrate dengan argumen args. Tanpa argumen, semua
+// migrasi yang belum diterapkan dijalankan.
+func Migrate(ctx context.Context, db *pgxpool.Pool, args []string, out io.Writer) error {
+	migrator, err := postgres.NewMigrator(db)
+	if err != nil {
+		return err
+	}
+
+	action := "up"
+	if len(args) > 0 {
+		action, args = args[0], args[1:]
+	}
+
+	switch action {
+	case "up":
+		done, err := migrator.Up(ctx)
+		printMigrations(out, "Diterapkan", done)
+		return err
+	case "down":
+		steps := 1
+		if len(args) > 0 {
+			if steps, err = strconv.Atoi(args[0]); err != nil || steps <= 0 {
+				return errors.New("jumlah migrasi yang dibatalkan harus bilangan bulat positif")
+			}
+		}
+		done, err := migrator.Down(ctx, steps)
+		printMigrations(out, "Dibatalkan", done)
+		return err
+	case "baseline":
+		if len(args) == 0 {
+			return errors.New(migrateUsage)
+		}
+		version, err := strconv.Atoi(args[0])
+		if err != nil {
+			return errors.New("versi baseline harus berupa angka")
+		}
+		done, err := migrator.Baseline(ctx, version)
+		printMigrations(out, "Dicatat sebagai sudah diterapkan", done)
+		return err
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "VERSI\tNAMA\tDITERAPKAN\tCATATAN")
+		for _, st := range statuses {
+			applied, note := "-", ""
+			if st.AppliedAt != nil {
+				applied = st.AppliedAt.Local().Format("2006-01-02 15:04:05")
+			}
+			if st.ChecksumMismatch {
+				note = "checksum berubah"
+			}
+			fmt.Fprintf(tw, "%04d\t%s\t%s\t%s\n", st.Version, st.Name, applied, note)
+		}
+		return tw.Flush()
+	default:
+		return errors.New(migrateUsage)
+	}
+}
+
+func printMigrations(out io.Writer, verb string, list []postgres.Migration) {
+	if len(list) == 0 {
+		fmt.Fprintln(out, "Tidak ada migrasi yang perlu dijalankan.")
+		return
+	}
+	for _, m := range list {
+		fmt.Fprintf(out, "%s: %04d_%s\n", verb, m.Version, m.Name)
+	}
+}
@@ -1,16 +1,122 @@
 package config
 
 import (
+	"login-api/internal/logging"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
+	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 )
 
+// Backend penyimpanan yang didukung.
+const (
+	DatabasePostgres = "postgres"
+	DatabaseSQLite   = "sqlite"
+)
+
+// defaultTimezone dipakai bila DEFAULT_TIMEZONE tidak diisi.
+const defaultTimezone = "Asia/Jakarta"
+
 type Config struct {
 	ServerAddress string
 	JWTSecretKey  string
 	DatabaseURL   string
+
+	// MetricsAddress adalah alamat listener admin yang menyajikan /metrics untuk
+	// Prometheus, terpisah dari API publik. Kosong berarti metrik tidak disajikan.
+	MetricsAddress string
+
+	// DatabaseDriver adalah backend penyimpanan yang dipilih dari skema
+	// DATABASE_URL: DatabasePostgres untuk postgres:// dan postgresql://, atau
+	// DatabaseSQLite untuk sqlite:PATH. SQLitePath adalah PATH tersebut.
+	DatabaseDriver string
+	SQLitePath     string
+
+	// DBQueryTimeout membatasi lama setiap query database. Permintaan yang query-nya
+	// melewati batas ini dijawab 504. Nol berarti hanya dibatasi oleh permintaan.
+	DBQueryTimeout time.Duration
+
+	// DemoMode menjalankan API dengan data contoh. Bila DATABASE_URL memakai
+	// SQLite, data contoh ditulis ke database yang masih kosong; selain itu data
+	// disimpan di memori tanpa database. Hanya autentikasi, pembayaran, dashboard,
+	// dan preferensi yang tersedia.
+	DemoMode bool
+
+	// MigrateOnStart menerapkan migrasi yang belum dijalankan sebelum server dimulai.
+	MigrateOnStart bool
+
+	// LogFormat adalah format keluaran log: logging.FormatConsole untuk
+	// pengembangan atau logging.FormatJSON untuk agregator log di produksi.
+	LogFormat string
+	LogLevel  zerolog.Level
+
+	// OrganizationID mengidentifikasi organisasi pemilik data pada tabel yang
+	// dipisahkan per organisasi, misalnya urutan nomor faktur.
+	OrganizationID string
+	InvoicePrefix  string
+	CompanyName    string
+	CompanyAddress string
+	CompanyEmail   string
+	CompanyPhone   string
+
+	// SubscriptionSchedulerInterval menentukan seberapa sering tagihan langganan
+	// yang jatuh tempo dibuat.
+	SubscriptionSchedulerInterval time.Duration
+
+	// ReceivableJobInterval menentukan seberapa sering pembayaran terlambat ditandai
+	// dan pengingat dikirim. ReminderSchedule berisi hari relatif terhadap jatuh
+	// tempo (negatif = sebelum jatuh tempo) kapan pengingat dikirim.
+	ReceivableJobInterval time.Duration
+	ReminderSchedule      []int
+
+	// SMTPHost kosong berarti email hanya dicatat ke log, tidak dikirim.
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+	SMTPFrom     string
+
+	// GatewayWebhookSecret kosong berarti endpoint webhook gateway menolak semua event.
+	GatewayWebhookSecret    string
+	GatewayWebhookTolerance time.Duration
+
+	// WebhookDeliveryInterval menentukan seberapa sering antrean webhook keluar diperiksa.
+	WebhookDeliveryInterval time.Duration
+
+	// OutboxRelayInterval menentukan seberapa sering outbox event diperiksa.
+	// OutboxSinks berisi tujuan penerbitan event: webhook, log, dan/atau nats.
+	OutboxRelayInterval time.Duration
+	OutboxSinks         []string
+	NATSURL             string
+	NATSSubjectPrefix   string
+
+	// ReconciliationDateWindow adalah selisih hari maksimum antara tanggal mutasi
+	// bank dan tanggal pembayaran agar dapat dicocokkan otomatis.
+	ReconciliationDateWindow int
+
+	// DefaultTimezone adalah zona waktu IANA untuk agregasi dashboard bila
+	// permintaan dan preferensi pengguna tidak menentukannya.
+	DefaultTimezone string
+
+	// DashboardCacheTTL adalah usia maksimum hasil agregasi dashboard di cache.
+	// Cache dikosongkan lebih awal setiap kali pembayaran berubah.
+	DashboardCacheTTL time.Duration
+
+	// LiveHeartbeatInterval adalah jeda keep-alive pada stream dashboard live.
+	LiveHeartbeatInterval time.Duration
+
+	// AlertJobInterval menentukan seberapa sering detektor anomali pembayaran berjalan.
+	// AlertEmailRecipients kosong berarti peringatan tidak dikirim lewat email.
+	AlertJobInterval     time.Duration
+	AlertEmailRecipients []string
+
+	// ReportSchedulerInterval menentukan seberapa sering langganan laporan yang
+	// jatuh tempo diperiksa. Jadwal tiap langganan diatur dengan ekspresi cron.
+	ReportSchedulerInterval time.Duration
 }
 
 func New() *Config {
@@ -20,13 +126,84 @@ func New() *Config {
 	}
 
 	jwtKey := getEnvOrPanic("JWT_SECRET_KEY")
-	dbURL := getEnvOrPanic("DATABASE_URL")
+	demoMode := getEnvBool("DEMO_MODE", false)
+	dbURL := os.Getenv("DATABASE_URL")
+	if !demoMode {
+		dbURL = getEnvOrPanic("DATABASE_URL")
+	}
+	dbDriver, sqlitePath := databaseDriver(dbURL)
 
 	return &Config{
-		ServerAddress: getEnv("SERVER_ADDRESS", ":8080"),
-		JWTSecretKey:  jwtKey,
-		DatabaseURL:   dbURL,
+		ServerAddress:  getEnv("SERVER_ADDRESS", ":8080"),
+		MetricsAddress: getEnv("METRICS_ADDRESS", ":9090"),
+		JWTSecretKey:   jwtKey,
+		DatabaseURL:    dbURL,
+		DatabaseDriver: dbDriver,
+		SQLitePath:     sqlitePath,
+		DBQueryTimeout: getEnvDuration("DB_QUERY_TIMEOUT", 5*time.Second),
+		DemoMode:       demoMode,
+		MigrateOnStart: getEnvBool("MIGRATE_ON_START", false),
+		LogFormat:      getEnvLogFormat("LOG_FORMAT", logging.FormatConsole),
+		LogLevel:       getEnvLogLevel("LOG_LEVEL", zerolog.InfoLevel),
+		OrganizationID: getEnv("ORGANIZATION_ID", "default"),
+		InvoicePrefix:  getEnv("INVOICE_PREFIX", "INV"),
+		CompanyName:    getEnv("COMPANY_NAME", "Perusahaan Anda"),
+		CompanyAddress: getEnv("COMPANY_ADDRESS", ""),
+		CompanyEmail:   getEnv("COMPANY_EMAIL", ""),
+		CompanyPhone:   getEnv("COMPANY_PHONE", ""),
+
+		SubscriptionSchedulerInterval: getEnvDuration("SUBSCRIPTION_SCHEDULER_INTERVAL", time.Hour),
+
+		ReceivableJobInterval: getEnvDuration("RECEIVABLE_JOB_INTERVAL", time.Hour),
+		ReminderSchedule:      getEnvIntList("REMINDER_SCHEDULE", []int{-3, 0, 7, 14, 30}),
+
+		SMTPHost:     getEnv("SMTP_HOST", ""),
+		SMTPPort:     getEnv("SMTP_PORT", "587"),
+		SMTPUsername: getEnv("SMTP_USERNAME", ""),
+		SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+		SMTPFrom:     getEnv("SMTP_FROM", "no-reply@localhost"),
+
+		GatewayWebhookSecret:    getEnv("GATEWAY_WEBHOOK_SECRET", ""),
+		GatewayWebhookTolerance: getEnvDuration("GATEWAY_WEBHOOK_TOLERANCE", 5*time.Minute),
+
+		WebhookDeliveryInterval: getEnvDuration("WEBHOOK_DELIVERY_INTERVAL", 10*time.Second),
+
+		OutboxRelayInterval: getEnvDuration("OUTBOX_RELAY_INTERVAL", 2*time.Second),
+		OutboxSinks:         getEnvList("OUTBOX_SINKS", []string{"webhook"}),
+		NATSURL:             getEnv("NATS_URL", "nats://127.0.0.1:4222"),
+		NATSSubjectPrefix:   getEnv("NATS_SUBJECT_PREFIX", "login-api"),
+
+		ReconciliationDateWindow: getEnvInt("RECONCILIATION_DATE_WINDOW", 3),
+
+		DefaultTimezone: getEnv("DEFAULT_TIMEZONE", defaultTimezone),
+
+		DashboardCacheTTL: getEnvDuration("DASHBOARD_CACHE_TTL", 30*time.Second),
+
+		LiveHeartbeatInterval: getEnvDuration("LIVE_HEARTBEAT_INTERVAL", 15*time.Second),
+
+		AlertJobInterval:     getEnvDuration("ALERT_JOB_INTERVAL", time.Hour),
+		AlertEmailRecipients: getEnvList("ALERT_EMAIL_RECIPIENTS", nil),
+
+		ReportSchedulerInterval: getEnvDuration("REPORT_SCHEDULER_INTERVAL", time.Minute),
+	}
+}
+
+// DatabaseURL memuat .env lalu mengembalikan DATABASE_URL. Dipakai alat baris
+// perintah seperti loginctl yang tidak membutuhkan konfigurasi server lainnya.
+func DatabaseURL() string {
+	if err := godotenv.Load(); err != nil {
+		log.Debug().Msg("Tidak dapat memuat file .env")
 	}
+	return getEnvOrPanic("DATABASE_URL")
+}
+
+// DefaultTimezone memuat .env lalu mengembalikan DEFAULT_TIMEZONE dengan bawaan
+// yang sama seperti Config.DefaultTimezone.
+func DefaultTimezone() string {
+	if err := godotenv.Load(); err != nil {
+		log.Debug().Msg("Tidak dapat memuat file .env")
+	}
+	return getEnv("DEFAULT_TIMEZONE", defaultTimezone)
 }
 
 func getEnv(key, fallback string) string {
@@ -36,10 +213,128 @@ func getEnv(key, fallback string) string {
 	return fallback
 }
 
+func getEnvBool(key string, fallback bool) bool {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return fallback
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Fatal().Msgf("FATAL: Environment variable %s harus berupa true atau false.", key)
+	}
+	return b
+}
+
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return fallback
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		log.Fatal().Msgf("FATAL: Environment variable %s harus berupa durasi positif, contoh: 30m.", key)
+	}
+	return d
+}
+
+func getEnvInt(key string, fallback int) int {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return fallback
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || n < 0 {
+		log.Fatal().Msgf("FATAL: Environment variable %s harus berupa bilangan bulat tidak negatif.", key)
+	}
+	return n
+}
+
+func getEnvIntList(key string, fallback []int) []int {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return fallback
+	}
+	var list []int
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			log.Fatal().Msgf("FATAL: Environment variable %s harus berupa daftar angka dipisah koma, contoh: -3,0,7.", key)
+		}
+		list = append(list, n)
+	}
+	return list
+}
+
+func getEnvList(key string, fallback []string) []string {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return fallback
+	}
+	var list []string
+	for _, part := range strings.Split(value, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			list = append(list, part)
+		}
+	}
+	return list
+}
+
+// databaseDriver menentukan backend dari skema url. URL tanpa skema dianggap
+// connection string PostgreSQL berformat key=value. Untuk SQLite, sisa URL
+// setelah "sqlite:" atau "sqlite://" adalah path file, contoh:
+// sqlite:data/login.db, sqlite:///var/lib/login-api/login.db, atau
+// sqlite::memory:.
+func databaseDriver(url string) (driver, sqlitePath string) {
+	scheme, rest, ok := strings.Cut(url, ":")
+	if !ok || strings.Contains(scheme, "=") || strings.Contains(scheme, " ") {
+		return DatabasePostgres, ""
+	}
+	switch strings.ToLower(scheme) {
+	case "postgres", "postgresql":
+		return DatabasePostgres, ""
+	case "sqlite", "sqlite3":
+		path := strings.TrimPrefix(rest, "//")
+		if path == "" {
+			log.Fatal().Msg("FATAL: DATABASE_URL sqlite harus menyertakan path file, contoh: sqlite:data/login.db")
+		}
+		return DatabaseSQLite, path
+	default:
+		log.Fatal().Msgf("FATAL: Skema DATABASE_URL %q tidak didukung; gunakan postgres:// atau sqlite:", scheme)
+		return "", ""
+	}
+}
+
+func getEnvLogFormat(key, fallback string) string {
+	value := strings.ToLower(getEnv(key, fallback))
+	if value == "" {
+		return fallback
+	}
+	if value != logging.FormatConsole && value != logging.FormatJSON {
+		log.Fatal().Msgf("FATAL: Environment variable %s harus berupa console atau json.", key)
+	}
+	return value
+}
+
+func getEnvLogLevel(key string, fallback zerolog.Level) zerolog.Level {
+	value, exists := os.LookupEnv(key)
+	if !exists || value == "" {
+		return fallback
+	}
+	level, err := zerolog.ParseLevel(strings.ToLower(value))
+	if err != nil || level == zerolog.NoLevel {
+		log.Fatal().Msgf("FATAL: Environment variable %s harus berupa debug, info, warn, atau error.", key)
+	}
+	return level
+}
+
 func getEnvOrPanic(key string) string {
 	value, exists := os.LookupEnv(key)
 	if !exists {
 		log.Fatal().Msgf("FATAL: Environment variable %s tidak diatur.", key)
 	}
 	return value
-}
\ No newline at end of file
+}
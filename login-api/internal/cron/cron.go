@@ -0,0 +1,163 @@
+// Package cron membaca ekspresi jadwal cron lima kolom (menit, jam, tanggal,
+// bulan, hari dalam minggu) dan menghitung waktu jalan berikutnya.
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// shortcuts adalah singkatan jadwal yang umum.
+var shortcuts = map[string]string{
+	"@hourly":  "0 * * * *",
+	"@daily":   "0 0 * * *",
+	"@weekly":  "0 0 * * 1",
+	"@monthly": "0 0 1 * *",
+}
+
+type field struct {
+	min, max int
+	name     string
+}
+
+var fields = [5]field{
+	{0, 59, "menit"},
+	{0, 23, "jam"},
+	{1, 31, "tanggal"},
+	{1, 12, "bulan"},
+	{0, 7, "hari"},
+}
+
+// Schedule adalah jadwal hasil Parse.
+type Schedule struct {
+	minute, hour, dom, month, dow uint64
+	// domAny dan dowAny mencatat kolom yang bernilai "*". Seperti cron pada
+	// umumnya, bila keduanya dibatasi, jadwal berjalan saat salah satunya cocok.
+	domAny, dowAny bool
+}
+
+// Parse membaca ekspresi cron lima kolom atau singkatan @hourly, @daily, @weekly,
+// dan @monthly. Setiap kolom mendukung *, angka, daftar (1,15), rentang (1-5),
+// dan langkah (*/15 atau 0-30/10). Hari minggu boleh ditulis 0 atau 7.
+func Parse(expr string) (Schedule, error) {
+	expr = strings.TrimSpace(expr)
+	if s, ok := shortcuts[expr]; ok {
+		expr = s
+	}
+
+	parts := strings.Fields(expr)
+	if len(parts) != 5 {
+		return Schedule{}, fmt.Errorf("ekspresi cron harus terdiri dari 5 kolom, bukan %d", len(parts))
+	}
+
+	var bits [5]uint64
+	for i, part := range parts {
+		b, err := parseField(part, fields[i])
+		if err != nil {
+			return Schedule{}, err
+		}
+		bits[i] = b
+	}
+	// Hari minggu 7 disamakan dengan 0.
+	if bits[4]&(1<<7) != 0 {
+		bits[4] = bits[4]&^(1<<7) | 1
+	}
+
+	return Schedule{
+		minute: bits[0], hour: bits[1], dom: bits[2], month: bits[3], dow: bits[4],
+		domAny: parts[2] == "*", dowAny: parts[4] == "*",
+	}, nil
+}
+
+func parseField(s string, f field) (uint64, error) {
+	var bits uint64
+	for _, item := range strings.Split(s, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(item, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepPart)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("langkah %q pada kolom %s tidak valid", stepPart, f.name)
+			}
+			step = n
+		}
+
+		lo, hi := f.min, f.max
+		if rangePart != "*" {
+			a, b, isRange := strings.Cut(rangePart, "-")
+			var err error
+			if lo, err = strconv.Atoi(a); err != nil {
+				return 0, fmt.Errorf("nilai %q pada kolom %s tidak valid", a, f.name)
+			}
+			hi = lo
+			if isRange {
+				if hi, err = strconv.Atoi(b); err != nil {
+					return 0, fmt.Errorf("nilai %q pada kolom %s tidak valid", b, f.name)
+				}
+			} else if hasStep {
+				hi = f.max
+			}
+		}
+		if lo < f.min || hi > f.max || lo > hi {
+			return 0, fmt.Errorf("rentang %q pada kolom %s harus antara %d dan %d", rangePart, f.name, f.min, f.max)
+		}
+
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+// Next mengembalikan waktu jadwal pertama setelah t, dihitung pada zona waktu t.
+// Waktu lokal yang tidak ada karena pergantian DST dilewati, sedangkan waktu
+// lokal yang terulang saat DST berakhir cocok dua kali.
+func (s Schedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = t.Truncate(time.Minute).Add(time.Minute)
+
+	// Jadwal yang valid selalu cocok dalam lima tahun (termasuk 29 Februari).
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			next := time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			if !next.After(t) {
+				// Jam berikutnya hilang karena DST dan dinormalisasi mundur.
+				next = t.Add(time.Hour - time.Duration(t.Minute())*time.Minute)
+			}
+			t = next
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+func (s Schedule) dayMatches(t time.Time) bool {
+	dom := s.dom&(1<<uint(t.Day())) != 0
+	dow := s.dow&(1<<uint(t.Weekday())) != 0
+	switch {
+	case s.domAny && s.dowAny:
+		return true
+	case s.domAny:
+		return dow
+	case s.dowAny:
+		return dom
+	default:
+		return dom || dow
+	}
+}
@@ -0,0 +1,121 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+var newYork = mustLoadLocation("America/New_York")
+
+func mustLoadLocation(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		panic(err)
+	}
+	return loc
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"@yearly",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * 32 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"*/-5 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+		"1-x * * * *",
+		"1,,2 * * * *",
+	} {
+		if _, err := Parse(expr); err == nil {
+			t.Errorf("Parse(%q) error = nil, want error", expr)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	utc := func(year int, month time.Month, day, hour, min int) time.Time {
+		return time.Date(year, month, day, hour, min, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name string
+		expr string
+		from time.Time
+		want time.Time
+	}{
+		{"setiap menit", "* * * * *", utc(2026, 10, 19, 10, 7).Add(30 * time.Second), utc(2026, 10, 19, 10, 8)},
+		{"tepat pada jadwal tidak dihitung", "0 7 * * *", utc(2026, 10, 19, 7, 0), utc(2026, 10, 20, 7, 0)},
+		{"langkah menit", "*/15 * * * *", utc(2026, 10, 19, 10, 7), utc(2026, 10, 19, 10, 15)},
+		{"langkah menit melewati jam", "*/15 * * * *", utc(2026, 10, 19, 10, 45), utc(2026, 10, 19, 11, 0)},
+		{"langkah dari angka", "5/20 * * * *", utc(2026, 10, 19, 10, 26), utc(2026, 10, 19, 10, 45)},
+		{"langkah pada rentang", "0-30/10 9 * * *", utc(2026, 10, 19, 9, 25), utc(2026, 10, 19, 9, 30)},
+		{"langkah pada rentang habis", "0-30/10 9 * * *", utc(2026, 10, 19, 9, 31), utc(2026, 10, 20, 9, 0)},
+		{"langkah jam", "0 */6 * * *", utc(2026, 10, 19, 13, 0), utc(2026, 10, 19, 18, 0)},
+		{"daftar dan rentang", "0 8 1,15 * *", utc(2026, 10, 2, 0, 0), utc(2026, 10, 15, 8, 0)},
+		{"@daily", "@daily", utc(2026, 10, 19, 10, 0), utc(2026, 10, 20, 0, 0)},
+		{"@weekly hari Senin", "@weekly", utc(2026, 10, 19, 0, 0), utc(2026, 10, 26, 0, 0)},
+		{"@monthly", "@monthly", utc(2026, 12, 15, 0, 0), utc(2027, 1, 1, 0, 0)},
+
+		// 19 Oktober 2026 hari Senin.
+		{"hari minggu ditulis 0", "0 8 * * 0", utc(2026, 10, 19, 0, 0), utc(2026, 10, 25, 8, 0)},
+		{"hari minggu ditulis 7", "0 8 * * 7", utc(2026, 10, 19, 0, 0), utc(2026, 10, 25, 8, 0)},
+		{"rentang hari sampai 7", "0 8 * * 6-7", utc(2026, 10, 19, 0, 0), utc(2026, 10, 24, 8, 0)},
+		{"rentang hari sampai 7 mencakup minggu", "0 8 * * 6-7", utc(2026, 10, 24, 9, 0), utc(2026, 10, 25, 8, 0)},
+
+		// Tanggal dan hari sama-sama dibatasi: cukup salah satu yang cocok.
+		{"tanggal atau hari, tanggal lebih dulu", "0 0 13 * 5", utc(2026, 10, 10, 0, 0), utc(2026, 10, 13, 0, 0)},
+		{"tanggal atau hari, hari lebih dulu", "0 0 13 * 5", utc(2026, 10, 13, 0, 0), utc(2026, 10, 16, 0, 0)},
+		{"hanya tanggal dibatasi", "0 0 13 * *", utc(2026, 10, 13, 0, 0), utc(2026, 11, 13, 0, 0)},
+		{"hanya hari dibatasi", "0 0 * * 5", utc(2026, 10, 13, 0, 0), utc(2026, 10, 16, 0, 0)},
+
+		{"tanggal 31 melewati bulan pendek", "0 0 31 * *", utc(2026, 10, 31, 12, 0), utc(2026, 12, 31, 0, 0)},
+		{"29 Februari", "0 12 29 2 *", utc(2026, 3, 1, 0, 0), utc(2028, 2, 29, 12, 0)},
+		{"tanggal mustahil", "0 0 30 2 *", utc(2026, 1, 1, 0, 0), time.Time{}},
+		{"tanggal 31 pada bulan 30 hari", "0 0 31 4,6,9,11 *", utc(2026, 1, 1, 0, 0), time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := Parse(tt.expr)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.expr, err)
+			}
+			if got := s.Next(tt.from); !got.Equal(tt.want) {
+				t.Errorf("Next(%v) = %v, want %v", tt.from, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNextDST(t *testing.T) {
+	ny := func(month time.Month, day, hour, min int) time.Time {
+		return time.Date(2026, month, day, hour, min, 0, 0, newYork)
+	}
+
+	// 8 Maret 2026 pukul 02.00 jam melompat ke 03.00, sehingga 02.30 tidak ada.
+	s, _ := Parse("30 2 * * *")
+	if got, want := s.Next(ny(3, 7, 3, 0)), ny(3, 9, 2, 30); !got.Equal(want) {
+		t.Errorf("Next() saat DST dimulai = %v, want %v", got, want)
+	}
+	s, _ = Parse("0 * * * *")
+	if got, want := s.Next(ny(3, 8, 1, 0)), ny(3, 8, 3, 0); !got.Equal(want) {
+		t.Errorf("Next() setiap jam saat DST dimulai = %v, want %v", got, want)
+	}
+
+	// 1 November 2026 pukul 02.00 jam mundur ke 01.00, sehingga 01.30 terjadi dua kali.
+	s, _ = Parse("30 1 * * *")
+	first := s.Next(ny(11, 1, 0, 0))
+	if want := time.Date(2026, 11, 1, 5, 30, 0, 0, time.UTC); !first.Equal(want) {
+		t.Fatalf("Next() saat DST berakhir = %v, want %v", first, want)
+	}
+	if got, want := s.Next(first), time.Date(2026, 11, 1, 6, 30, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("Next() kedua saat DST berakhir = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,138 @@
+package document
+
+import (
+	"fmt"
+	"io"
+	"login-api/internal/model"
+	"strings"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+)
+
+// InvoiceData berisi seluruh data yang dicetak pada faktur/kwitansi.
+type InvoiceData struct {
+	Company model.Company
+	Invoice model.Invoice
+	Payment model.Payment
+}
+
+// Title mengembalikan judul dokumen: kwitansi untuk pembayaran yang sudah lunas,
+// faktur untuk pembayaran lainnya.
+func (d InvoiceData) Title() string {
+	if d.Payment.Status == model.PaymentStatusPaid {
+		return "KWITANSI PEMBAYARAN"
+	}
+	return "FAKTUR"
+}
+
+// FileName mengembalikan nama berkas PDF yang disarankan untuk diunduh.
+func (d InvoiceData) FileName() string {
+	return strings.ReplaceAll(d.Invoice.InvoiceNumber, "/", "-") + ".pdf"
+}
+
+// RenderInvoicePDF menulis dokumen faktur/kwitansi dalam format PDF ke w.
+func RenderInvoicePDF(w io.Writer, data InvoiceData) error {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetTitle(data.Title()+" "+data.Invoice.InvoiceNumber, true)
+	pdf.SetAuthor(data.Company.Name, true)
+	pdf.SetCreationDate(data.Invoice.IssuedAt)
+	pdf.SetMargins(20, 20, 20)
+	pdf.AddPage()
+
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pageWidth, _ := pdf.GetPageSize()
+	left, _, right, _ := pdf.GetMargins()
+	contentWidth := pageWidth - left - right
+
+	// Kop perusahaan
+	pdf.SetFont("Helvetica", "B", 16)
+	pdf.CellFormat(contentWidth, 8, tr(data.Company.Name), "", 1, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 9)
+	for _, line := range []string{data.Company.Address, data.Company.Email, data.Company.Phone} {
+		if line != "" {
+			pdf.MultiCell(contentWidth, 4.5, tr(line), "", "L", false)
+		}
+	}
+	pdf.Ln(3)
+	y := pdf.GetY()
+	pdf.SetDrawColor(180, 180, 180)
+	pdf.Line(left, y, pageWidth-right, y)
+	pdf.Ln(8)
+
+	// Judul dan nomor referensi
+	pdf.SetFont("Helvetica", "B", 14)
+	pdf.CellFormat(contentWidth, 8, data.Title(), "", 1, "C", false, 0, "")
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.CellFormat(contentWidth, 6, "No. "+tr(data.Invoice.InvoiceNumber), "", 1, "C", false, 0, "")
+	pdf.Ln(8)
+
+	// Rincian pembayaran
+	rows := [][2]string{
+		{"Pelanggan", data.Payment.CustomerName},
+		{"Tanggal Pembayaran", FormatDate(data.Payment.PaymentDate)},
+		{"Tanggal Terbit", FormatDate(data.Invoice.IssuedAt)},
+		{"Referensi Pembayaran", fmt.Sprintf("#%d", data.Payment.ID)},
+		{"Status", data.Payment.Status},
+	}
+	if data.Payment.DueDate != nil {
+		rows = append(rows, [2]string{"Jatuh Tempo", FormatDate(*data.Payment.DueDate)})
+	}
+	labelWidth := 55.0
+	for _, row := range rows {
+		pdf.SetFont("Helvetica", "B", 10)
+		pdf.CellFormat(labelWidth, 7, row[0], "", 0, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "", 10)
+		pdf.CellFormat(contentWidth-labelWidth, 7, ": "+tr(row[1]), "", 1, "L", false, 0, "")
+	}
+	pdf.Ln(6)
+
+	// Total
+	pdf.SetFillColor(240, 240, 240)
+	pdf.SetFont("Helvetica", "B", 12)
+	pdf.CellFormat(labelWidth, 10, "Jumlah", "1", 0, "L", true, 0, "")
+	pdf.CellFormat(contentWidth-labelWidth, 10, FormatRupiah(data.Payment.Amount), "1", 1, "R", true, 0, "")
+	pdf.Ln(12)
+
+	pdf.SetFont("Helvetica", "I", 8)
+	pdf.MultiCell(contentWidth, 4, "Dokumen ini dibuat secara otomatis oleh sistem dan sah tanpa tanda tangan.", "", "C", false)
+
+	return pdf.Output(w)
+}
+
+var indonesianMonths = [...]string{
+	"Januari", "Februari", "Maret", "April", "Mei", "Juni",
+	"Juli", "Agustus", "September", "Oktober", "November", "Desember",
+}
+
+// FormatDate memformat tanggal dalam bahasa Indonesia, contoh: "5 Maret 2025".
+func FormatDate(t time.Time) string {
+	return fmt.Sprintf("%d %s %d", t.Day(), indonesianMonths[t.Month()-1], t.Year())
+}
+
+// FormatRupiah memformat nominal dengan pemisah ribuan Indonesia, contoh: "Rp 1.500.000".
+func FormatRupiah(amount float64) string {
+	negative := amount < 0
+	if negative {
+		amount = -amount
+	}
+
+	cents := int64(amount*100 + 0.5)
+	whole := fmt.Sprintf("%d", cents/100)
+
+	var b strings.Builder
+	for i, digit := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteByte('.')
+		}
+		b.WriteRune(digit)
+	}
+	if frac := cents % 100; frac != 0 {
+		fmt.Fprintf(&b, ",%02d", frac)
+	}
+
+	if negative {
+		return "-Rp " + b.String()
+	}
+	return "Rp " + b.String()
+}
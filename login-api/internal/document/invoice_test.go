@@ -0,0 +1,84 @@
+package document
+
+import (
+	"bytes"
+	"login-api/internal/model"
+	"testing"
+	"time"
+)
+
+func TestFormatRupiah(t *testing.T) {
+	tests := []struct {
+		amount float64
+		want   string
+	}{
+		{0, "Rp 0"},
+		{999, "Rp 999"},
+		{1000, "Rp 1.000"},
+		{1500000, "Rp 1.500.000"},
+		{1234567.5, "Rp 1.234.567,50"},
+		{0.05, "Rp 0,05"},
+		{0.999, "Rp 1"},
+		{-250000, "-Rp 250.000"},
+	}
+	for _, tt := range tests {
+		if got := FormatRupiah(tt.amount); got != tt.want {
+			t.Errorf("FormatRupiah(%v) = %q, want %q", tt.amount, got, tt.want)
+		}
+	}
+}
+
+func TestFormatDate(t *testing.T) {
+	tests := []struct {
+		date time.Time
+		want string
+	}{
+		{time.Date(2025, time.March, 5, 0, 0, 0, 0, time.UTC), "5 Maret 2025"},
+		{time.Date(2026, time.January, 1, 23, 59, 0, 0, time.UTC), "1 Januari 2026"},
+		{time.Date(2026, time.December, 31, 0, 0, 0, 0, time.UTC), "31 Desember 2026"},
+	}
+	for _, tt := range tests {
+		if got := FormatDate(tt.date); got != tt.want {
+			t.Errorf("FormatDate(%v) = %q, want %q", tt.date, got, tt.want)
+		}
+	}
+}
+
+func TestInvoiceDataTitleAndFileName(t *testing.T) {
+	tests := []struct {
+		status string
+		want   string
+	}{
+		{model.PaymentStatusPaid, "KWITANSI PEMBAYARAN"},
+		{model.PaymentStatusPending, "FAKTUR"},
+		{model.PaymentStatusOverdue, "FAKTUR"},
+	}
+	for _, tt := range tests {
+		d := InvoiceData{Payment: model.Payment{Status: tt.status}}
+		if got := d.Title(); got != tt.want {
+			t.Errorf("Title() untuk status %s = %q, want %q", tt.status, got, tt.want)
+		}
+	}
+
+	d := InvoiceData{Invoice: model.Invoice{InvoiceNumber: "INV/2025/000042"}}
+	if got, want := d.FileName(), "INV-2025-000042.pdf"; got != want {
+		t.Errorf("FileName() = %q, want %q", got, want)
+	}
+}
+
+func TestRenderInvoicePDF(t *testing.T) {
+	due := time.Date(2025, time.March, 12, 0, 0, 0, 0, time.UTC)
+	data := InvoiceData{
+		Company: model.Company{Name: "PT Contoh Sejahtera", Address: "Jl. Merdeka No. 1, Jakarta", Email: "keuangan@contoh.id"},
+		Invoice: model.Invoice{InvoiceNumber: "INV/2025/000042", IssuedAt: time.Date(2025, time.March, 5, 9, 0, 0, 0, time.UTC)},
+		Payment: model.Payment{ID: 7, CustomerName: "Andi Pratama", Amount: 1500000, Status: model.PaymentStatusPending, PaymentDate: due.AddDate(0, 0, -7), DueDate: &due},
+	}
+
+	var buf bytes.Buffer
+	if err := RenderInvoicePDF(&buf, data); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(buf.Bytes(), []byte("%PDF-")) {
+		t.Errorf("keluaran tidak diawali header PDF: %q", buf.Bytes()[:min(buf.Len(), 16)])
+	}
+}
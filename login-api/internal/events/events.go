@@ -0,0 +1,82 @@
+package events
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Jenis event domain yang dapat diterbitkan.
+const (
+	PaymentCreated  = "payment.created"
+	PaymentUpdated  = "payment.updated"
+	PaymentRefunded = "payment.refunded"
+	UserCreated     = "user.created"
+	UserUpdated     = "user.updated"
+	AlertCreated    = "alert.created"
+	CustomerUpdated = "customer.updated"
+	// CustomerMerged diterbitkan setelah pelanggan duplikat digabungkan ke satu
+	// pelanggan tujuan dan dihapus.
+	CustomerMerged = "customer.merged"
+)
+
+// Types mendaftar semua jenis event yang dikenali.
+var Types = []string{PaymentCreated, PaymentUpdated, PaymentRefunded, UserCreated, UserUpdated, AlertCreated, CustomerUpdated, CustomerMerged}
+
+// IsKnownType melaporkan apakah t adalah jenis event yang dikenali.
+func IsKnownType(t string) bool {
+	for _, known := range Types {
+		if known == t {
+			return true
+		}
+	}
+	return false
+}
+
+// Event adalah amplop event domain. ID unik per event dan dipakai penerima untuk
+// de-duplikasi, karena pengiriman bisa terjadi lebih dari sekali.
+type Event struct {
+	ID         string          `json:"id"`
+	Type       string          `json:"type"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Data       json.RawMessage `json:"data"`
+}
+
+// New membuat event baru dengan data yang di-encode sebagai JSON.
+func New(eventType string, data interface{}) (Event, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return Event{}, err
+	}
+	return Event{ID: newID(), Type: eventType, OccurredAt: time.Now().UTC(), Data: raw}, nil
+}
+
+// Sink adalah tujuan penerbitan event dari outbox, misalnya webhook atau broker
+// pesan. Publish harus mengembalikan error bila event belum diterima dengan pasti;
+// relay akan mencoba lagi sehingga sink dapat menerima event yang sama lebih dari
+// sekali.
+type Sink interface {
+	Name() string
+	Publish(ctx context.Context, ev Event) error
+}
+
+// LogSink menerbitkan event ke log aplikasi. Berguna untuk pengembangan lokal.
+type LogSink struct{}
+
+func (LogSink) Name() string { return "log" }
+
+func (LogSink) Publish(_ context.Context, ev Event) error {
+	log.Info().Str("event_id", ev.ID).Str("event_type", ev.Type).RawJSON("data", ev.Data).Msg("Event diterbitkan")
+	return nil
+}
+
+func newID() string {
+	b := make([]byte, 16)
+	// crypto/rand.Read tidak pernah gagal pada platform yang didukung.
+	_, _ = rand.Read(b)
+	return "evt_" + hex.EncodeToString(b)
+}
@@ -0,0 +1,187 @@
+package events
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// NATSSink menerbitkan event ke broker yang kompatibel dengan protokol NATS
+// (nats-server, atau broker lokal lain yang memahami protokol teksnya). Subjek
+// pesan adalah "<SubjectPrefix>.<jenis event>", misalnya "login-api.payment.created".
+//
+// Setiap pesan dikirim dengan header Nats-Msg-Id berisi ID event sehingga stream
+// JetStream dapat membuang duplikat dari percobaan ulang relay. Publish baru
+// dianggap berhasil setelah server membalas PING berikutnya, yang menandakan
+// pesan sebelumnya sudah diproses.
+type NATSSink struct {
+	URL           string
+	SubjectPrefix string
+	Timeout       time.Duration
+
+	mu   sync.Mutex
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+// NewNATSSink membuat NATSSink untuk server pada rawURL, contoh: nats://127.0.0.1:4222.
+func NewNATSSink(rawURL, subjectPrefix string) *NATSSink {
+	return &NATSSink{URL: rawURL, SubjectPrefix: subjectPrefix, Timeout: 5 * time.Second}
+}
+
+func (s *NATSSink) Name() string { return "nats" }
+
+func (s *NATSSink) Publish(ctx context.Context, ev Event) error {
+	payload, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn == nil {
+		if err := s.connect(ctx); err != nil {
+			return fmt.Errorf("gagal terhubung ke broker NATS: %w", err)
+		}
+	}
+
+	if err := s.publish(ctx, s.subject(ev.Type), ev.ID, payload); err != nil {
+		// Koneksi dalam keadaan tidak diketahui; buat ulang pada percobaan berikutnya.
+		s.conn.Close()
+		s.conn, s.r = nil, nil
+		return fmt.Errorf("gagal menerbitkan event ke broker NATS: %w", err)
+	}
+	return nil
+}
+
+// Close menutup koneksi ke broker.
+func (s *NATSSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn, s.r = nil, nil
+	return err
+}
+
+func (s *NATSSink) subject(eventType string) string {
+	if s.SubjectPrefix == "" {
+		return eventType
+	}
+	return s.SubjectPrefix + "." + eventType
+}
+
+func (s *NATSSink) connect(ctx context.Context) error {
+	u, err := url.Parse(s.URL)
+	if err != nil || u.Host == "" {
+		return fmt.Errorf("URL broker %q tidak valid", s.URL)
+	}
+	host := u.Host
+	if u.Port() == "" {
+		host = net.JoinHostPort(u.Hostname(), "4222")
+	}
+
+	dialer := net.Dialer{Timeout: s.Timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", host)
+	if err != nil {
+		return err
+	}
+	conn.SetDeadline(s.deadline(ctx))
+	r := bufio.NewReader(conn)
+
+	// Server selalu membuka percakapan dengan INFO.
+	line, err := readLine(r)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	if !strings.HasPrefix(line, "INFO ") {
+		conn.Close()
+		return fmt.Errorf("balasan pembuka tidak terduga: %q", line)
+	}
+
+	opts := map[string]interface{}{
+		"verbose":  false,
+		"pedantic": false,
+		"headers":  true,
+		"name":     "login-api",
+		"lang":     "go",
+	}
+	if u.User != nil {
+		opts["user"] = u.User.Username()
+		if pass, ok := u.User.Password(); ok {
+			opts["pass"] = pass
+		}
+	}
+	connectJSON, _ := json.Marshal(opts)
+	if _, err := fmt.Fprintf(conn, "CONNECT %s\r\nPING\r\n", connectJSON); err != nil {
+		conn.Close()
+		return err
+	}
+
+	s.conn, s.r = conn, r
+	if err := s.awaitPong(); err != nil {
+		conn.Close()
+		s.conn, s.r = nil, nil
+		return err
+	}
+	return nil
+}
+
+func (s *NATSSink) publish(ctx context.Context, subject, msgID string, payload []byte) error {
+	s.conn.SetDeadline(s.deadline(ctx))
+
+	header := "NATS/1.0\r\nNats-Msg-Id: " + msgID + "\r\n\r\n"
+	msg := fmt.Sprintf("HPUB %s %d %d\r\n%s%s\r\nPING\r\n", subject, len(header), len(header)+len(payload), header, payload)
+	if _, err := s.conn.Write([]byte(msg)); err != nil {
+		return err
+	}
+	return s.awaitPong()
+}
+
+// awaitPong membaca balasan server sampai PONG, membalas PING dari server dan
+// mengembalikan -ERR sebagai error.
+func (s *NATSSink) awaitPong() error {
+	for {
+		line, err := readLine(s.r)
+		if err != nil {
+			return err
+		}
+		switch {
+		case line == "PONG":
+			return nil
+		case line == "PING":
+			if _, err := s.conn.Write([]byte("PONG\r\n")); err != nil {
+				return err
+			}
+		case strings.HasPrefix(line, "-ERR"):
+			return errors.New(strings.TrimSpace(strings.TrimPrefix(line, "-ERR")))
+		}
+	}
+}
+
+func (s *NATSSink) deadline(ctx context.Context) time.Time {
+	deadline := time.Now().Add(s.Timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		return d
+	}
+	return deadline
+}
+
+func readLine(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
@@ -0,0 +1,185 @@
+// Package forecast memproyeksikan deret waktu pendapatan dengan model
+// exponential smoothing yang deterministik.
+package forecast
+
+import "math"
+
+// Metode yang dipakai Project, tergantung panjang riwayat.
+const (
+	MethodHoltWinters   = "holt_winters"
+	MethodHolt          = "holt"
+	MethodMovingAverage = "moving_average"
+)
+
+// z95 adalah kuantil distribusi normal untuk pita keyakinan 95%.
+const z95 = 1.96
+
+// Point adalah satu titik proyeksi beserta batas bawah dan atas pita keyakinan 95%.
+type Point struct {
+	Value float64
+	Lower float64
+	Upper float64
+}
+
+// Result adalah hasil proyeksi.
+type Result struct {
+	Method string
+	Points []Point
+}
+
+// grid adalah kandidat parameter smoothing. Parameter dipilih dengan galat
+// kuadrat satu langkah terkecil pada riwayat, sehingga hasilnya selalu sama untuk
+// data yang sama.
+var grid = []float64{0.1, 0.2, 0.3, 0.4, 0.5, 0.6, 0.7, 0.8, 0.9}
+
+// Project memproyeksikan horizon periode setelah history. season adalah panjang
+// musim (misalnya 7 untuk data harian); Holt-Winters aditif dipakai bila riwayat
+// mencakup setidaknya dua musim penuh, Holt linear bila riwayat minimal tiga
+// titik, dan rata-rata bergerak selebihnya. Nilai negatif dipotong ke nol karena
+// pendapatan tidak pernah negatif.
+func Project(history []float64, horizon, season int) Result {
+	var (
+		method string
+		values []float64
+		sigma  float64
+	)
+	switch {
+	case season > 1 && len(history) >= 2*season:
+		method = MethodHoltWinters
+		values, sigma = bestHoltWinters(history, horizon, season)
+	case len(history) >= 3:
+		method = MethodHolt
+		values, sigma = bestHolt(history, horizon)
+	default:
+		method = MethodMovingAverage
+		values, sigma = movingAverage(history, horizon)
+	}
+
+	points := make([]Point, horizon)
+	for h := range points {
+		// Ketidakpastian tumbuh seiring jarak proyeksi.
+		band := z95 * sigma * math.Sqrt(float64(h+1))
+		points[h] = Point{
+			Value: math.Max(0, values[h]),
+			Lower: math.Max(0, values[h]-band),
+			Upper: math.Max(0, values[h]+band),
+		}
+	}
+	return Result{Method: method, Points: points}
+}
+
+func bestHoltWinters(x []float64, horizon, m int) ([]float64, float64) {
+	var best []float64
+	bestSSE, bestN := math.Inf(1), 0
+	for _, alpha := range grid {
+		for _, beta := range grid {
+			for _, gamma := range grid {
+				values, sse, n := holtWinters(x, horizon, m, alpha, beta, gamma)
+				if sse < bestSSE {
+					best, bestSSE, bestN = values, sse, n
+				}
+			}
+		}
+	}
+	return best, rmse(bestSSE, bestN)
+}
+
+// holtWinters menjalankan Holt-Winters aditif. Level, tren, dan musim awal
+// diambil dari dua musim pertama. Mengembalikan proyeksi, jumlah kuadrat galat
+// satu langkah, dan jumlah galat yang dihitung.
+func holtWinters(x []float64, horizon, m int, alpha, beta, gamma float64) ([]float64, float64, int) {
+	first, second := mean(x[:m]), mean(x[m:2*m])
+	level := first
+	trend := (second - first) / float64(m)
+	seasonal := make([]float64, m)
+	for i := range seasonal {
+		seasonal[i] = x[i] - first
+	}
+
+	var sse float64
+	for t := m; t < len(x); t++ {
+		s := seasonal[t%m]
+		e := x[t] - (level + trend + s)
+		sse += e * e
+
+		prev := level
+		level = alpha*(x[t]-s) + (1-alpha)*(level+trend)
+		trend = beta*(level-prev) + (1-beta)*trend
+		seasonal[t%m] = gamma*(x[t]-level) + (1-gamma)*s
+	}
+
+	values := make([]float64, horizon)
+	for h := range values {
+		values[h] = level + float64(h+1)*trend + seasonal[(len(x)+h)%m]
+	}
+	return values, sse, len(x) - m
+}
+
+func bestHolt(x []float64, horizon int) ([]float64, float64) {
+	var best []float64
+	bestSSE, bestN := math.Inf(1), 0
+	for _, alpha := range grid {
+		for _, beta := range grid {
+			values, sse, n := holt(x, horizon, alpha, beta)
+			if sse < bestSSE {
+				best, bestSSE, bestN = values, sse, n
+			}
+		}
+	}
+	return best, rmse(bestSSE, bestN)
+}
+
+// holt menjalankan Holt linear (double exponential smoothing).
+func holt(x []float64, horizon int, alpha, beta float64) ([]float64, float64, int) {
+	level, trend := x[0], x[1]-x[0]
+
+	var sse float64
+	for t := 1; t < len(x); t++ {
+		e := x[t] - (level + trend)
+		sse += e * e
+
+		prev := level
+		level = alpha*x[t] + (1-alpha)*(level+trend)
+		trend = beta*(level-prev) + (1-beta)*trend
+	}
+
+	values := make([]float64, horizon)
+	for h := range values {
+		values[h] = level + float64(h+1)*trend
+	}
+	return values, sse, len(x) - 1
+}
+
+// movingAverage memproyeksikan rata-rata riwayat secara datar dengan simpangan
+// baku riwayat sebagai ketidakpastian.
+func movingAverage(x []float64, horizon int) ([]float64, float64) {
+	avg := mean(x)
+	var sse float64
+	for _, v := range x {
+		sse += (v - avg) * (v - avg)
+	}
+
+	values := make([]float64, horizon)
+	for h := range values {
+		values[h] = avg
+	}
+	return values, rmse(sse, len(x))
+}
+
+func mean(x []float64) float64 {
+	if len(x) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, v := range x {
+		sum += v
+	}
+	return sum / float64(len(x))
+}
+
+func rmse(sse float64, n int) float64 {
+	if n == 0 {
+		return 0
+	}
+	return math.Sqrt(sse / float64(n))
+}
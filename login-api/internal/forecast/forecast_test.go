@@ -0,0 +1,177 @@
+package forecast
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+const epsilon = 1e-6
+
+// repeat mengulang pola sebanyak n kali.
+func repeat(pattern []float64, n int) []float64 {
+	var x []float64
+	for i := 0; i < n; i++ {
+		x = append(x, pattern...)
+	}
+	return x
+}
+
+func assertValues(t *testing.T, got Result, want []float64) {
+	t.Helper()
+	if len(got.Points) != len(want) {
+		t.Fatalf("len(Points) = %d, want %d", len(got.Points), len(want))
+	}
+	for h, p := range got.Points {
+		if math.Abs(p.Value-want[h]) > epsilon {
+			t.Errorf("Points[%d].Value = %v, want %v", h, p.Value, want[h])
+		}
+	}
+}
+
+func TestProjectMethod(t *testing.T) {
+	tests := []struct {
+		name    string
+		history int
+		season  int
+		want    string
+	}{
+		{"dua musim penuh", 14, 7, MethodHoltWinters},
+		{"kurang dari dua musim", 13, 7, MethodHolt},
+		{"tanpa musim", 30, 0, MethodHolt},
+		{"musim satu periode", 30, 1, MethodHolt},
+		{"tiga titik", 3, 7, MethodHolt},
+		{"dua titik", 2, 7, MethodMovingAverage},
+		{"satu titik", 1, 0, MethodMovingAverage},
+		{"tanpa riwayat", 0, 7, MethodMovingAverage},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			history := make([]float64, tt.history)
+			for i := range history {
+				history[i] = float64(100 + i%3)
+			}
+			got := Project(history, 5, tt.season)
+			if got.Method != tt.want {
+				t.Errorf("Method = %q, want %q", got.Method, tt.want)
+			}
+			if len(got.Points) != 5 {
+				t.Errorf("len(Points) = %d, want 5", len(got.Points))
+			}
+		})
+	}
+}
+
+func TestProjectExactFit(t *testing.T) {
+	week := []float64{100, 120, 140, 160, 180, 60, 40}
+	linear := make([]float64, 10)
+	for i := range linear {
+		linear[i] = 10 + 5*float64(i)
+	}
+
+	tests := []struct {
+		name    string
+		history []float64
+		season  int
+		method  string
+		want    []float64
+	}{
+		{"konstan dengan musim", repeat([]float64{100}, 21), 7, MethodHoltWinters, []float64{100, 100, 100}},
+		{"konstan tanpa musim", repeat([]float64{100}, 5), 0, MethodHolt, []float64{100, 100, 100}},
+		{"tren linear", linear, 0, MethodHolt, []float64{60, 65, 70}},
+		{"musiman", repeat(week, 4), 7, MethodHoltWinters, week},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Project(tt.history, len(tt.want), tt.season)
+			if got.Method != tt.method {
+				t.Errorf("Method = %q, want %q", got.Method, tt.method)
+			}
+			assertValues(t, got, tt.want)
+			// Riwayat yang cocok sempurna tidak menyisakan ketidakpastian.
+			for h, p := range got.Points {
+				if math.Abs(p.Upper-p.Lower) > epsilon {
+					t.Errorf("Points[%d] pita = [%v, %v], want selebar nol", h, p.Lower, p.Upper)
+				}
+			}
+		})
+	}
+}
+
+func TestProjectSeasonalTrend(t *testing.T) {
+	week := []float64{100, 120, 140, 160, 180, 60, 40}
+	var history []float64
+	for i := 0; i < 42; i++ {
+		history = append(history, week[i%7]+2*float64(i))
+	}
+
+	got := Project(history, 7, 7)
+	if got.Method != MethodHoltWinters {
+		t.Fatalf("Method = %q, want %q", got.Method, MethodHoltWinters)
+	}
+	for h, p := range got.Points {
+		want := week[h] + 2*float64(42+h)
+		if math.Abs(p.Value-want) > 0.05*want {
+			t.Errorf("Points[%d].Value = %v, want sekitar %v", h, p.Value, want)
+		}
+		if p.Lower > want || p.Upper < want {
+			t.Errorf("Points[%d] pita [%v, %v] tidak memuat %v", h, p.Lower, p.Upper, want)
+		}
+	}
+}
+
+func TestProjectMovingAverage(t *testing.T) {
+	got := Project([]float64{10, 20}, 2, 7)
+	// Rata-rata 15 dengan simpangan baku 5.
+	want := []Point{
+		{Value: 15, Lower: 15 - 1.96*5, Upper: 15 + 1.96*5},
+		{Value: 15, Lower: 15 - 1.96*5*math.Sqrt2, Upper: 15 + 1.96*5*math.Sqrt2},
+	}
+	for h, p := range got.Points {
+		if math.Abs(p.Value-want[h].Value) > epsilon || math.Abs(p.Lower-want[h].Lower) > epsilon || math.Abs(p.Upper-want[h].Upper) > epsilon {
+			t.Errorf("Points[%d] = %+v, want %+v", h, p, want[h])
+		}
+	}
+
+	if got := Project(nil, 3, 7); !reflect.DeepEqual(got.Points, make([]Point, 3)) {
+		t.Errorf("Project(nil) = %+v, want nol", got.Points)
+	}
+	if got := Project([]float64{10, 20}, 0, 7); len(got.Points) != 0 {
+		t.Errorf("Project() dengan horizon 0 = %+v, want kosong", got.Points)
+	}
+}
+
+func TestProjectIntervals(t *testing.T) {
+	history := []float64{120, 80, 150, 90, 130, 70, 160, 100, 140, 60, 170, 110, 90, 150, 80}
+
+	got := Project(history, 10, 0)
+	prevWidth := 0.0
+	for h, p := range got.Points {
+		if p.Lower > p.Value || p.Value > p.Upper {
+			t.Errorf("Points[%d] = %+v, want Lower <= Value <= Upper", h, p)
+		}
+		// Pita atas melebar seiring jarak proyeksi.
+		width := p.Upper - p.Value
+		if p.Value > 0 && width <= prevWidth {
+			t.Errorf("Points[%d] lebar pita %v tidak lebih besar dari %v", h, width, prevWidth)
+		}
+		prevWidth = width
+	}
+
+	// Hasil harus sama persis untuk data yang sama.
+	if again := Project(history, 10, 0); !reflect.DeepEqual(got, again) {
+		t.Errorf("Project() tidak deterministik: %+v != %+v", got, again)
+	}
+}
+
+func TestProjectClampsNegative(t *testing.T) {
+	got := Project([]float64{50, 40, 30, 20, 10}, 3, 0)
+	if got.Method != MethodHolt {
+		t.Fatalf("Method = %q, want %q", got.Method, MethodHolt)
+	}
+	for h, p := range got.Points {
+		if p != (Point{}) {
+			t.Errorf("Points[%d] = %+v, want dipotong ke nol", h, p)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package gateway
+
+import "login-api/internal/model"
+
+// Jenis event yang dikirim oleh penyedia pembayaran.
+const (
+	EventPaymentPending   = "payment.pending"
+	EventPaymentSucceeded = "payment.succeeded"
+	EventPaymentFailed    = "payment.failed"
+	EventPaymentExpired   = "payment.expired"
+	EventPaymentRefunded  = "payment.refunded"
+)
+
+// Event adalah isi webhook dari penyedia pembayaran.
+type Event struct {
+	ID      string    `json:"id"`
+	Type    string    `json:"type"`
+	Created int64     `json:"created"`
+	Data    EventData `json:"data"`
+}
+
+// EventData berisi referensi pembayaran internal yang dititipkan saat transaksi dibuat.
+type EventData struct {
+	PaymentID int      `json:"payment_id"`
+	Amount    *float64 `json:"amount,omitempty"`
+	Reference string   `json:"reference,omitempty"`
+}
+
+// TargetStatus memetakan jenis event ke status pembayaran tujuan. Nilai false
+// berarti jenis event tidak dikenali dan harus diabaikan.
+func TargetStatus(eventType string) (string, bool) {
+	switch eventType {
+	case EventPaymentPending:
+		return model.PaymentStatusPending, true
+	case EventPaymentSucceeded:
+		return model.PaymentStatusPaid, true
+	case EventPaymentFailed, EventPaymentExpired:
+		return model.PaymentStatusCancelled, true
+	case EventPaymentRefunded:
+		return model.PaymentStatusRefunded, true
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,66 @@
+package gateway
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// FakeGateway meniru penyedia pembayaran yang mengirim webhook bertanda tangan.
+// Dipakai pada pengujian (misalnya bersama httptest.Server) dan pengembangan lokal.
+type FakeGateway struct {
+	Secret []byte
+	Client *http.Client
+	// Now dapat diganti untuk menguji penolakan timestamp kedaluwarsa.
+	Now func() time.Time
+
+	seq atomic.Int64
+}
+
+// NewFakeGateway membuat FakeGateway dengan secret yang sama seperti konfigurasi server.
+func NewFakeGateway(secret []byte) *FakeGateway {
+	return &FakeGateway{Secret: secret, Client: http.DefaultClient, Now: time.Now}
+}
+
+// NewEvent membuat event baru dengan ID unik.
+func (g *FakeGateway) NewEvent(eventType string, paymentID int, amount float64) Event {
+	n := g.seq.Add(1)
+	return Event{
+		ID:      fmt.Sprintf("evt_fake_%d_%d", g.Now().UnixNano(), n),
+		Type:    eventType,
+		Created: g.Now().Unix(),
+		Data: EventData{
+			PaymentID: paymentID,
+			Amount:    &amount,
+			Reference: fmt.Sprintf("FAKE-%d", paymentID),
+		},
+	}
+}
+
+// NewRequest menyusun permintaan webhook bertanda tangan untuk event ev.
+func (g *FakeGateway) NewRequest(url string, ev Event) (*http.Request, error) {
+	body, err := json.Marshal(ev)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(SignatureHeader, Sign(g.Secret, body, g.Now()))
+	return req, nil
+}
+
+// Deliver mengirim event ev ke url. Pemanggil wajib menutup body respons.
+func (g *FakeGateway) Deliver(url string, ev Event) (*http.Response, error) {
+	req, err := g.NewRequest(url, ev)
+	if err != nil {
+		return nil, err
+	}
+	return g.Client.Do(req)
+}
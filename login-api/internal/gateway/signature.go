@@ -0,0 +1,79 @@
+package gateway
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SignatureHeader adalah header HTTP yang membawa tanda tangan webhook dengan format
+// "t=<unix timestamp>,v1=<hex HMAC-SHA256 dari "<timestamp>.<body>">".
+const SignatureHeader = "X-Gateway-Signature"
+
+var (
+	// ErrInvalidSignature dikembalikan saat header tanda tangan rusak atau tidak cocok.
+	ErrInvalidSignature = errors.New("tanda tangan webhook tidak valid")
+	// ErrTimestampOutOfRange dikembalikan saat timestamp tanda tangan terlalu lama atau
+	// terlalu jauh di masa depan, untuk mencegah serangan replay.
+	ErrTimestampOutOfRange = errors.New("timestamp webhook di luar batas toleransi")
+)
+
+// Sign menghitung nilai header tanda tangan untuk body pada waktu ts.
+func Sign(secret, body []byte, ts time.Time) string {
+	unix := strconv.FormatInt(ts.Unix(), 10)
+	return fmt.Sprintf("t=%s,v1=%s", unix, computeMAC(secret, unix, body))
+}
+
+// VerifySignature memeriksa header tanda tangan terhadap body. Beberapa nilai v1
+// diperbolehkan agar rotasi secret di sisi penyedia tidak memutus pengiriman.
+func VerifySignature(secret, body []byte, header string, tolerance time.Duration, now time.Time) error {
+	var (
+		timestamp  string
+		signatures []string
+	)
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+	if timestamp == "" || len(signatures) == 0 {
+		return ErrInvalidSignature
+	}
+
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	age := now.Sub(time.Unix(unix, 0))
+	if age > tolerance || age < -tolerance {
+		return ErrTimestampOutOfRange
+	}
+
+	expected := []byte(computeMAC(secret, timestamp, body))
+	for _, sig := range signatures {
+		if hmac.Equal(expected, []byte(sig)) {
+			return nil
+		}
+	}
+	return ErrInvalidSignature
+}
+
+func computeMAC(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
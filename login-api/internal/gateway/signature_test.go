@@ -0,0 +1,73 @@
+package gateway
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestVerifySignature(t *testing.T) {
+	secret := []byte("rahasia-webhook")
+	now := time.Date(2026, time.March, 15, 9, 0, 0, 0, time.UTC)
+	tolerance := 5 * time.Minute
+
+	// signedAt menyusun body dan header tanda tangan dari FakeGateway pada waktu ts.
+	signedAt := func(ts time.Time) ([]byte, string) {
+		g := NewFakeGateway(secret)
+		g.Now = func() time.Time { return ts }
+		req, err := g.NewRequest("http://gateway.test/webhook", g.NewEvent(EventPaymentSucceeded, 7, 150000))
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return body, req.Header.Get(SignatureHeader)
+	}
+	body, header := signedAt(now)
+	_, oldHeader := signedAt(now.Add(-tolerance - time.Second))
+	_, futureHeader := signedAt(now.Add(tolerance + time.Second))
+	otherMAC := computeMAC([]byte("secret-lama"), strings.TrimPrefix(strings.Split(header, ",")[0], "t="), body)
+
+	tests := []struct {
+		name   string
+		secret []byte
+		body   []byte
+		header string
+		want   error
+	}{
+		{"valid", secret, body, header, nil},
+		{"body diubah", secret, []byte(strings.Replace(string(body), "150000", "1", 1)), header, ErrInvalidSignature},
+		{"secret berbeda", []byte("secret-lain"), body, header, ErrInvalidSignature},
+		{"timestamp kedaluwarsa", secret, body, oldHeader, ErrTimestampOutOfRange},
+		{"timestamp di masa depan", secret, body, futureHeader, ErrTimestampOutOfRange},
+		// Saat rotasi secret, penyedia mengirim satu v1 untuk setiap secret.
+		{"beberapa v1", secret, body, header + ",v1=" + otherMAC, nil},
+		{"beberapa v1 tanpa yang cocok", secret, body, strings.Split(header, ",")[0] + ",v1=" + otherMAC, ErrInvalidSignature},
+		{"header kosong", secret, body, "", ErrInvalidSignature},
+		{"tanpa v1", secret, body, strings.Split(header, ",")[0], ErrInvalidSignature},
+		{"timestamp bukan angka", secret, body, "t=kemarin," + strings.Split(header, ",")[1], ErrInvalidSignature},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifySignature(tt.secret, tt.body, tt.header, tolerance, now)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("VerifySignature() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestTargetStatus(t *testing.T) {
+	for _, eventType := range []string{EventPaymentPending, EventPaymentSucceeded, EventPaymentFailed, EventPaymentExpired, EventPaymentRefunded} {
+		if status, ok := TargetStatus(eventType); !ok || status == "" {
+			t.Errorf("TargetStatus(%q) = %q, %v, want status tujuan", eventType, status, ok)
+		}
+	}
+	if status, ok := TargetStatus("payment.disputed"); ok {
+		t.Errorf("TargetStatus(payment.disputed) = %q, want tidak dikenali", status)
+	}
+}
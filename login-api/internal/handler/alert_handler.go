@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"errors"
+	"login-api/internal/model"
+	"login-api/internal/service"
+	"login-api/internal/storage"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+type AlertHandler struct {
+	Svc *service.AlertService
+}
+
+func NewAlertHandler(svc *service.AlertService) *AlertHandler {
+	return &AlertHandler{Svc: svc}
+}
+
+// ListAlertsHandler menampilkan peringatan anomali terbaru. Secara bawaan hanya
+// peringatan yang belum ditindaklanjuti; gunakan ?status=all untuk semuanya.
+func (h *AlertHandler) ListAlertsHandler(w http.ResponseWriter, r *http.Request) {
+	includeAcknowledged := false
+	switch r.URL.Query().Get("status") {
+	case "", "open":
+	case "all":
+		includeAcknowledged = true
+	default:
+		http.Error(w, `{"message":"Parameter status harus open atau all."}`, http.StatusBadRequest)
+		return
+	}
+
+	alerts, err := h.Svc.Store.ListAlerts(r.Context(), h.Svc.OrganizationID, includeAcknowledged, 200)
+	if err != nil {
+		writeServerError(w, err, `{"message":"Gagal mengambil data peringatan."}`)
+		return
+	}
+	if alerts == nil {
+		alerts = []model.Alert{}
+	}
+
+	writeJSON(w, http.StatusOK, alerts)
+}
+
+// AcknowledgeAlertHandler menandai peringatan sudah ditindaklanjuti.
+func (h *AlertHandler) AcknowledgeAlertHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, `{"message":"ID peringatan tidak valid."}`, http.StatusBadRequest)
+		return
+	}
+
+	alert, err := h.Svc.Store.AcknowledgeAlert(r.Context(), h.Svc.OrganizationID, id)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			http.Error(w, `{"message":"Peringatan tidak ditemukan."}`, http.StatusNotFound)
+			return
+		}
+		writeServerError(w, err, `{"message":"Gagal memperbarui peringatan."}`)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, alert)
+}
@@ -3,14 +3,16 @@ package handler
 import (
 	"encoding/json"
 	"errors"
-	"log"
+	"login-api/internal/metrics"
 	"login-api/internal/model"
 	"login-api/internal/service"
+	"login-api/internal/storage"
 	"login-api/internal/validator"
 	"net/http"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/rs/zerolog/log"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -38,7 +40,7 @@ func (h *AuthHandler) RegisterHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err := h.AuthSvc.RegisterUser(creds)
+	err := h.AuthSvc.RegisterUser(r.Context(), creds)
 	if err != nil {
 		// Menentukan kode status berdasarkan jenis error dari service
 		if errors.Is(err, service.ErrEmailExists) {
@@ -46,6 +48,10 @@ func (h *AuthHandler) RegisterHandler(w http.ResponseWriter, r *http.Request) {
 			json.NewEncoder(w).Encode(model.Response{Message: err.Error(), Success: false})
 			return
 		}
+		if isUnavailable(err) {
+			writeServerError(w, err, "")
+			return
+		}
 		// Untuk error validasi lainnya
 		w.WriteHeader(http.StatusBadRequest)
 		json.NewEncoder(w).Encode(model.Response{Message: err.Error(), Success: false})
@@ -66,17 +72,25 @@ func (h *AuthHandler) LoginHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	accessToken, refreshToken, err := h.AuthSvc.LoginUser(creds)
+	accessToken, refreshToken, err := h.AuthSvc.LoginUser(r.Context(), creds)
 	if err != nil {
 		if errors.Is(err, validator.ErrInvalidCredentials) {
+			metrics.LoginAttempts.WithLabelValues(metrics.ResultFailure).Inc()
 			w.WriteHeader(http.StatusUnauthorized)
 			json.NewEncoder(w).Encode(model.Response{Message: err.Error(), Success: false})
 			return
 		}
-		log.Printf("KRITIS: Gagal membuat token JWT: %v", err)
-		http.Error(w, `{"message":"Terjadi kesalahan internal pada server."}`, http.StatusInternalServerError)
+		if errors.Is(err, service.ErrAccountDisabled) {
+			metrics.LoginAttempts.WithLabelValues(metrics.ResultFailure).Inc()
+			w.WriteHeader(http.StatusForbidden)
+			json.NewEncoder(w).Encode(model.Response{Message: err.Error(), Success: false})
+			return
+		}
+		log.Ctx(r.Context()).Error().Err(err).Msg("Gagal memproses login")
+		writeServerError(w, err, `{"message":"Terjadi kesalahan internal pada server."}`)
 		return
 	}
+	metrics.LoginAttempts.WithLabelValues(metrics.ResultSuccess).Inc()
 
 	// Set HttpOnly cookie untuk access token
 	http.SetCookie(w, &http.Cookie{
@@ -112,6 +126,7 @@ func (h *AuthHandler) RefreshTokenHandler(w http.ResponseWriter, r *http.Request
 	c, err := r.Cookie("refresh_token")
 	if err != nil {
 		if err == http.ErrNoCookie {
+			metrics.TokenRefreshes.WithLabelValues(metrics.ResultFailure).Inc()
 			http.Error(w, `{"message":"Refresh token tidak ditemukan."}`, http.StatusUnauthorized)
 			return
 		}
@@ -126,17 +141,30 @@ func (h *AuthHandler) RefreshTokenHandler(w http.ResponseWriter, r *http.Request
 	})
 
 	if err != nil || !token.Valid {
+		metrics.TokenRefreshes.WithLabelValues(metrics.ResultFailure).Inc()
 		http.Error(w, `{"message":"Refresh token tidak valid atau kedaluwarsa."}`, http.StatusUnauthorized)
 		return
 	}
 
+	if err := h.AuthSvc.ValidateRefresh(r.Context(), claims); err != nil {
+		if isUnavailable(err) {
+			writeServerError(w, err, "")
+			return
+		}
+		metrics.TokenRefreshes.WithLabelValues(metrics.ResultFailure).Inc()
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode(model.Response{Message: err.Error(), Success: false})
+		return
+	}
+
 	// Buat access token baru
 	expirationTime := time.Now().Add(15 * time.Minute)
 	claims.ExpiresAt = jwt.NewNumericDate(expirationTime)
 	accessToken := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	accessTokenString, err := accessToken.SignedString(h.JwtKey)
 	if err != nil {
-		http.Error(w, `{"message":"Gagal membuat token baru."}`, http.StatusInternalServerError)
+		writeServerError(w, err, `{"message":"Gagal membuat token baru."}`)
 		return
 	}
 
@@ -151,6 +179,7 @@ func (h *AuthHandler) RefreshTokenHandler(w http.ResponseWriter, r *http.Request
 		Path:     "/",
 	})
 
+	metrics.TokenRefreshes.WithLabelValues(metrics.ResultSuccess).Inc()
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(model.Response{Message: "Token berhasil diperbarui.", Success: true})
 }
@@ -194,11 +223,15 @@ func (h *AuthHandler) ChangePasswordHandler(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	user, ok := h.AuthSvc.UserStore.GetUser(req.Email) // Akses UserStore melalui AuthSvc
-	if !ok {
+	user, err := h.AuthSvc.UserStore.GetUser(r.Context(), req.Email) // Akses UserStore melalui AuthSvc
+	if errors.Is(err, storage.ErrNotFound) {
 		http.Error(w, `{"message":"Pengguna tidak ditemukan."}`, http.StatusNotFound)
 		return
 	}
+	if err != nil {
+		writeServerError(w, err, `{"message":"Terjadi kesalahan internal pada server."}`)
+		return
+	}
 
 	if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.OldPassword)) != nil {
 		w.WriteHeader(http.StatusUnauthorized)
@@ -220,16 +253,16 @@ func (h *AuthHandler) ChangePasswordHandler(w http.ResponseWriter, r *http.Reque
 
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
 	if err != nil {
-		log.Printf("KRITIS: Gagal melakukan hash password baru untuk email %s: %v", req.Email, err)
-		http.Error(w, `{"message":"Terjadi kesalahan internal pada server."}`, http.StatusInternalServerError)
+		log.Ctx(r.Context()).Error().Err(err).Str("email", req.Email).Msg("Gagal melakukan hash password baru")
+		writeServerError(w, err, `{"message":"Terjadi kesalahan internal pada server."}`)
 		return
 	}
 
 	user.PasswordHash = string(hashedPassword)
 
-	if err := h.AuthSvc.UserStore.UpdateUser(req.Email, user); err != nil {
-		log.Printf("ERROR: Gagal memperbarui password untuk email %s: %v", req.Email, err)
-		http.Error(w, `{"message":"Gagal memperbarui kata sandi."}`, http.StatusInternalServerError)
+	if err := h.AuthSvc.UserStore.UpdateUser(r.Context(), req.Email, user); err != nil {
+		log.Ctx(r.Context()).Error().Err(err).Str("email", req.Email).Msg("Gagal memperbarui password")
+		writeServerError(w, err, `{"message":"Gagal memperbarui kata sandi."}`)
 		return
 	}
 
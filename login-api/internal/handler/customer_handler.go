@@ -0,0 +1,165 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"login-api/internal/model"
+	"login-api/internal/storage"
+	"login-api/internal/storage/postgres"
+	"login-api/internal/validator"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+type CustomerHandler struct {
+	Store        *postgres.PostgresCustomerStore
+	PaymentStore storage.PaymentStore
+}
+
+func NewCustomerHandler(store *postgres.PostgresCustomerStore, paymentStore storage.PaymentStore) *CustomerHandler {
+	return &CustomerHandler{Store: store, PaymentStore: paymentStore}
+}
+
+// ListCustomersHandler menangani permintaan daftar pelanggan beserta total pembayarannya.
+func (h *CustomerHandler) ListCustomersHandler(w http.ResponseWriter, r *http.Request) {
+	customers, err := h.Store.ListCustomers(r.Context())
+	if err != nil {
+		writeServerError(w, err, `{"message":"Gagal mengambil data pelanggan."}`)
+		return
+	}
+	if customers == nil {
+		customers = []model.CustomerWithTotals{}
+	}
+
+	writeJSON(w, http.StatusOK, customers)
+}
+
+// CreateCustomerHandler menangani permintaan pembuatan pelanggan baru.
+func (h *CustomerHandler) CreateCustomerHandler(w http.ResponseWriter, r *http.Request) {
+	customer, ok := decodeCustomer(w, r)
+	if !ok {
+		return
+	}
+
+	created, err := h.Store.CreateCustomer(r.Context(), customer)
+	if err != nil {
+		writeCustomerStoreError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, created)
+}
+
+// UpdateCustomerHandler menangani permintaan perubahan data pelanggan.
+func (h *CustomerHandler) UpdateCustomerHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, `{"message":"ID pelanggan tidak valid."}`, http.StatusBadRequest)
+		return
+	}
+
+	customer, ok := decodeCustomer(w, r)
+	if !ok {
+		return
+	}
+	customer.ID = id
+
+	updated, err := h.Store.UpdateCustomer(r.Context(), customer)
+	if err != nil {
+		writeCustomerStoreError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, updated)
+}
+
+// MergeCustomersHandler menggabungkan pelanggan duplikat ke pelanggan pada URL.
+func (h *CustomerHandler) MergeCustomersHandler(w http.ResponseWriter, r *http.Request) {
+	targetID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, `{"message":"ID pelanggan tidak valid."}`, http.StatusBadRequest)
+		return
+	}
+
+	var req model.MergeCustomersRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"message":"Format permintaan tidak sesuai."}`, http.StatusBadRequest)
+		return
+	}
+	if len(req.SourceIDs) == 0 {
+		http.Error(w, `{"message":"Daftar pelanggan yang akan digabung tidak boleh kosong."}`, http.StatusBadRequest)
+		return
+	}
+
+	merged, err := h.Store.MergeCustomers(r.Context(), targetID, req.SourceIDs)
+	if err != nil {
+		writeCustomerStoreError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, merged)
+}
+
+// GetCustomerPaymentsHandler mengambil semua pembayaran milik satu pelanggan.
+func (h *CustomerHandler) GetCustomerPaymentsHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, `{"message":"ID pelanggan tidak valid."}`, http.StatusBadRequest)
+		return
+	}
+
+	if _, err := h.Store.GetCustomerByID(r.Context(), id); err != nil {
+		writeCustomerStoreError(w, err)
+		return
+	}
+
+	payments, err := h.PaymentStore.GetPaymentsByCustomer(r.Context(), id)
+	if err != nil {
+		writeServerError(w, err, `{"message":"Gagal mengambil data pembayaran."}`)
+		return
+	}
+	if payments == nil {
+		payments = []model.Payment{}
+	}
+
+	writeJSON(w, http.StatusOK, payments)
+}
+
+func decodeCustomer(w http.ResponseWriter, r *http.Request) (model.Customer, bool) {
+	var c model.Customer
+	if err := json.NewDecoder(r.Body).Decode(&c); err != nil {
+		http.Error(w, `{"message":"Format permintaan tidak sesuai."}`, http.StatusBadRequest)
+		return model.Customer{}, false
+	}
+
+	c.Name = strings.Join(strings.Fields(c.Name), " ")
+	c.Email = strings.TrimSpace(c.Email)
+	c.Phone = strings.TrimSpace(c.Phone)
+
+	if c.Name == "" {
+		http.Error(w, `{"message":"Nama pelanggan wajib diisi."}`, http.StatusBadRequest)
+		return model.Customer{}, false
+	}
+	if c.Email != "" {
+		if err := validator.ValidateEmail(c.Email); err != nil {
+			writeJSON(w, http.StatusBadRequest, model.Response{Message: err.Error(), Success: false})
+			return model.Customer{}, false
+		}
+	}
+
+	return c, true
+}
+
+func writeCustomerStoreError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, storage.ErrNotFound):
+		http.Error(w, `{"message":"Pelanggan tidak ditemukan."}`, http.StatusNotFound)
+	case errors.Is(err, storage.ErrDuplicate):
+		http.Error(w, `{"message":"Pelanggan dengan nama tersebut sudah ada."}`, http.StatusConflict)
+	default:
+		writeServerError(w, err, `{"message":"Gagal menyimpan data pelanggan."}`)
+	}
+}
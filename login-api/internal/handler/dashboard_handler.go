@@ -2,46 +2,182 @@ package handler
 
 import (
 	"encoding/json"
-	"login-api/internal/storage/postgres"
+	"errors"
+	"login-api/internal/model"
+	"login-api/internal/service"
 	"net/http"
+	"strconv"
 
 	"github.com/rs/zerolog/log"
 )
 
 type DashboardHandler struct {
-	Store *postgres.PostgresPaymentStore
+	Svc   *service.DashboardService
+	Prefs *service.PreferenceService
 }
 
-func NewDashboardHandler(store *postgres.PostgresPaymentStore) *DashboardHandler {
-	return &DashboardHandler{Store: store}
+func NewDashboardHandler(svc *service.DashboardService, prefs *service.PreferenceService) *DashboardHandler {
+	return &DashboardHandler{Svc: svc, Prefs: prefs}
 }
 
-// GetSummaryHandler menangani permintaan untuk data ringkasan dashboard.
+// GetSummaryHandler menangani permintaan untuk data ringkasan dashboard. Tanpa
+// query period, ringkasan mencakup seluruh data. Dengan period (today, week,
+// month, atau custom beserta from dan to), respons berisi ringkasan periode itu,
+// periode sebelumnya yang setara, dan perubahannya.
 func (h *DashboardHandler) GetSummaryHandler(w http.ResponseWriter, r *http.Request) {
-	summary, err := h.Store.GetDashboardSummary()
+	q := r.URL.Query()
+	if period := q.Get("period"); period != "" {
+		loc, ok := requestLocation(w, r, h.Prefs)
+		if !ok {
+			return
+		}
+
+		comparison, err := h.Svc.SummaryComparison(r.Context(), period, q.Get("from"), q.Get("to"), loc)
+		if err != nil {
+			var validationErr *service.ValidationError
+			if errors.As(err, &validationErr) {
+				writeJSON(w, http.StatusBadRequest, model.Response{Message: validationErr.Message, Success: false})
+				return
+			}
+			writeServerError(w, err, `{"message":"Gagal mengambil data ringkasan."}`)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, comparison)
+		return
+	}
+
+	summary, err := h.Svc.Summary(r.Context())
 	if err != nil {
-		http.Error(w, `{"message":"Gagal mengambil data ringkasan."}`, http.StatusInternalServerError)
+		writeServerError(w, err, `{"message":"Gagal mengambil data ringkasan."}`)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	if err := json.NewEncoder(w).Encode(summary); err != nil {
-		log.Error().Err(err).Msg("Gagal melakukan encode response ringkasan")
+		log.Ctx(r.Context()).Error().Err(err).Msg("Gagal melakukan encode response ringkasan")
 	}
 }
 
-// GetChartDataHandler menangani permintaan untuk data grafik.
+// GetChartDataHandler menangani permintaan untuk data grafik. Query opsional:
+// from, to (YYYY-MM-DD atau RFC 3339), interval (hour, day, week, month), dan
+// tz (zona waktu IANA; bawaannya preferensi pengguna).
 func (h *DashboardHandler) GetChartDataHandler(w http.ResponseWriter, r *http.Request) {
-	chartData, err := h.Store.GetChartData()
+	loc, ok := requestLocation(w, r, h.Prefs)
+	if !ok {
+		return
+	}
+
+	q := r.URL.Query()
+	chartData, err := h.Svc.ChartData(r.Context(), q.Get("from"), q.Get("to"), q.Get("interval"), loc)
 	if err != nil {
-		http.Error(w, `{"message":"Gagal mengambil data grafik."}`, http.StatusInternalServerError)
+		var validationErr *service.ValidationError
+		if errors.As(err, &validationErr) {
+			writeJSON(w, http.StatusBadRequest, model.Response{Message: validationErr.Message, Success: false})
+			return
+		}
+		writeServerError(w, err, `{"message":"Gagal mengambil data grafik."}`)
 		return
 	}
+	if chartData == nil {
+		chartData = []model.ChartData{}
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	if err := json.NewEncoder(w).Encode(chartData); err != nil {
-		log.Error().Err(err).Msg("Gagal melakukan encode response data grafik")
+		log.Ctx(r.Context()).Error().Err(err).Msg("Gagal melakukan encode response data grafik")
+	}
+}
+
+// GetStatusBreakdownHandler menampilkan jumlah dan nilai pembayaran per status.
+// Query opsional: from, to, dan tz seperti pada data grafik.
+func (h *DashboardHandler) GetStatusBreakdownHandler(w http.ResponseWriter, r *http.Request) {
+	loc, ok := requestLocation(w, r, h.Prefs)
+	if !ok {
+		return
+	}
+
+	q := r.URL.Query()
+	breakdown, err := h.Svc.StatusBreakdown(r.Context(), q.Get("from"), q.Get("to"), loc)
+	if err != nil {
+		writeDashboardError(w, err, `{"message":"Gagal mengambil rincian status pembayaran."}`)
+		return
+	}
+	if breakdown == nil {
+		breakdown = []model.StatusBreakdown{}
 	}
-}
\ No newline at end of file
+
+	writeJSON(w, http.StatusOK, breakdown)
+}
+
+// GetTopCustomersHandler menampilkan pelanggan dengan pendapatan lunas terbesar.
+// Query opsional: limit (bawaan 10), from, to, dan tz.
+func (h *DashboardHandler) GetTopCustomersHandler(w http.ResponseWriter, r *http.Request) {
+	loc, ok := requestLocation(w, r, h.Prefs)
+	if !ok {
+		return
+	}
+
+	q := r.URL.Query()
+	limit := 10
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			http.Error(w, `{"message":"Parameter limit tidak valid."}`, http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+
+	breakdown, err := h.Svc.TopCustomers(r.Context(), q.Get("from"), q.Get("to"), limit, loc)
+	if err != nil {
+		writeDashboardError(w, err, `{"message":"Gagal mengambil data pelanggan teratas."}`)
+		return
+	}
+	if breakdown == nil {
+		breakdown = []model.CustomerBreakdown{}
+	}
+
+	writeJSON(w, http.StatusOK, breakdown)
+}
+
+// GetForecastHandler menampilkan proyeksi pendapatan lunas. Query opsional:
+// interval (day atau week), periods (jumlah periode), dan tz.
+func (h *DashboardHandler) GetForecastHandler(w http.ResponseWriter, r *http.Request) {
+	loc, ok := requestLocation(w, r, h.Prefs)
+	if !ok {
+		return
+	}
+
+	q := r.URL.Query()
+	periods := 0
+	if v := q.Get("periods"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			http.Error(w, `{"message":"Parameter periods tidak valid."}`, http.StatusBadRequest)
+			return
+		}
+		periods = n
+	}
+
+	result, err := h.Svc.Forecast(r.Context(), q.Get("interval"), periods, loc)
+	if err != nil {
+		writeDashboardError(w, err, `{"message":"Gagal menghitung proyeksi pendapatan."}`)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+// writeDashboardError menulis 400 untuk kesalahan validasi dan meneruskan
+// kesalahan lainnya ke writeServerError dengan body fallback.
+func writeDashboardError(w http.ResponseWriter, err error, fallback string) {
+	var validationErr *service.ValidationError
+	if errors.As(err, &validationErr) {
+		writeJSON(w, http.StatusBadRequest, model.Response{Message: validationErr.Message, Success: false})
+		return
+	}
+	writeServerError(w, err, fallback)
+}
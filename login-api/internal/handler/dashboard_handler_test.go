@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"login-api/internal/model"
+	"login-api/internal/service"
+	"login-api/internal/storage"
+	"login-api/internal/storage/memory"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// failingDashboardStore mengembalikan err untuk semua agregat dashboard.
+type failingDashboardStore struct {
+	storage.PaymentStore
+	err error
+}
+
+func (s failingDashboardStore) GetDashboardSummary(context.Context) (model.DashboardSummary, error) {
+	return model.DashboardSummary{}, s.err
+}
+
+func (s failingDashboardStore) GetChartData(context.Context, model.ChartRange) ([]model.ChartData, error) {
+	return nil, s.err
+}
+
+func (s failingDashboardStore) GetStatusBreakdown(context.Context, *time.Time, *time.Time) ([]model.StatusBreakdown, error) {
+	return nil, s.err
+}
+
+func (s failingDashboardStore) GetTopCustomers(context.Context, *time.Time, *time.Time, int) ([]model.CustomerBreakdown, error) {
+	return nil, s.err
+}
+
+func TestDashboardHandlerServerErrors(t *testing.T) {
+	endpoints := []struct {
+		name    string
+		target  string
+		handler func(h *DashboardHandler) http.HandlerFunc
+	}{
+		{"ringkasan", "/api/dashboard/summary", func(h *DashboardHandler) http.HandlerFunc { return h.GetSummaryHandler }},
+		{"grafik", "/api/dashboard/chart", func(h *DashboardHandler) http.HandlerFunc { return h.GetChartDataHandler }},
+		{"rincian status", "/api/dashboard/breakdown/status", func(h *DashboardHandler) http.HandlerFunc { return h.GetStatusBreakdownHandler }},
+		{"pelanggan teratas", "/api/dashboard/breakdown/customers", func(h *DashboardHandler) http.HandlerFunc { return h.GetTopCustomersHandler }},
+		{"proyeksi", "/api/dashboard/forecast", func(h *DashboardHandler) http.HandlerFunc { return h.GetForecastHandler }},
+	}
+	errs := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"tenggat", fmt.Errorf("query: %w", context.DeadlineExceeded), http.StatusGatewayTimeout},
+		{"tidak tersedia", fmt.Errorf("%w: dial", storage.ErrUnavailable), http.StatusServiceUnavailable},
+		{"lainnya", fmt.Errorf("syntax error"), http.StatusInternalServerError},
+	}
+
+	prefs := service.NewPreferenceService(memory.NewMemoryUserStore(), time.UTC)
+	for _, e := range endpoints {
+		for _, tt := range errs {
+			t.Run(e.name+"/"+tt.name, func(t *testing.T) {
+				svc := service.NewDashboardService(failingDashboardStore{err: tt.err}, 0)
+				h := NewDashboardHandler(svc, prefs)
+
+				rec := httptest.NewRecorder()
+				e.handler(h)(rec, httptest.NewRequest(http.MethodGet, e.target, nil))
+				if rec.Code != tt.want {
+					t.Errorf("status = %d, want %d", rec.Code, tt.want)
+				}
+			})
+		}
+	}
+}
+
+func TestDashboardHandlerValidationError(t *testing.T) {
+	prefs := service.NewPreferenceService(memory.NewMemoryUserStore(), time.UTC)
+	h := NewDashboardHandler(service.NewDashboardService(memory.NewMemoryPaymentStore(nil), 0), prefs)
+
+	rec := httptest.NewRecorder()
+	h.GetTopCustomersHandler(rec, httptest.NewRequest(http.MethodGet, "/api/dashboard/breakdown/customers?limit=0", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"login-api/internal/gateway"
+	"login-api/internal/model"
+	"login-api/internal/service"
+	"login-api/internal/storage"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/rs/zerolog/log"
+)
+
+// maxWebhookBodySize membatasi ukuran body webhook yang dibaca.
+const maxWebhookBodySize = 1 << 20
+
+type GatewayHandler struct {
+	Svc *service.GatewayService
+}
+
+func NewGatewayHandler(svc *service.GatewayService) *GatewayHandler {
+	return &GatewayHandler{Svc: svc}
+}
+
+// WebhookHandler menerima webhook dari penyedia pembayaran. Respons 2xx memberi tahu
+// penyedia agar tidak mengirim ulang; 5xx memintanya mencoba lagi nanti.
+func (h *GatewayHandler) WebhookHandler(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBodySize))
+	if err != nil {
+		http.Error(w, `{"message":"Body webhook tidak dapat dibaca."}`, http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.Svc.HandleWebhook(r.Context(), body, r.Header.Get(gateway.SignatureHeader))
+	if err != nil {
+		var validationErr *service.ValidationError
+		switch {
+		case errors.Is(err, service.ErrGatewayNotConfigured):
+			http.Error(w, `{"message":"Webhook gateway belum dikonfigurasi."}`, http.StatusServiceUnavailable)
+		case errors.Is(err, gateway.ErrInvalidSignature), errors.Is(err, gateway.ErrTimestampOutOfRange):
+			log.Ctx(r.Context()).Warn().Err(err).Str("remote_addr", r.RemoteAddr).Msg("Webhook gateway ditolak")
+			writeJSON(w, http.StatusUnauthorized, model.Response{Message: err.Error(), Success: false})
+		case errors.As(err, &validationErr):
+			writeJSON(w, http.StatusBadRequest, model.Response{Message: validationErr.Message, Success: false})
+		default:
+			log.Ctx(r.Context()).Error().Err(err).Msg("Gagal memproses webhook gateway")
+			writeServerError(w, err, `{"message":"Gagal memproses webhook."}`)
+		}
+		return
+	}
+
+	if result.Duplicate {
+		writeJSON(w, http.StatusOK, model.Response{Message: "Event sudah pernah diterima.", Success: true})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result.Event)
+}
+
+// ListEventsHandler menampilkan event gateway terbaru.
+func (h *GatewayHandler) ListEventsHandler(w http.ResponseWriter, r *http.Request) {
+	limit := 100
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > 1000 {
+			http.Error(w, `{"message":"Parameter limit harus antara 1 dan 1000."}`, http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+
+	events, err := h.Svc.Store.ListEvents(r.Context(), limit)
+	if err != nil {
+		writeServerError(w, err, `{"message":"Gagal mengambil event gateway."}`)
+		return
+	}
+	if events == nil {
+		events = []model.GatewayEvent{}
+	}
+
+	writeJSON(w, http.StatusOK, events)
+}
+
+// ReplayEventHandler menerapkan ulang event gateway yang tersimpan.
+func (h *GatewayHandler) ReplayEventHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, `{"message":"ID event tidak valid."}`, http.StatusBadRequest)
+		return
+	}
+
+	ev, err := h.Svc.ReplayEvent(r.Context(), id)
+	if err != nil {
+		var validationErr *service.ValidationError
+		switch {
+		case errors.Is(err, storage.ErrNotFound):
+			http.Error(w, `{"message":"Event tidak ditemukan."}`, http.StatusNotFound)
+		case errors.As(err, &validationErr):
+			writeJSON(w, http.StatusUnprocessableEntity, model.Response{Message: validationErr.Message, Success: false})
+		default:
+			log.Ctx(r.Context()).Error().Err(err).Int("event_id", id).Msg("Gagal memutar ulang event gateway")
+			writeServerError(w, err, `{"message":"Gagal memutar ulang event."}`)
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, ev)
+}
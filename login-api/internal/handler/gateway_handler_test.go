@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"login-api/internal/gateway"
+	"login-api/internal/model"
+	"login-api/internal/service"
+	"login-api/internal/storage"
+	"login-api/internal/storage/postgres"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// memoryGatewayEventStore menyimpan event gateway di memori dan menolak
+// provider_event_id yang sama seperti constraint unik di Postgres.
+type memoryGatewayEventStore struct {
+	events  []model.GatewayEvent
+	applied []postgres.GatewayTransition
+}
+
+func (s *memoryGatewayEventStore) RecordEvent(_ context.Context, ev model.GatewayEvent, t postgres.GatewayTransition) (postgres.GatewayEventResult, error) {
+	for _, stored := range s.events {
+		if stored.ProviderEventID == ev.ProviderEventID {
+			return postgres.GatewayEventResult{Duplicate: true}, nil
+		}
+	}
+	ev.ID = len(s.events) + 1
+	ev.Status = model.GatewayEventProcessed
+	s.events = append(s.events, ev)
+	s.applied = append(s.applied, t)
+	return postgres.GatewayEventResult{Event: ev}, nil
+}
+
+func (s *memoryGatewayEventStore) ReplayEvent(_ context.Context, id int, t postgres.GatewayTransition) (postgres.GatewayEventResult, error) {
+	if id < 1 || id > len(s.events) {
+		return postgres.GatewayEventResult{}, storage.ErrNotFound
+	}
+	s.applied = append(s.applied, t)
+	return postgres.GatewayEventResult{Event: s.events[id-1]}, nil
+}
+
+func (s *memoryGatewayEventStore) ListEvents(context.Context, int) ([]model.GatewayEvent, error) {
+	return s.events, nil
+}
+
+func (s *memoryGatewayEventStore) GetEvent(_ context.Context, id int) (model.GatewayEvent, error) {
+	if id < 1 || id > len(s.events) {
+		return model.GatewayEvent{}, storage.ErrNotFound
+	}
+	return s.events[id-1], nil
+}
+
+func TestGatewayWebhookHandler(t *testing.T) {
+	secret := []byte("rahasia-webhook")
+	store := &memoryGatewayEventStore{}
+	h := NewGatewayHandler(service.NewGatewayService(store, secret, 5*time.Minute))
+	server := httptest.NewServer(http.HandlerFunc(h.WebhookHandler))
+	defer server.Close()
+
+	fake := gateway.NewFakeGateway(secret)
+	fake.Client = server.Client()
+	ev := fake.NewEvent(gateway.EventPaymentSucceeded, 7, 150000)
+
+	deliver := func(g *gateway.FakeGateway, ev gateway.Event) (int, map[string]interface{}) {
+		t.Helper()
+		resp, err := g.Deliver(server.URL, ev)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		var body map[string]interface{}
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+		return resp.StatusCode, body
+	}
+
+	code, body := deliver(fake, ev)
+	if code != http.StatusOK || body["provider_event_id"] != ev.ID {
+		t.Fatalf("pengiriman pertama = %d %v, want 200 dengan event %s", code, body, ev.ID)
+	}
+
+	// Penyedia mengirim ulang event yang sama, misalnya karena respons pertama hilang.
+	code, body = deliver(fake, ev)
+	if code != http.StatusOK || body["message"] != "Event sudah pernah diterima." {
+		t.Errorf("pengiriman ulang = %d %v, want 200 duplikat", code, body)
+	}
+	if len(store.events) != 1 || len(store.applied) != 1 {
+		t.Fatalf("event tersimpan = %d, diterapkan = %d, want masing-masing 1", len(store.events), len(store.applied))
+	}
+	if got := store.applied[0]; got.TargetStatus != model.PaymentStatusPaid || got.Amount == nil || *got.Amount != 150000 {
+		t.Errorf("transisi = %+v, want Lunas sebesar 150000", got)
+	}
+
+	t.Run("ditolak", func(t *testing.T) {
+		stale := gateway.NewFakeGateway(secret)
+		stale.Client = server.Client()
+		stale.Now = func() time.Time { return time.Now().Add(-time.Hour) }
+
+		tests := []struct {
+			name string
+			g    *gateway.FakeGateway
+		}{
+			{"secret berbeda", gateway.NewFakeGateway([]byte("secret-lain"))},
+			{"timestamp kedaluwarsa", stale},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				if code, body := deliver(tt.g, tt.g.NewEvent(gateway.EventPaymentSucceeded, 8, 1000)); code != http.StatusUnauthorized {
+					t.Errorf("status = %d %v, want %d", code, body, http.StatusUnauthorized)
+				}
+			})
+		}
+		if len(store.events) != 1 {
+			t.Errorf("event tersimpan = %d, want webhook yang ditolak tidak disimpan", len(store.events))
+		}
+	})
+}
@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"login-api/internal/document"
+	"login-api/internal/model"
+	"login-api/internal/storage"
+	"login-api/internal/storage/postgres"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/rs/zerolog/log"
+)
+
+type InvoiceHandler struct {
+	PaymentStore   storage.PaymentStore
+	InvoiceStore   *postgres.PostgresInvoiceStore
+	OrganizationID string
+	Company        model.Company
+}
+
+func NewInvoiceHandler(paymentStore storage.PaymentStore, invoiceStore *postgres.PostgresInvoiceStore, orgID string, company model.Company) *InvoiceHandler {
+	return &InvoiceHandler{
+		PaymentStore:   paymentStore,
+		InvoiceStore:   invoiceStore,
+		OrganizationID: orgID,
+		Company:        company,
+	}
+}
+
+// GetInvoicePDFHandler menerbitkan (bila belum ada) dan mengirimkan faktur/kwitansi PDF untuk satu pembayaran.
+func (h *InvoiceHandler) GetInvoicePDFHandler(w http.ResponseWriter, r *http.Request) {
+	paymentID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, `{"message":"ID pembayaran tidak valid."}`, http.StatusBadRequest)
+		return
+	}
+
+	payment, err := h.PaymentStore.GetPaymentByID(r.Context(), paymentID)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			http.Error(w, `{"message":"Pembayaran tidak ditemukan."}`, http.StatusNotFound)
+			return
+		}
+		writeServerError(w, err, `{"message":"Gagal mengambil data pembayaran."}`)
+		return
+	}
+
+	invoice, err := h.InvoiceStore.GetOrCreateInvoice(r.Context(), h.OrganizationID, payment.ID)
+	if errors.Is(err, storage.ErrNotFound) {
+		http.Error(w, `{"message":"Pembayaran tidak ditemukan."}`, http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Ctx(r.Context()).Error().Err(err).Int("payment_id", payment.ID).Msg("Gagal menerbitkan faktur")
+		writeServerError(w, err, `{"message":"Gagal menerbitkan faktur."}`)
+		return
+	}
+
+	data := document.InvoiceData{Company: h.Company, Invoice: invoice, Payment: payment}
+
+	// Render ke buffer lebih dulu agar kegagalan masih bisa dilaporkan sebagai error JSON.
+	var buf bytes.Buffer
+	if err := document.RenderInvoicePDF(&buf, data); err != nil {
+		log.Ctx(r.Context()).Error().Err(err).Str("invoice_number", invoice.InvoiceNumber).Msg("Gagal membuat PDF faktur")
+		writeServerError(w, err, `{"message":"Gagal membuat dokumen PDF."}`)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, data.FileName()))
+	w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+	w.WriteHeader(http.StatusOK)
+	if _, err := buf.WriteTo(w); err != nil {
+		log.Ctx(r.Context()).Error().Err(err).Msg("Gagal mengirim PDF faktur")
+	}
+}
@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"login-api/internal/metrics"
+	"login-api/internal/middleware"
+	"login-api/internal/service"
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+type LiveHandler struct {
+	Svc *service.LiveService
+}
+
+func NewLiveHandler(svc *service.LiveService) *LiveHandler {
+	return &LiveHandler{Svc: svc}
+}
+
+// StreamDashboardHandler mengirim perubahan dashboard sebagai Server-Sent Events.
+// Klien baru menerima snapshot ringkasan lalu event dashboard.delta; klien yang
+// tersambung kembali dengan header Last-Event-ID (atau query last_event_id)
+// menerima event yang terlewat, atau snapshot baru bila event tersebut sudah
+// tidak tersedia. Stream ditutup dengan event auth.expired saat token akses
+// kedaluwarsa, sehingga sesi yang dicabut atau akun yang dinonaktifkan tidak
+// terus menerima data setelah token berikutnya ditolak oleh /refresh.
+func (h *LiveHandler) StreamDashboardHandler(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, `{"message":"Streaming tidak didukung."}`, http.StatusInternalServerError)
+		return
+	}
+
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		// EventSource yang dibuka ulang klien setelah memperbarui token tidak dapat
+		// mengirim header, sehingga ID terakhir dikirim lewat query.
+		lastEventID = r.URL.Query().Get("last_event_id")
+	}
+	tenant := h.Svc.OrganizationID
+	events, replay, resync, cancel := h.Svc.Subscribe(tenant, lastEventID)
+	defer cancel()
+	metrics.OpenStreams.Inc()
+	defer metrics.OpenStreams.Dec()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, "retry: 3000\n\n")
+
+	// Snapshot diambil setelah berlangganan agar tidak ada perubahan yang
+	// terlewat; event yang sudah tercakup di dalamnya dilewati di bawah.
+	var snapshotID string
+	if resync {
+		summary, id, err := h.Svc.Snapshot(r.Context(), tenant)
+		if err != nil {
+			// Klien tetap menerima delta dan dapat memuat ringkasan lewat endpoint biasa.
+			log.Ctx(r.Context()).Error().Err(err).Msg("Gagal mengambil snapshot ringkasan untuk stream dashboard")
+		} else if err := writeEvent(w, service.LiveEvent{ID: id, Type: service.LiveEventSnapshot}, summary); err != nil {
+			return
+		} else {
+			snapshotID = id
+		}
+	}
+	for _, ev := range replay {
+		if err := writeEvent(w, ev, nil); err != nil {
+			return
+		}
+	}
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(h.Svc.Heartbeat)
+	defer heartbeat.Stop()
+
+	var expired <-chan time.Time
+	if expiresAt, ok := middleware.TokenExpiry(r.Context()); ok {
+		timer := time.NewTimer(time.Until(expiresAt))
+		defer timer.Stop()
+		expired = timer.C
+	}
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case ev, ok := <-events:
+			if !ok {
+				return
+			}
+			if snapshotID != "" && h.Svc.Covers(snapshotID, ev) {
+				continue
+			}
+			if err := writeEvent(w, ev, nil); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-expired:
+			// Tanpa ID agar Last-Event-ID klien tetap menunjuk event data terakhir.
+			fmt.Fprintf(w, "event: %s\ndata: {}\n\n", service.LiveEventAuthExpired)
+			flusher.Flush()
+			return
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// writeEvent menulis satu event SSE. Bila data tidak nil, data di-encode sebagai
+// JSON menggantikan ev.Data.
+func writeEvent(w http.ResponseWriter, ev service.LiveEvent, data interface{}) error {
+	payload := ev.Data
+	if data != nil {
+		var err error
+		if payload, err = json.Marshal(data); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", ev.ID, ev.Type, payload)
+	return err
+}
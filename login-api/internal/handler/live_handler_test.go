@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"login-api/internal/middleware"
+	"login-api/internal/model"
+	"login-api/internal/service"
+	"login-api/internal/storage/memory"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStreamDashboardHandlerClosesWhenTokenExpires(t *testing.T) {
+	store := memory.NewMemoryPaymentStore([]model.Payment{
+		{CustomerName: "Andi", Amount: 100, Status: model.PaymentStatusPaid, PaymentDate: time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)},
+	})
+	live := service.NewLiveService(nil, service.NewDashboardService(store, time.Minute), "org", time.Hour)
+	h := NewLiveHandler(live)
+
+	// ID dari proses lain tidak dikenali, sehingga klien menerima snapshot baru.
+	req := httptest.NewRequest(http.MethodGet, "/api/dashboard/stream?last_event_id=lama-3", nil)
+	req = req.WithContext(middleware.WithTokenExpiry(req.Context(), time.Now().Add(50*time.Millisecond)))
+	rec := httptest.NewRecorder()
+
+	done := make(chan struct{})
+	go func() {
+		h.StreamDashboardHandler(rec, req)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("stream tidak ditutup setelah token kedaluwarsa")
+	}
+
+	body := rec.Body.String()
+	if !strings.Contains(body, "event: "+service.LiveEventSnapshot+"\n") || !strings.Contains(body, `"total_revenue":100`) {
+		t.Errorf("body = %q, want snapshot ringkasan", body)
+	}
+	want := "event: " + service.LiveEventAuthExpired + "\ndata: {}\n\n"
+	if !strings.HasSuffix(body, want) {
+		t.Errorf("body = %q, want diakhiri %q", body, want)
+	}
+}
@@ -2,31 +2,31 @@ package handler
 
 import (
 	"encoding/json"
-	"login-api/internal/storage/postgres"
+	"login-api/internal/storage"
 	"net/http"
 
 	"github.com/rs/zerolog/log"
 )
 
 type PaymentHandler struct {
-	Store *postgres.PostgresPaymentStore
+	Store storage.PaymentStore
 }
 
-func NewPaymentHandler(store *postgres.PostgresPaymentStore) *PaymentHandler {
+func NewPaymentHandler(store storage.PaymentStore) *PaymentHandler {
 	return &PaymentHandler{Store: store}
 }
 
 // GetPaymentsHandler menangani permintaan untuk mengambil data pembayaran.
 func (h *PaymentHandler) GetPaymentsHandler(w http.ResponseWriter, r *http.Request) {
-	payments, err := h.Store.GetPayments()
+	payments, err := h.Store.GetPayments(r.Context())
 	if err != nil {
-		http.Error(w, `{"message":"Gagal mengambil data pembayaran."}`, http.StatusInternalServerError)
+		writeServerError(w, err, `{"message":"Gagal mengambil data pembayaran."}`)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	if err := json.NewEncoder(w).Encode(payments); err != nil {
-		log.Error().Err(err).Msg("Gagal melakukan encode response pembayaran")
+		log.Ctx(r.Context()).Error().Err(err).Msg("Gagal melakukan encode response pembayaran")
 	}
 }
\ No newline at end of file
@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"login-api/internal/model"
+	"login-api/internal/storage"
+	"login-api/internal/storage/memory"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// failingPaymentStore mengembalikan err untuk GetPayments.
+type failingPaymentStore struct {
+	storage.PaymentStore
+	err error
+}
+
+func (s failingPaymentStore) GetPayments(context.Context) ([]model.Payment, error) {
+	return nil, s.err
+}
+
+func TestGetPaymentsHandler(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, time.March, d, 9, 0, 0, 0, time.UTC) }
+	store := memory.NewMemoryPaymentStore([]model.Payment{
+		{CustomerName: "Andi", Amount: 100, Status: model.PaymentStatusPaid, PaymentDate: day(1)},
+		{CustomerName: "Budi", Amount: 200, Status: model.PaymentStatusPending, PaymentDate: day(3)},
+		{CustomerName: "Citra", Amount: 300, Status: model.PaymentStatusPaid, PaymentDate: day(2)},
+	})
+	h := NewPaymentHandler(store)
+
+	rec := httptest.NewRecorder()
+	h.GetPaymentsHandler(rec, httptest.NewRequest(http.MethodGet, "/api/payments", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", ct)
+	}
+
+	var got []model.Payment
+	if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, p := range got {
+		names = append(names, p.CustomerName)
+	}
+	if fmt.Sprint(names) != "[Budi Citra Andi]" {
+		t.Errorf("urutan pembayaran = %v, want terbaru lebih dulu [Budi Citra Andi]", names)
+	}
+	if got[0].ID != 2 || got[0].ReconciliationStatus != model.ReconciliationUnreconciled {
+		t.Errorf("pembayaran pertama = %+v, want ID 2 dengan status rekonsiliasi bawaan", got[0])
+	}
+}
+
+func TestGetPaymentsHandlerErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"tenggat query terlampaui", fmt.Errorf("query: %w", context.DeadlineExceeded), http.StatusGatewayTimeout},
+		{"database tidak tersedia", fmt.Errorf("%w: dial", storage.ErrUnavailable), http.StatusServiceUnavailable},
+		{"permintaan dibatalkan", context.Canceled, http.StatusServiceUnavailable},
+		{"error lain", errors.New("syntax error"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewPaymentHandler(failingPaymentStore{err: tt.err})
+			rec := httptest.NewRecorder()
+			h.GetPaymentsHandler(rec, httptest.NewRequest(http.MethodGet, "/api/payments", nil))
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
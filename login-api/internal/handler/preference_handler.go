@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"login-api/internal/middleware"
+	"login-api/internal/model"
+	"login-api/internal/service"
+	"login-api/internal/storage"
+	"net/http"
+	"time"
+)
+
+type PreferenceHandler struct {
+	Svc *service.PreferenceService
+}
+
+func NewPreferenceHandler(svc *service.PreferenceService) *PreferenceHandler {
+	return &PreferenceHandler{Svc: svc}
+}
+
+type preferencesResponse struct {
+	Timezone string `json:"timezone"`
+}
+
+// GetPreferencesHandler menampilkan preferensi pengguna yang sedang login.
+func (h *PreferenceHandler) GetPreferencesHandler(w http.ResponseWriter, r *http.Request) {
+	email, _ := middleware.UserEmail(r.Context())
+
+	tz, err := h.Svc.Timezone(r.Context(), email)
+	if err != nil {
+		writeServerError(w, err, `{"message":"Gagal mengambil preferensi pengguna."}`)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, preferencesResponse{Timezone: tz})
+}
+
+// UpdatePreferencesHandler menyimpan preferensi pengguna yang sedang login.
+func (h *PreferenceHandler) UpdatePreferencesHandler(w http.ResponseWriter, r *http.Request) {
+	email, ok := middleware.UserEmail(r.Context())
+	if !ok {
+		http.Error(w, `{"message":"Token otentikasi tidak ditemukan."}`, http.StatusUnauthorized)
+		return
+	}
+
+	var req preferencesResponse
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"message":"Format permintaan tidak sesuai."}`, http.StatusBadRequest)
+		return
+	}
+
+	tz, err := h.Svc.SetTimezone(r.Context(), email, req.Timezone)
+	if err != nil {
+		var validationErr *service.ValidationError
+		switch {
+		case errors.As(err, &validationErr):
+			writeJSON(w, http.StatusBadRequest, model.Response{Message: validationErr.Message, Success: false})
+		case errors.Is(err, storage.ErrNotFound):
+			http.Error(w, `{"message":"Pengguna tidak ditemukan."}`, http.StatusNotFound)
+		default:
+			writeServerError(w, err, `{"message":"Gagal menyimpan preferensi pengguna."}`)
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, preferencesResponse{Timezone: tz})
+}
+
+// requestLocation menentukan zona waktu permintaan dari query ?tz= atau preferensi
+// pengguna. Mengembalikan false setelah menulis respons error.
+func requestLocation(w http.ResponseWriter, r *http.Request, prefs *service.PreferenceService) (*time.Location, bool) {
+	email, _ := middleware.UserEmail(r.Context())
+
+	loc, err := prefs.Location(r.Context(), r.URL.Query().Get("tz"), email)
+	if err != nil {
+		var validationErr *service.ValidationError
+		if errors.As(err, &validationErr) {
+			writeJSON(w, http.StatusBadRequest, model.Response{Message: validationErr.Message, Success: false})
+			return nil, false
+		}
+		writeServerError(w, err, `{"message":"Gagal menentukan zona waktu."}`)
+		return nil, false
+	}
+	return loc, true
+}
@@ -0,0 +1,25 @@
+package handler
+
+import (
+	"login-api/internal/service"
+	"net/http"
+)
+
+type ReceivableHandler struct {
+	Svc *service.ReceivableService
+}
+
+func NewReceivableHandler(svc *service.ReceivableService) *ReceivableHandler {
+	return &ReceivableHandler{Svc: svc}
+}
+
+// GetAgingReportHandler menangani permintaan laporan umur piutang (0-30, 31-60, 61-90, 90+ hari).
+func (h *ReceivableHandler) GetAgingReportHandler(w http.ResponseWriter, r *http.Request) {
+	report, err := h.Svc.AgingReport(r.Context())
+	if err != nil {
+		writeServerError(w, err, `{"message":"Gagal mengambil laporan umur piutang."}`)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, report)
+}
@@ -0,0 +1,169 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"login-api/internal/model"
+	"login-api/internal/service"
+	"login-api/internal/storage"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// maxStatementSize membatasi ukuran berkas mutasi yang diunggah.
+const maxStatementSize = 10 << 20
+
+type ReconciliationHandler struct {
+	Svc *service.ReconciliationService
+}
+
+func NewReconciliationHandler(svc *service.ReconciliationService) *ReconciliationHandler {
+	return &ReconciliationHandler{Svc: svc}
+}
+
+// UploadStatementHandler menerima berkas mutasi (multipart, field "file") dan
+// langsung mencocokkannya. Field "format" opsional: csv, mt940, atau camt053.
+func (h *ReconciliationHandler) UploadStatementHandler(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxStatementSize+1<<20)
+	if err := r.ParseMultipartForm(maxStatementSize); err != nil {
+		http.Error(w, `{"message":"Berkas mutasi wajib diunggah sebagai multipart dengan field file (maks. 10 MB)."}`, http.StatusBadRequest)
+		return
+	}
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, `{"message":"Berkas mutasi wajib diunggah pada field file."}`, http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, maxStatementSize+1))
+	if err != nil {
+		http.Error(w, `{"message":"Gagal membaca berkas mutasi."}`, http.StatusBadRequest)
+		return
+	}
+	if len(data) > maxStatementSize {
+		http.Error(w, `{"message":"Ukuran berkas mutasi melebihi 10 MB."}`, http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	statement, err := h.Svc.ImportStatement(r.Context(), header.Filename, data, r.FormValue("format"))
+	if err != nil {
+		writeReconciliationError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, statement)
+}
+
+// ListStatementsHandler menampilkan mutasi yang sudah diunggah beserta ringkasan pencocokannya.
+func (h *ReconciliationHandler) ListStatementsHandler(w http.ResponseWriter, r *http.Request) {
+	statements, err := h.Svc.Store.ListStatements(r.Context(), h.Svc.OrganizationID)
+	if err != nil {
+		writeServerError(w, err, `{"message":"Gagal mengambil data mutasi rekening."}`)
+		return
+	}
+	if statements == nil {
+		statements = []model.BankStatement{}
+	}
+
+	writeJSON(w, http.StatusOK, statements)
+}
+
+// ListLinesHandler menampilkan baris sebuah mutasi; query ?status= menyaring hasil.
+func (h *ReconciliationHandler) ListLinesHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, `{"message":"ID mutasi tidak valid."}`, http.StatusBadRequest)
+		return
+	}
+
+	lines, err := h.Svc.ListLines(r.Context(), id, r.URL.Query().Get("status"))
+	if err != nil {
+		writeReconciliationError(w, err)
+		return
+	}
+	if lines == nil {
+		lines = []model.BankStatementLine{}
+	}
+
+	writeJSON(w, http.StatusOK, lines)
+}
+
+// ReviewQueueHandler menampilkan baris mutasi yang memiliki lebih dari satu
+// kandidat pembayaran dan perlu dipilih manual.
+func (h *ReconciliationHandler) ReviewQueueHandler(w http.ResponseWriter, r *http.Request) {
+	lines, err := h.Svc.Store.ListReviewQueue(r.Context(), h.Svc.OrganizationID)
+	if err != nil {
+		writeServerError(w, err, `{"message":"Gagal mengambil antrean tinjauan."}`)
+		return
+	}
+	if lines == nil {
+		lines = []model.BankStatementLine{}
+	}
+
+	writeJSON(w, http.StatusOK, lines)
+}
+
+// MatchLineHandler mencocokkan baris mutasi dengan pembayaran secara manual.
+func (h *ReconciliationHandler) MatchLineHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, `{"message":"ID baris mutasi tidak valid."}`, http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		PaymentID int `json:"payment_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"message":"Format permintaan tidak sesuai."}`, http.StatusBadRequest)
+		return
+	}
+
+	line, err := h.Svc.MatchLine(r.Context(), id, req.PaymentID)
+	if err != nil {
+		writeReconciliationError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, line)
+}
+
+// UnmatchLineHandler membatalkan kecocokan baris mutasi.
+func (h *ReconciliationHandler) UnmatchLineHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, `{"message":"ID baris mutasi tidak valid."}`, http.StatusBadRequest)
+		return
+	}
+
+	line, err := h.Svc.UnmatchLine(r.Context(), id)
+	if err != nil {
+		writeReconciliationError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, line)
+}
+
+func writeReconciliationError(w http.ResponseWriter, err error) {
+	var validationErr *service.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		writeJSON(w, http.StatusBadRequest, model.Response{Message: validationErr.Message, Success: false})
+	case errors.Is(err, storage.ErrNotFound):
+		http.Error(w, `{"message":"Mutasi, baris mutasi, atau pembayaran tidak ditemukan."}`, http.StatusNotFound)
+	case errors.Is(err, storage.ErrDuplicate):
+		http.Error(w, `{"message":"Berkas mutasi ini sudah pernah diunggah."}`, http.StatusConflict)
+	case errors.Is(err, storage.ErrConflict):
+		http.Error(w, `{"message":"Baris mutasi atau pembayaran sudah direkonsiliasi."}`, http.StatusConflict)
+	case errors.Is(err, storage.ErrMismatch):
+		http.Error(w, `{"message":"Nominal atau tanggal pembayaran tidak sesuai dengan baris mutasi."}`, http.StatusUnprocessableEntity)
+	default:
+		writeServerError(w, err, `{"message":"Gagal memproses rekonsiliasi."}`)
+	}
+}
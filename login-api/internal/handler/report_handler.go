@@ -0,0 +1,150 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"login-api/internal/middleware"
+	"login-api/internal/model"
+	"login-api/internal/service"
+	"login-api/internal/storage"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+type ReportHandler struct {
+	Svc *service.ReportService
+}
+
+func NewReportHandler(svc *service.ReportService) *ReportHandler {
+	return &ReportHandler{Svc: svc}
+}
+
+type reportSubscriptionRequest struct {
+	Period   string `json:"period"`
+	Schedule string `json:"schedule"`
+	Timezone string `json:"timezone"`
+	Active   *bool  `json:"active"`
+}
+
+// ListReportSubscriptionsHandler menampilkan langganan laporan milik pengguna yang sedang login.
+func (h *ReportHandler) ListReportSubscriptionsHandler(w http.ResponseWriter, r *http.Request) {
+	email, _ := middleware.UserEmail(r.Context())
+
+	subs, err := h.Svc.Store.ListSubscriptions(r.Context(), email)
+	if err != nil {
+		writeServerError(w, err, `{"message":"Gagal mengambil data langganan laporan."}`)
+		return
+	}
+	if subs == nil {
+		subs = []model.ReportSubscription{}
+	}
+
+	writeJSON(w, http.StatusOK, subs)
+}
+
+// CreateReportSubscriptionHandler membuat langganan laporan untuk pengguna yang
+// sedang login. Laporan dikirim ke email akun pengguna.
+func (h *ReportHandler) CreateReportSubscriptionHandler(w http.ResponseWriter, r *http.Request) {
+	email, ok := middleware.UserEmail(r.Context())
+	if !ok {
+		http.Error(w, `{"message":"Token otentikasi tidak ditemukan."}`, http.StatusUnauthorized)
+		return
+	}
+
+	var req reportSubscriptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"message":"Format permintaan tidak sesuai."}`, http.StatusBadRequest)
+		return
+	}
+
+	sub := model.ReportSubscription{Email: email, Period: req.Period, Schedule: req.Schedule, Timezone: req.Timezone, Active: true}
+	if req.Active != nil {
+		sub.Active = *req.Active
+	}
+
+	created, err := h.Svc.CreateSubscription(r.Context(), sub)
+	if err != nil {
+		writeReportError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, created)
+}
+
+// UpdateReportSubscriptionHandler memperbarui langganan laporan. Kolom yang tidak
+// diisi tetap memakai nilai sebelumnya.
+func (h *ReportHandler) UpdateReportSubscriptionHandler(w http.ResponseWriter, r *http.Request) {
+	email, _ := middleware.UserEmail(r.Context())
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, `{"message":"ID langganan laporan tidak valid."}`, http.StatusBadRequest)
+		return
+	}
+
+	var req reportSubscriptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"message":"Format permintaan tidak sesuai."}`, http.StatusBadRequest)
+		return
+	}
+
+	sub, err := h.Svc.Store.GetSubscription(r.Context(), email, id)
+	if err != nil {
+		writeReportError(w, err)
+		return
+	}
+	if req.Period != "" && req.Period != sub.Period {
+		sub.Period = req.Period
+		// Jadwal lama mengikuti periode lama; pakai bawaan periode baru bila tidak diisi.
+		sub.Schedule = ""
+	}
+	if req.Schedule != "" {
+		sub.Schedule = req.Schedule
+	}
+	if req.Timezone != "" {
+		sub.Timezone = req.Timezone
+	}
+	if req.Active != nil {
+		sub.Active = *req.Active
+	}
+
+	updated, err := h.Svc.UpdateSubscription(r.Context(), sub)
+	if err != nil {
+		writeReportError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, updated)
+}
+
+// DeleteReportSubscriptionHandler menghapus langganan laporan.
+func (h *ReportHandler) DeleteReportSubscriptionHandler(w http.ResponseWriter, r *http.Request) {
+	email, _ := middleware.UserEmail(r.Context())
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, `{"message":"ID langganan laporan tidak valid."}`, http.StatusBadRequest)
+		return
+	}
+
+	if err := h.Svc.Store.DeleteSubscription(r.Context(), email, id); err != nil {
+		writeReportError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, model.Response{Message: "Langganan laporan berhasil dihapus.", Success: true})
+}
+
+func writeReportError(w http.ResponseWriter, err error) {
+	var validationErr *service.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		writeJSON(w, http.StatusBadRequest, model.Response{Message: validationErr.Message, Success: false})
+	case errors.Is(err, storage.ErrNotFound):
+		http.Error(w, `{"message":"Langganan laporan tidak ditemukan."}`, http.StatusNotFound)
+	default:
+		writeServerError(w, err, `{"message":"Gagal memproses langganan laporan."}`)
+	}
+}
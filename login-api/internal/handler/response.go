@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"login-api/internal/storage"
+	"net/http"
+
+	"github.com/rs/zerolog/log"
+)
+
+// writeJSON menulis v sebagai respons JSON dengan kode status yang diberikan.
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Error().Err(err).Msg("Gagal melakukan encode response")
+	}
+}
+
+// writeServerError menulis respons untuk error yang bukan kesalahan klien.
+// Tenggat query yang terlampaui menjadi 504 dan database yang tidak dapat
+// dihubungi atau permintaan yang dibatalkan menjadi 503 agar klien tahu
+// permintaan boleh diulang. Error lain menjadi 500 dengan body message.
+func writeServerError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case !isUnavailable(err):
+		http.Error(w, message, http.StatusInternalServerError)
+	case errors.Is(err, context.DeadlineExceeded):
+		http.Error(w, `{"message":"Waktu pemrosesan permintaan habis. Silakan coba lagi."}`, http.StatusGatewayTimeout)
+	default:
+		http.Error(w, `{"message":"Layanan sedang tidak tersedia. Silakan coba lagi."}`, http.StatusServiceUnavailable)
+	}
+}
+
+// isUnavailable melaporkan apakah err berasal dari database yang lambat atau
+// tidak dapat dihubungi, bukan dari permintaan klien.
+func isUnavailable(err error) bool {
+	return errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) || errors.Is(err, storage.ErrUnavailable)
+}
@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"login-api/internal/model"
+	"login-api/internal/service"
+	"login-api/internal/storage"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+type SubscriptionHandler struct {
+	Svc   *service.SubscriptionService
+	Prefs *service.PreferenceService
+}
+
+func NewSubscriptionHandler(svc *service.SubscriptionService, prefs *service.PreferenceService) *SubscriptionHandler {
+	return &SubscriptionHandler{Svc: svc, Prefs: prefs}
+}
+
+// ListSubscriptionsHandler menangani permintaan daftar langganan.
+func (h *SubscriptionHandler) ListSubscriptionsHandler(w http.ResponseWriter, r *http.Request) {
+	subs, err := h.Svc.Store.ListSubscriptions(r.Context())
+	if err != nil {
+		writeServerError(w, err, `{"message":"Gagal mengambil data langganan."}`)
+		return
+	}
+	if subs == nil {
+		subs = []model.Subscription{}
+	}
+
+	writeJSON(w, http.StatusOK, subs)
+}
+
+// CreateSubscriptionHandler menangani permintaan pembuatan langganan baru.
+func (h *SubscriptionHandler) CreateSubscriptionHandler(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		CustomerID    int     `json:"customer_id"`
+		Description   string  `json:"description"`
+		Amount        float64 `json:"amount"`
+		IntervalUnit  string  `json:"interval_unit"`
+		IntervalCount int     `json:"interval_count"`
+		StartDate     string  `json:"start_date"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"message":"Format permintaan tidak sesuai."}`, http.StatusBadRequest)
+		return
+	}
+
+	sub := model.Subscription{
+		CustomerID:    req.CustomerID,
+		Description:   req.Description,
+		Amount:        req.Amount,
+		IntervalUnit:  req.IntervalUnit,
+		IntervalCount: req.IntervalCount,
+	}
+	if req.StartDate != "" {
+		start, err := time.Parse("2006-01-02", req.StartDate)
+		if err != nil {
+			http.Error(w, `{"message":"Format start_date harus YYYY-MM-DD."}`, http.StatusBadRequest)
+			return
+		}
+		sub.StartDate = start
+	}
+
+	created, err := h.Svc.CreateSubscription(r.Context(), sub)
+	if err != nil {
+		writeSubscriptionError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, created)
+}
+
+// PauseSubscriptionHandler menjeda langganan.
+func (h *SubscriptionHandler) PauseSubscriptionHandler(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, h.Svc.PauseSubscription)
+}
+
+// ResumeSubscriptionHandler mengaktifkan kembali langganan yang dijeda.
+func (h *SubscriptionHandler) ResumeSubscriptionHandler(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, h.Svc.ResumeSubscription)
+}
+
+// CancelSubscriptionHandler membatalkan langganan.
+func (h *SubscriptionHandler) CancelSubscriptionHandler(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, h.Svc.CancelSubscription)
+}
+
+func (h *SubscriptionHandler) transition(w http.ResponseWriter, r *http.Request, apply func(context.Context, int) (model.Subscription, error)) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, `{"message":"ID langganan tidak valid."}`, http.StatusBadRequest)
+		return
+	}
+
+	sub, err := apply(r.Context(), id)
+	if err != nil {
+		writeSubscriptionError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, sub)
+}
+
+// GetUpcomingPaymentsHandler menampilkan tagihan langganan yang akan datang untuk dashboard.
+func (h *SubscriptionHandler) GetUpcomingPaymentsHandler(w http.ResponseWriter, r *http.Request) {
+	days := 30
+	if v := r.URL.Query().Get("days"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > 366 {
+			http.Error(w, `{"message":"Parameter days harus antara 1 dan 366."}`, http.StatusBadRequest)
+			return
+		}
+		days = n
+	}
+
+	loc, ok := requestLocation(w, r, h.Prefs)
+	if !ok {
+		return
+	}
+
+	upcoming, err := h.Svc.UpcomingPayments(r.Context(), days, loc)
+	if err != nil {
+		writeServerError(w, err, `{"message":"Gagal mengambil tagihan yang akan datang."}`)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, upcoming)
+}
+
+func writeSubscriptionError(w http.ResponseWriter, err error) {
+	var validationErr *service.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		writeJSON(w, http.StatusBadRequest, model.Response{Message: validationErr.Message, Success: false})
+	case errors.Is(err, storage.ErrNotFound):
+		http.Error(w, `{"message":"Langganan atau pelanggan tidak ditemukan."}`, http.StatusNotFound)
+	case errors.Is(err, service.ErrInvalidTransition):
+		writeJSON(w, http.StatusConflict, model.Response{Message: err.Error(), Success: false})
+	default:
+		writeServerError(w, err, `{"message":"Gagal memproses langganan."}`)
+	}
+}
@@ -0,0 +1,160 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"login-api/internal/model"
+	"login-api/internal/service"
+	"login-api/internal/storage"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+type WebhookHandler struct {
+	Svc *service.WebhookService
+}
+
+func NewWebhookHandler(svc *service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{Svc: svc}
+}
+
+type webhookSubscriptionRequest struct {
+	URL        string   `json:"url"`
+	Secret     string   `json:"secret"`
+	EventTypes []string `json:"event_types"`
+	Active     *bool    `json:"active"`
+}
+
+// ListWebhooksHandler menampilkan langganan webhook milik organisasi.
+func (h *WebhookHandler) ListWebhooksHandler(w http.ResponseWriter, r *http.Request) {
+	subs, err := h.Svc.Store.ListSubscriptions(r.Context(), h.Svc.OrganizationID)
+	if err != nil {
+		writeServerError(w, err, `{"message":"Gagal mengambil data webhook."}`)
+		return
+	}
+	if subs == nil {
+		subs = []model.WebhookSubscription{}
+	}
+
+	writeJSON(w, http.StatusOK, subs)
+}
+
+// CreateWebhookHandler membuat langganan webhook. Secret hanya dikembalikan pada respons ini.
+func (h *WebhookHandler) CreateWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	var req webhookSubscriptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"message":"Format permintaan tidak sesuai."}`, http.StatusBadRequest)
+		return
+	}
+
+	sub := model.WebhookSubscription{URL: req.URL, Secret: req.Secret, EventTypes: req.EventTypes, Active: true}
+	if req.Active != nil {
+		sub.Active = *req.Active
+	}
+
+	created, err := h.Svc.CreateSubscription(r.Context(), sub)
+	if err != nil {
+		writeWebhookError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, struct {
+		model.WebhookSubscription
+		Secret string `json:"secret"`
+	}{created, created.Secret})
+}
+
+// UpdateWebhookHandler memperbarui URL, jenis event, atau status aktif langganan webhook.
+func (h *WebhookHandler) UpdateWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, `{"message":"ID webhook tidak valid."}`, http.StatusBadRequest)
+		return
+	}
+
+	var req webhookSubscriptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"message":"Format permintaan tidak sesuai."}`, http.StatusBadRequest)
+		return
+	}
+
+	sub := model.WebhookSubscription{ID: id, URL: req.URL, EventTypes: req.EventTypes, Active: true}
+	if req.Active != nil {
+		sub.Active = *req.Active
+	}
+
+	updated, err := h.Svc.UpdateSubscription(r.Context(), sub)
+	if err != nil {
+		writeWebhookError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, updated)
+}
+
+// DeleteWebhookHandler menghapus langganan webhook.
+func (h *WebhookHandler) DeleteWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, `{"message":"ID webhook tidak valid."}`, http.StatusBadRequest)
+		return
+	}
+
+	if err := h.Svc.Store.DeleteSubscription(r.Context(), h.Svc.OrganizationID, id); err != nil {
+		writeWebhookError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, model.Response{Message: "Webhook berhasil dihapus.", Success: true})
+}
+
+// ListDeliveriesHandler menampilkan log pengiriman sebuah langganan webhook.
+func (h *WebhookHandler) ListDeliveriesHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, `{"message":"ID webhook tidak valid."}`, http.StatusBadRequest)
+		return
+	}
+
+	deliveries, err := h.Svc.Store.ListDeliveries(r.Context(), h.Svc.OrganizationID, id, 200)
+	if err != nil {
+		writeServerError(w, err, `{"message":"Gagal mengambil log pengiriman webhook."}`)
+		return
+	}
+	if deliveries == nil {
+		deliveries = []model.WebhookDelivery{}
+	}
+
+	writeJSON(w, http.StatusOK, deliveries)
+}
+
+// RedeliverHandler mengantrikan ulang sebuah pengiriman webhook.
+func (h *WebhookHandler) RedeliverHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, `{"message":"ID pengiriman tidak valid."}`, http.StatusBadRequest)
+		return
+	}
+
+	delivery, err := h.Svc.Store.Redeliver(r.Context(), h.Svc.OrganizationID, id)
+	if err != nil {
+		writeWebhookError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, delivery)
+}
+
+func writeWebhookError(w http.ResponseWriter, err error) {
+	var validationErr *service.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		writeJSON(w, http.StatusBadRequest, model.Response{Message: validationErr.Message, Success: false})
+	case errors.Is(err, storage.ErrNotFound):
+		http.Error(w, `{"message":"Webhook tidak ditemukan."}`, http.StatusNotFound)
+	default:
+		writeServerError(w, err, `{"message":"Gagal memproses webhook."}`)
+	}
+}
@@ -0,0 +1,33 @@
+// Package logging mengatur logger zerolog global yang dipakai seluruh aplikasi.
+package logging
+
+import (
+	"io"
+	"os"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+// Format keluaran log yang didukung.
+const (
+	// FormatConsole menulis log berwarna yang mudah dibaca manusia.
+	FormatConsole = "console"
+	// FormatJSON menulis satu objek JSON per baris untuk agregator log.
+	FormatJSON = "json"
+)
+
+// Setup mengganti logger global dengan logger ke stderr dalam format dan level
+// yang diberikan. Logger global juga menjadi logger bawaan log.Ctx untuk context
+// yang belum membawa logger, misalnya context pekerja latar belakang.
+func Setup(format string, level zerolog.Level) {
+	zerolog.TimeFieldFormat = zerolog.TimeFormatUnix
+	zerolog.SetGlobalLevel(level)
+
+	var out io.Writer = os.Stderr
+	if format != FormatJSON {
+		out = zerolog.ConsoleWriter{Out: os.Stderr}
+	}
+	log.Logger = zerolog.New(out).With().Timestamp().Logger()
+	zerolog.DefaultContextLogger = &log.Logger
+}
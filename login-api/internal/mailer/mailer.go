@@ -0,0 +1,136 @@
+package mailer
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Message adalah email yang akan dikirim. HTML dan Attachments bersifat opsional;
+// bila salah satunya diisi, email dikirim sebagai multipart dengan Text sebagai
+// alternatif teks biasa.
+type Message struct {
+	To          []string
+	Subject     string
+	Text        string
+	HTML        string
+	Attachments []Attachment
+}
+
+// Attachment adalah berkas yang dilampirkan pada email.
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
+
+// Mailer mengirimkan email.
+type Mailer interface {
+	Send(msg Message) error
+}
+
+// SMTPMailer mengirim email melalui server SMTP.
+type SMTPMailer struct {
+	Addr string
+	Auth smtp.Auth
+	From string
+}
+
+// NewSMTPMailer membuat SMTPMailer. Autentikasi PLAIN hanya dipakai bila username diisi.
+func NewSMTPMailer(host, port, username, password, from string) *SMTPMailer {
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+	return &SMTPMailer{Addr: host + ":" + port, Auth: auth, From: from}
+}
+
+// Send mengirim pesan ke semua penerima.
+func (m *SMTPMailer) Send(msg Message) error {
+	if err := smtp.SendMail(m.Addr, m.Auth, m.From, msg.To, m.build(msg)); err != nil {
+		return fmt.Errorf("kesalahan saat mengirim email ke %s: %w", strings.Join(msg.To, ", "), err)
+	}
+	return nil
+}
+
+func (m *SMTPMailer) build(msg Message) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", m.From)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(msg.To, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	if msg.HTML == "" && len(msg.Attachments) == 0 {
+		b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+		b.WriteString("Content-Transfer-Encoding: 8bit\r\n\r\n")
+		b.WriteString(strings.ReplaceAll(msg.Text, "\n", "\r\n"))
+		return b.Bytes()
+	}
+
+	// multipart/mixed berisi multipart/alternative (teks dan HTML) diikuti lampiran.
+	mixed := multipart.NewWriter(&b)
+	fmt.Fprintf(&b, "Content-Type: multipart/mixed; boundary=%s\r\n\r\n", mixed.Boundary())
+
+	var alt bytes.Buffer
+	altWriter := multipart.NewWriter(&alt)
+	writeTextPart(altWriter, "text/plain; charset=utf-8", msg.Text)
+	if msg.HTML != "" {
+		writeTextPart(altWriter, "text/html; charset=utf-8", msg.HTML)
+	}
+	altWriter.Close()
+
+	part, _ := mixed.CreatePart(textproto.MIMEHeader{
+		"Content-Type": {"multipart/alternative; boundary=" + altWriter.Boundary()},
+	})
+	part.Write(alt.Bytes())
+
+	for _, a := range msg.Attachments {
+		part, _ := mixed.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {a.ContentType},
+			"Content-Transfer-Encoding": {"base64"},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": a.Filename})},
+		})
+		writeBase64(part, a.Data)
+	}
+	mixed.Close()
+	return b.Bytes()
+}
+
+func writeTextPart(w *multipart.Writer, contentType, text string) {
+	part, _ := w.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {contentType},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	qp := quotedprintable.NewWriter(part)
+	qp.Write([]byte(text))
+	qp.Close()
+}
+
+// writeBase64 menulis data sebagai base64 dengan baris maksimal 76 karakter sesuai RFC 2045.
+func writeBase64(w io.Writer, data []byte) {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	for len(encoded) > 76 {
+		io.WriteString(w, encoded[:76]+"\r\n")
+		encoded = encoded[76:]
+	}
+	io.WriteString(w, encoded+"\r\n")
+}
+
+// LogMailer hanya mencatat email ke log. Dipakai saat SMTP belum dikonfigurasi.
+type LogMailer struct{}
+
+// Send mencatat pesan tanpa mengirimkannya.
+func (LogMailer) Send(msg Message) error {
+	log.Info().Strs("to", msg.To).Str("subject", msg.Subject).Int("attachments", len(msg.Attachments)).Msg("SMTP tidak dikonfigurasi, email hanya dicatat")
+	return nil
+}
@@ -0,0 +1,87 @@
+// Package metrics mendefinisikan metrik Prometheus aplikasi dan handler
+// /metrics yang menyajikannya. Metrik didaftarkan ke Registry milik paket ini,
+// bukan registry global, sehingga hanya metrik yang disengaja yang terekspos.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "login_api"
+
+// Label hasil untuk LoginAttempts dan TokenRefreshes.
+const (
+	ResultSuccess = "success"
+	ResultFailure = "failure"
+)
+
+// Registry menampung seluruh metrik yang disajikan Handler.
+var Registry = prometheus.NewRegistry()
+
+var (
+	// HTTPRequestDuration mencatat lama permintaan HTTP per method, template
+	// route dari router (misalnya /api/customers/{id:[0-9]+}), dan kode status.
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Lama pemrosesan permintaan HTTP per route.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	// OpenStreams adalah jumlah stream server-sent events yang sedang terbuka.
+	// Stream tidak tercatat di HTTPRequestDuration karena koneksinya berumur
+	// panjang dan akan selalu jatuh di bucket teratas.
+	OpenStreams = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "sse_open_streams",
+		Help:      "Jumlah stream server-sent events yang sedang terbuka.",
+	})
+
+	// LoginAttempts menghitung percobaan login. Kegagalan mencakup kredensial
+	// salah dan akun nonaktif; error server hanya tercatat di HTTPRequestDuration.
+	LoginAttempts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "login_attempts_total",
+		Help:      "Jumlah percobaan login per hasil.",
+	}, []string{"result"})
+
+	// TokenRefreshes menghitung permintaan pembaruan access token.
+	TokenRefreshes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "token_refreshes_total",
+		Help:      "Jumlah pembaruan access token per hasil.",
+	}, []string{"result"})
+
+	// RateLimitRejections menghitung permintaan yang ditolak pembatas laju.
+	RateLimitRejections = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rate_limit_rejections_total",
+		Help:      "Jumlah permintaan yang ditolak karena melewati batas laju.",
+	})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequestDuration,
+		OpenStreams,
+		LoginAttempts,
+		TokenRefreshes,
+		RateLimitRejections,
+	)
+	// Inisialisasi label agar deret bernilai 0 sudah muncul sebelum kejadian pertama.
+	for _, result := range []string{ResultSuccess, ResultFailure} {
+		LoginAttempts.WithLabelValues(result)
+		TokenRefreshes.WithLabelValues(result)
+	}
+}
+
+// Handler menyajikan metrik dalam format eksposisi Prometheus.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
@@ -0,0 +1,72 @@
+package metrics
+
+import (
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// poolCollector membaca pgxpool.Stat setiap kali /metrics diambil.
+type poolCollector struct {
+	pool *pgxpool.Pool
+
+	acquiredConns        *prometheus.Desc
+	idleConns            *prometheus.Desc
+	constructingConns    *prometheus.Desc
+	totalConns           *prometheus.Desc
+	maxConns             *prometheus.Desc
+	acquireCount         *prometheus.Desc
+	acquireDuration      *prometheus.Desc
+	emptyAcquireCount    *prometheus.Desc
+	canceledAcquireCount *prometheus.Desc
+	newConnsCount        *prometheus.Desc
+	maxLifetimeDestroy   *prometheus.Desc
+	maxIdleDestroy       *prometheus.Desc
+}
+
+// NewPoolCollector membuat collector statistik koneksi pool PostgreSQL.
+func NewPoolCollector(pool *pgxpool.Pool) prometheus.Collector {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, "db_pool", name), help, nil, nil)
+	}
+	return &poolCollector{
+		pool:                 pool,
+		acquiredConns:        desc("acquired_connections", "Jumlah koneksi yang sedang dipakai."),
+		idleConns:            desc("idle_connections", "Jumlah koneksi yang menganggur."),
+		constructingConns:    desc("constructing_connections", "Jumlah koneksi yang sedang dibuat."),
+		totalConns:           desc("total_connections", "Jumlah seluruh koneksi di pool."),
+		maxConns:             desc("max_connections", "Batas jumlah koneksi di pool."),
+		acquireCount:         desc("acquires_total", "Jumlah pengambilan koneksi yang berhasil."),
+		acquireDuration:      desc("acquire_duration_seconds_total", "Total lama menunggu pengambilan koneksi yang berhasil."),
+		emptyAcquireCount:    desc("empty_acquires_total", "Jumlah pengambilan koneksi yang harus menunggu karena pool kosong."),
+		canceledAcquireCount: desc("canceled_acquires_total", "Jumlah pengambilan koneksi yang dibatalkan oleh context."),
+		newConnsCount:        desc("new_connections_total", "Jumlah koneksi baru yang dibuat."),
+		maxLifetimeDestroy:   desc("max_lifetime_destroys_total", "Jumlah koneksi yang ditutup karena melewati MaxConnLifetime."),
+		maxIdleDestroy:       desc("max_idle_destroys_total", "Jumlah koneksi yang ditutup karena melewati MaxConnIdleTime."),
+	}
+}
+
+func (c *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	prometheus.DescribeByCollect(c, ch)
+}
+
+func (c *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	s := c.pool.Stat()
+	gauge := func(d *prometheus.Desc, v float64) {
+		ch <- prometheus.MustNewConstMetric(d, prometheus.GaugeValue, v)
+	}
+	counter := func(d *prometheus.Desc, v float64) {
+		ch <- prometheus.MustNewConstMetric(d, prometheus.CounterValue, v)
+	}
+	gauge(c.acquiredConns, float64(s.AcquiredConns()))
+	gauge(c.idleConns, float64(s.IdleConns()))
+	gauge(c.constructingConns, float64(s.ConstructingConns()))
+	gauge(c.totalConns, float64(s.TotalConns()))
+	gauge(c.maxConns, float64(s.MaxConns()))
+	counter(c.acquireCount, float64(s.AcquireCount()))
+	counter(c.acquireDuration, s.AcquireDuration().Seconds())
+	counter(c.emptyAcquireCount, float64(s.EmptyAcquireCount()))
+	counter(c.canceledAcquireCount, float64(s.CanceledAcquireCount()))
+	counter(c.newConnsCount, float64(s.NewConnsCount()))
+	counter(c.maxLifetimeDestroy, float64(s.MaxLifetimeDestroyCount()))
+	counter(c.maxIdleDestroy, float64(s.MaxIdleDestroyCount()))
+}
@@ -1,11 +1,11 @@
 package middleware
 
 import (
-	"log"
 	"login-api/internal/model"
 	"net/http"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/rs/zerolog/log"
 )
 
 // NewJwtMiddleware membuat lapisan pelindung untuk memeriksa token JWT dari cookie.
@@ -15,7 +15,7 @@ func NewJwtMiddleware(jwtKey []byte) func(http.Handler) http.Handler {
 			c, err := r.Cookie("access_token")
 			if err != nil {
 				if err == http.ErrNoCookie {
-					log.Printf("PERINGATAN: Permintaan ke '%s' dari IP %s ditolak karena tidak ada token.", r.URL.Path, r.RemoteAddr)
+					log.Ctx(r.Context()).Warn().Str("path", r.URL.Path).Str("remote_addr", r.RemoteAddr).Msg("Permintaan ditolak karena tidak ada token")
 					http.Error(w, `{"message":"Token otentikasi tidak ditemukan."}`, http.StatusUnauthorized)
 					return
 				}
@@ -30,11 +30,32 @@ func NewJwtMiddleware(jwtKey []byte) func(http.Handler) http.Handler {
 			})
 
 			if err != nil || !token.Valid {
-				log.Printf("PERINGATAN: Token tidak valid atau kedaluwarsa digunakan untuk akses ke '%s' dari IP %s. Error: %v", r.URL.Path, r.RemoteAddr, err)
+				log.Ctx(r.Context()).Warn().Err(err).Str("path", r.URL.Path).Str("remote_addr", r.RemoteAddr).Msg("Token tidak valid atau kedaluwarsa")
 				http.Error(w, `{"message":"Token tidak valid atau telah kedaluwarsa. Silakan login kembali."}`, http.StatusUnauthorized)
 				return
 			}
 
+			setRequestUser(r.Context(), claims.Email)
+			ctx := WithUserEmail(r.Context(), claims.Email)
+			ctx = WithUserRole(ctx, claims.Role)
+			if claims.ExpiresAt != nil {
+				ctx = WithTokenExpiry(ctx, claims.ExpiresAt.Time)
+			}
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// RequireRole menolak permintaan dengan 403 bila peran pada token bukan role.
+// Harus dipasang setelah middleware JWT.
+func RequireRole(role string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if userRole, _ := UserRole(r.Context()); userRole != role {
+				log.Ctx(r.Context()).Warn().Str("path", r.URL.Path).Str("role", userRole).Msg("Permintaan ditolak karena peran tidak mencukupi")
+				http.Error(w, `{"message":"Anda tidak memiliki akses untuk melakukan tindakan ini."}`, http.StatusForbidden)
+				return
+			}
 			next.ServeHTTP(w, r)
 		})
 	}
@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"context"
+	"time"
+)
+
+type contextKey int
+
+const (
+	userEmailKey contextKey = iota
+	requestInfoKey
+	userRoleKey
+	tokenExpiryKey
+)
+
+// WithUserEmail menyimpan email pengguna yang terautentikasi ke dalam ctx.
+func WithUserEmail(ctx context.Context, email string) context.Context {
+	return context.WithValue(ctx, userEmailKey, email)
+}
+
+// UserEmail mengambil email pengguna yang terautentikasi dari ctx.
+func UserEmail(ctx context.Context) (string, bool) {
+	email, ok := ctx.Value(userEmailKey).(string)
+	return email, ok && email != ""
+}
+
+// WithUserRole menyimpan peran pengguna yang terautentikasi ke dalam ctx.
+func WithUserRole(ctx context.Context, role string) context.Context {
+	return context.WithValue(ctx, userRoleKey, role)
+}
+
+// UserRole mengambil peran pengguna yang terautentikasi dari ctx.
+func UserRole(ctx context.Context) (string, bool) {
+	role, ok := ctx.Value(userRoleKey).(string)
+	return role, ok && role != ""
+}
+
+// WithTokenExpiry menyimpan waktu kedaluwarsa token akses ke dalam ctx.
+func WithTokenExpiry(ctx context.Context, expiresAt time.Time) context.Context {
+	return context.WithValue(ctx, tokenExpiryKey, expiresAt)
+}
+
+// TokenExpiry mengambil waktu kedaluwarsa token akses dari ctx. Koneksi yang
+// berumur panjang, seperti stream dashboard, memakainya untuk berhenti saat token
+// kedaluwarsa.
+func TokenExpiry(ctx context.Context) (time.Time, bool) {
+	expiresAt, ok := ctx.Value(tokenExpiryKey).(time.Time)
+	return expiresAt, ok
+}
@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"login-api/internal/metrics"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// Metrics mencatat lama setiap permintaan ke metrics.HTTPRequestDuration dengan
+// label template route, bukan path mentah, agar ID di path tidak membuat deret
+// metrik baru. Dipasang dengan Router.Use sehingga hanya route yang cocok yang
+// tercatat. Respons text/event-stream dilewati; stream yang terbuka dihitung
+// oleh metrics.OpenStreams.
+func Metrics(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+		if strings.HasPrefix(rec.Header().Get("Content-Type"), "text/event-stream") {
+			return
+		}
+
+		route := "unknown"
+		if current := mux.CurrentRoute(r); current != nil {
+			if tpl, err := current.GetPathTemplate(); err == nil {
+				route = tpl
+			}
+		}
+		metrics.HTTPRequestDuration.
+			WithLabelValues(r.Method, route, strconv.Itoa(rec.status)).
+			Observe(time.Since(start).Seconds())
+	})
+}
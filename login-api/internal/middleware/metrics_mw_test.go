@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"io"
+	"login-api/internal/metrics"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+// scrapeMetrics mengambil keluaran metrics.Handler dalam format teks.
+func scrapeMetrics(t *testing.T) string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body, err := io.ReadAll(rec.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(body)
+}
+
+func TestMetricsSkipsEventStream(t *testing.T) {
+	r := mux.NewRouter()
+	r.Use(Metrics)
+	r.HandleFunc("/test/metrics/{id:[0-9]+}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	r.HandleFunc("/test/metrics/stream", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+	})
+
+	for _, path := range []string{"/test/metrics/42", "/test/metrics/stream"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	body := scrapeMetrics(t)
+	if !strings.Contains(body, `route="/test/metrics/{id:[0-9]+}",status="404"`) {
+		t.Error("permintaan biasa tidak tercatat dengan template route")
+	}
+	if strings.Contains(body, `route="/test/metrics/stream"`) {
+		t.Error("stream SSE tercatat di http_request_duration_seconds")
+	}
+	if !strings.Contains(body, "login_api_sse_open_streams 0") {
+		t.Error("metrik login_api_sse_open_streams tidak tersedia")
+	}
+}
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"login-api/internal/metrics"
 	"net/http"
 	"sync"
 	"time"
@@ -41,6 +42,7 @@ func RateLimiterMiddleware(next http.Handler) http.Handler {
 		clients[ip].lastSeen = time.Now()
 		if !clients[ip].limiter.Allow() {
 			mu.Unlock()
+			metrics.RateLimitRejections.Inc()
 			http.Error(w, `{"message":"Terlalu banyak permintaan."}`, http.StatusTooManyRequests)
 			return
 		}
@@ -0,0 +1,125 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+// RequestIDHeader adalah header tempat ID permintaan dibaca dan dikembalikan.
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength membatasi ID permintaan dari klien agar log tidak dibanjiri.
+const maxRequestIDLength = 128
+
+// requestInfo menyimpan data permintaan yang baru diketahui oleh handler di
+// dalam rantai middleware, misalnya pengguna yang terautentikasi.
+type requestInfo struct {
+	logger *zerolog.Logger
+	user   string
+}
+
+// RequestLogger memberi setiap permintaan ID dari header X-Request-ID, atau ID
+// acak bila header kosong atau tidak valid, dan mengembalikannya di respons.
+// Logger berisi ID tersebut disimpan di context permintaan sehingga
+// log.Ctx(r.Context()) di handler, service, dan store ikut mencatatnya. Setelah
+// permintaan selesai, satu baris access log ditulis.
+func RequestLogger(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		id := r.Header.Get(RequestIDHeader)
+		if !validRequestID(id) {
+			id = rand.Text()
+		}
+		w.Header().Set(RequestIDHeader, id)
+
+		logger := log.With().Str("request_id", id).Logger()
+		ctx := logger.WithContext(r.Context())
+		info := &requestInfo{logger: zerolog.Ctx(ctx)}
+		ctx = context.WithValue(ctx, requestInfoKey, info)
+
+		rec := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r.WithContext(ctx))
+
+		event := logger.Info()
+		if rec.status >= http.StatusInternalServerError {
+			event = logger.Error()
+		}
+		event.
+			Str("method", r.Method).
+			Str("path", r.URL.Path).
+			Int("status", rec.status).
+			Dur("latency", time.Since(start)).
+			Int64("bytes", rec.bytes).
+			Str("user", info.user).
+			Msg("Permintaan HTTP")
+	})
+}
+
+// setRequestUser mencatat email pengguna yang terautentikasi ke access log dan
+// ke logger permintaan. Tidak berpengaruh bila RequestLogger tidak dipasang.
+func setRequestUser(ctx context.Context, email string) {
+	info, ok := ctx.Value(requestInfoKey).(*requestInfo)
+	if !ok {
+		return
+	}
+	info.user = email
+	info.logger.UpdateContext(func(c zerolog.Context) zerolog.Context {
+		return c.Str("user", email)
+	})
+}
+
+// validRequestID menerima ID dari klien hanya bila berisi karakter ASCII yang
+// dapat dicetak dan tidak terlalu panjang.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// responseRecorder mencatat kode status dan jumlah byte respons.
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	bytes       int64
+	wroteHeader bool
+}
+
+func (rec *responseRecorder) WriteHeader(status int) {
+	if !rec.wroteHeader {
+		rec.status = status
+		rec.wroteHeader = true
+	}
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+func (rec *responseRecorder) Write(b []byte) (int, error) {
+	rec.wroteHeader = true
+	n, err := rec.ResponseWriter.Write(b)
+	rec.bytes += int64(n)
+	return n, err
+}
+
+// Flush meneruskan flush ke ResponseWriter asli agar streaming SSE tetap berjalan.
+func (rec *responseRecorder) Flush() {
+	if f, ok := rec.ResponseWriter.(http.Flusher); ok {
+		rec.wroteHeader = true
+		f.Flush()
+	}
+}
+
+// Unwrap dipakai http.ResponseController untuk mencapai ResponseWriter asli.
+func (rec *responseRecorder) Unwrap() http.ResponseWriter {
+	return rec.ResponseWriter
+}
@@ -0,0 +1,180 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"login-api/internal/auth"
+	"login-api/internal/model"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+// captureLogs mengarahkan logger global ke buffer selama test berjalan.
+func captureLogs(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	prev := log.Logger
+	log.Logger = zerolog.New(&buf)
+	t.Cleanup(func() { log.Logger = prev })
+	return &buf
+}
+
+// logLines mengurai setiap baris log JSON di buf.
+func logLines(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+	var lines []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var entry map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("baris log bukan JSON: %q", line)
+		}
+		lines = append(lines, entry)
+	}
+	return lines
+}
+
+func TestValidRequestID(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+		want bool
+	}{
+		{"kosong", "", false},
+		{"uuid", "3f2b6c1e-8a4d-4f6e-9b1a-2c3d4e5f6a7b", true},
+		{"tanda baca", "req_42/abc:1", true},
+		{"panjang maksimum", strings.Repeat("a", maxRequestIDLength), true},
+		{"terlalu panjang", strings.Repeat("a", maxRequestIDLength+1), false},
+		{"berisi spasi", "req 42", false},
+		{"karakter kontrol", "req\n42", false},
+		{"non-ASCII", "permintaan-é", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := validRequestID(tt.id); got != tt.want {
+				t.Errorf("validRequestID(%q) = %v, want %v", tt.id, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequestLoggerRequestID(t *testing.T) {
+	tests := []struct {
+		name     string
+		id       string
+		wantEcho bool
+	}{
+		{"ID dari klien dikembalikan", "klien-123", true},
+		{"tanpa ID", "", false},
+		{"ID tidak valid diganti", "bukan valid", false},
+		{"ID terlalu panjang diganti", strings.Repeat("x", maxRequestIDLength+1), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := captureLogs(t)
+			var seen string
+			h := RequestLogger(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				log.Ctx(r.Context()).Info().Msg("di dalam handler")
+				seen = w.Header().Get(RequestIDHeader)
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/payments", nil)
+			if tt.id != "" {
+				req.Header.Set(RequestIDHeader, tt.id)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			got := rec.Header().Get(RequestIDHeader)
+			if tt.wantEcho && got != tt.id {
+				t.Errorf("%s = %q, want %q", RequestIDHeader, got, tt.id)
+			}
+			if !tt.wantEcho && (got == tt.id || !validRequestID(got)) {
+				t.Errorf("%s = %q, want ID acak yang valid", RequestIDHeader, got)
+			}
+			if seen != got {
+				t.Errorf("handler melihat ID %q, want %q", seen, got)
+			}
+			for _, entry := range logLines(t, buf) {
+				if entry["request_id"] != got {
+					t.Errorf("log %v tanpa request_id %q", entry, got)
+				}
+			}
+		})
+	}
+}
+
+func TestRequestLoggerAccessLog(t *testing.T) {
+	buf := captureLogs(t)
+	h := RequestLogger(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("gagal"))
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/payments?x=1", nil)
+	req.Header.Set(RequestIDHeader, "req-1")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	lines := logLines(t, buf)
+	if len(lines) != 1 {
+		t.Fatalf("jumlah baris log = %d, want 1", len(lines))
+	}
+	entry := lines[0]
+	want := map[string]interface{}{
+		"level":      "error",
+		"request_id": "req-1",
+		"method":     http.MethodPost,
+		"path":       "/api/payments",
+		"status":     float64(http.StatusInternalServerError),
+		"bytes":      float64(len("gagal")),
+		"user":       "",
+		"message":    "Permintaan HTTP",
+	}
+	for k, v := range want {
+		if entry[k] != v {
+			t.Errorf("access log %s = %v, want %v", k, entry[k], v)
+		}
+	}
+	if _, ok := entry["latency"]; !ok {
+		t.Error("access log tanpa latency")
+	}
+}
+
+func TestRequestLoggerUserAfterJwt(t *testing.T) {
+	buf := captureLogs(t)
+	jwtKey := []byte("rahasia-test")
+	access, _, err := auth.GenerateTokens("andi@contoh.id", model.RoleUser, jwtKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h := RequestLogger(NewJwtMiddleware(jwtKey)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		log.Ctx(r.Context()).Info().Msg("di dalam handler")
+	})))
+	req := httptest.NewRequest(http.MethodGet, "/api/dashboard", nil)
+	req.AddCookie(&http.Cookie{Name: "access_token", Value: access})
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	lines := logLines(t, buf)
+	if len(lines) != 2 {
+		t.Fatalf("jumlah baris log = %d, want 2", len(lines))
+	}
+	for _, entry := range lines {
+		if entry["user"] != "andi@contoh.id" {
+			t.Errorf("log %q user = %v, want andi@contoh.id", entry["message"], entry["user"])
+		}
+	}
+}
+
+func TestSetRequestUserWithoutRequestLogger(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	// Tidak boleh panik bila RequestLogger tidak dipasang.
+	setRequestUser(req.Context(), "andi@contoh.id")
+}
@@ -0,0 +1,56 @@
+package model
+
+import "time"
+
+// AgingBucket merangkum piutang yang telah lewat jatuh tempo dalam rentang hari tertentu.
+type AgingBucket struct {
+	Label   string  `json:"label"`    // Contoh: "31-60"
+	MinDays int     `json:"min_days"` // Inklusif
+	MaxDays *int    `json:"max_days"` // Inklusif; nil untuk bucket terakhir (90+)
+	Count   int64   `json:"count"`
+	Amount  float64 `json:"amount"`
+}
+
+// AgingReport adalah laporan umur piutang per tanggal tertentu.
+type AgingReport struct {
+	AsOf        string        `json:"as_of"`
+	Buckets     []AgingBucket `json:"buckets"`
+	TotalCount  int64         `json:"total_count"`
+	TotalAmount float64       `json:"total_amount"`
+}
+
+// NewAgingReport membuat laporan umur piutang kosong per tanggal asOf dengan
+// bucket 0-30, 31-60, 61-90, dan 90+ hari.
+func NewAgingReport(asOf time.Time) AgingReport {
+	maxDays := func(v int) *int { return &v }
+	return AgingReport{
+		AsOf: asOf.Format("2006-01-02"),
+		Buckets: []AgingBucket{
+			{Label: "0-30", MinDays: 0, MaxDays: maxDays(30)},
+			{Label: "31-60", MinDays: 31, MaxDays: maxDays(60)},
+			{Label: "61-90", MinDays: 61, MaxDays: maxDays(90)},
+			{Label: "90+", MinDays: 91},
+		},
+	}
+}
+
+// Add menambahkan count piutang senilai amount yang terlambat days hari ke
+// bucket yang sesuai.
+func (r *AgingReport) Add(days int, count int64, amount float64) {
+	for i := range r.Buckets {
+		b := &r.Buckets[i]
+		if days >= b.MinDays && (b.MaxDays == nil || days <= *b.MaxDays) {
+			b.Count += count
+			b.Amount += amount
+			break
+		}
+	}
+	r.TotalCount += count
+	r.TotalAmount += amount
+}
+
+// ReminderCandidate adalah pembayaran belum lunas yang mungkin perlu dikirimi pengingat.
+type ReminderCandidate struct {
+	Payment       Payment
+	CustomerEmail string
+}
@@ -0,0 +1,46 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Jenis anomali yang dideteksi.
+const (
+	AlertRevenueDrop    = "revenue_drop"
+	AlertUnsettledSpike = "unsettled_spike"
+	AlertLargePayment   = "large_payment"
+)
+
+// Tingkat keparahan peringatan.
+const (
+	AlertSeverityWarning  = "warning"
+	AlertSeverityCritical = "critical"
+)
+
+// Alert adalah peringatan anomali aktivitas pembayaran.
+type Alert struct {
+	ID             int             `json:"id"`
+	Kind           string          `json:"kind"`
+	Severity       string          `json:"severity"`
+	Fingerprint    string          `json:"fingerprint"`
+	Message        string          `json:"message"`
+	Details        json.RawMessage `json:"details"`
+	DetectedAt     time.Time       `json:"detected_at"`
+	AcknowledgedAt *time.Time      `json:"acknowledged_at"`
+}
+
+// DailyActivity adalah aktivitas pembayaran pada satu tanggal lokal. Unsettled
+// menghitung pembayaran Tertunda, Terlambat, dan Dibatalkan.
+type DailyActivity struct {
+	Date      time.Time
+	Revenue   float64
+	Unsettled int64
+}
+
+// AmountStats adalah statistik nominal pembayaran pada suatu rentang.
+type AmountStats struct {
+	Mean   float64
+	StdDev float64
+	Count  int64
+}
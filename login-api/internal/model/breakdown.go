@@ -0,0 +1,17 @@
+package model
+
+// StatusBreakdown adalah jumlah dan nilai pembayaran untuk satu status.
+type StatusBreakdown struct {
+	Status string  `json:"status"`
+	Count  int64   `json:"count"`
+	Amount float64 `json:"amount"`
+}
+
+// CustomerBreakdown adalah pendapatan lunas dari satu pelanggan. CustomerID
+// bernilai null untuk pembayaran lama yang belum terhubung ke data pelanggan.
+type CustomerBreakdown struct {
+	CustomerID   *int    `json:"customer_id"`
+	CustomerName string  `json:"customer_name"`
+	Revenue      float64 `json:"revenue"`
+	PaymentCount int64   `json:"payment_count"`
+}
@@ -0,0 +1,82 @@
+package model
+
+import "time"
+
+// Granularitas data grafik.
+const (
+	ChartIntervalHour  = "hour"
+	ChartIntervalDay   = "day"
+	ChartIntervalWeek  = "week"
+	ChartIntervalMonth = "month"
+)
+
+// ChartRange menentukan rentang dan granularitas data grafik. From dan To
+// inklusif; keduanya dibulatkan ke awal interval saat membentuk deret. Batas
+// interval (tengah malam, awal minggu, dan seterusnya) dihitung pada Location.
+type ChartRange struct {
+	From     time.Time
+	To       time.Time
+	Interval string
+	Location *time.Location
+}
+
+// chartLabelLayouts adalah format label grafik untuk setiap interval. Label
+// minggu adalah tanggal Senin awal minggu tersebut.
+var chartLabelLayouts = map[string]string{
+	ChartIntervalHour:  "2006-01-02T15:00",
+	ChartIntervalDay:   "2006-01-02",
+	ChartIntervalWeek:  "2006-01-02",
+	ChartIntervalMonth: "2006-01",
+}
+
+// Buckets mengembalikan awal setiap interval pada r.Location, mulai dari
+// interval yang memuat From sampai interval yang memuat To. Batas dihitung dari
+// waktu lokal sehingga hari pergantian DST tetap benar.
+func (r ChartRange) Buckets() []time.Time {
+	var buckets []time.Time
+	last := r.truncate(r.To.In(r.Location))
+	for b := r.truncate(r.From.In(r.Location)); !b.After(last); b = r.Next(b) {
+		buckets = append(buckets, b)
+	}
+	return buckets
+}
+
+// truncate membulatkan t ke awal interval. Minggu dimulai hari Senin.
+func (r ChartRange) truncate(t time.Time) time.Time {
+	y, m, d := t.Date()
+	switch r.Interval {
+	case ChartIntervalHour:
+		return time.Date(y, m, d, t.Hour(), 0, 0, 0, t.Location())
+	case ChartIntervalWeek:
+		return time.Date(y, m, d-(int(t.Weekday())+6)%7, 0, 0, 0, 0, t.Location())
+	case ChartIntervalMonth:
+		return time.Date(y, m, 1, 0, 0, 0, 0, t.Location())
+	default:
+		return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+	}
+}
+
+// Next mengembalikan awal interval setelah interval yang dimulai pada bucket.
+func (r ChartRange) Next(bucket time.Time) time.Time {
+	switch r.Interval {
+	case ChartIntervalHour:
+		return bucket.Add(time.Hour)
+	case ChartIntervalWeek:
+		return bucket.AddDate(0, 0, 7)
+	case ChartIntervalMonth:
+		return bucket.AddDate(0, 1, 0)
+	default:
+		return bucket.AddDate(0, 0, 1)
+	}
+}
+
+// Label mengembalikan label grafik untuk interval yang dimulai pada bucket.
+func (r ChartRange) Label(bucket time.Time) string {
+	return bucket.Format(chartLabelLayouts[r.Interval])
+}
+
+// ValidInterval melaporkan apakah r.Interval dikenali.
+func (r ChartRange) ValidInterval() bool {
+	_, ok := chartLabelLayouts[r.Interval]
+	return ok
+}
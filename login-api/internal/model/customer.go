@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+// Customer merepresentasikan pelanggan yang dapat ditautkan ke banyak pembayaran.
+type Customer struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	Phone     string    `json:"phone"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CustomerWithTotals adalah pelanggan beserta agregat pembayarannya.
+type CustomerWithTotals struct {
+	Customer
+	PaymentCount     int64   `json:"payment_count"`
+	TotalPaid        float64 `json:"total_paid"`
+	TotalOutstanding float64 `json:"total_outstanding"`
+}
+
+// MergeCustomersRequest berisi ID pelanggan duplikat yang akan digabung ke pelanggan tujuan.
+type MergeCustomersRequest struct {
+	SourceIDs []int `json:"source_ids"`
+}
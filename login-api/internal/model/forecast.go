@@ -0,0 +1,20 @@
+package model
+
+// ForecastPoint adalah proyeksi pendapatan untuk satu periode beserta pita
+// keyakinannya. Label memakai format yang sama dengan ChartData.
+type ForecastPoint struct {
+	Label string  `json:"label"`
+	Value float64 `json:"value"`
+	Lower float64 `json:"lower"`
+	Upper float64 `json:"upper"`
+}
+
+// RevenueForecast adalah riwayat pendapatan lunas dan proyeksinya untuk periode
+// berikutnya, dimulai dari periode berjalan.
+type RevenueForecast struct {
+	Interval   string          `json:"interval"`
+	Method     string          `json:"method"`
+	Confidence float64         `json:"confidence"`
+	History    []ChartData     `json:"history"`
+	Forecast   []ForecastPoint `json:"forecast"`
+}
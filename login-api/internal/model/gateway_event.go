@@ -0,0 +1,26 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Status pemrosesan event dari penyedia pembayaran.
+const (
+	GatewayEventProcessed = "diproses"
+	GatewayEventIgnored   = "diabaikan"
+	GatewayEventFailed    = "gagal"
+)
+
+// GatewayEvent adalah event webhook mentah dari penyedia pembayaran beserta hasil pemrosesannya.
+type GatewayEvent struct {
+	ID              int             `json:"id"`
+	ProviderEventID string          `json:"provider_event_id"`
+	EventType       string          `json:"event_type"`
+	PaymentID       int             `json:"payment_id"`
+	Payload         json.RawMessage `json:"payload"`
+	Status          string          `json:"status"`
+	Message         string          `json:"message"`
+	ReceivedAt      time.Time       `json:"received_at"`
+	ProcessedAt     *time.Time      `json:"processed_at"`
+}
@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+// Invoice merepresentasikan dokumen faktur/kwitansi yang diterbitkan untuk satu pembayaran.
+type Invoice struct {
+	ID             int       `json:"id"`
+	OrganizationID string    `json:"organization_id"`
+	PaymentID      int       `json:"payment_id"`
+	InvoiceNumber  string    `json:"invoice_number"`
+	IssuedAt       time.Time `json:"issued_at"`
+}
+
+// Company berisi identitas perusahaan yang dicetak pada kop dokumen.
+type Company struct {
+	Name    string
+	Address string
+	Email   string
+	Phone   string
+}
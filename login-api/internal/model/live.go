@@ -0,0 +1,39 @@
+package model
+
+import "time"
+
+// Jenis perubahan pembayaran yang dilaporkan oleh database.
+const (
+	PaymentChangeInsert = "insert"
+	PaymentChangeUpdate = "update"
+	PaymentChangeDelete = "delete"
+)
+
+// PaymentChange adalah notifikasi perubahan satu pembayaran. Field Old* kosong
+// untuk insert, sedangkan field baru kosong untuk delete.
+type PaymentChange struct {
+	Op             string     `json:"op"`
+	ID             int        `json:"id"`
+	Status         string     `json:"status"`
+	Amount         float64    `json:"amount"`
+	PaymentDate    *time.Time `json:"payment_date"`
+	OldStatus      string     `json:"old_status"`
+	OldAmount      float64    `json:"old_amount"`
+	OldPaymentDate *time.Time `json:"old_payment_date"`
+}
+
+// RevenueChange adalah perubahan pendapatan lunas pada waktu At. Klien
+// menambahkan Value ke titik grafik yang memuat At.
+type RevenueChange struct {
+	At    time.Time `json:"at"`
+	Value float64   `json:"value"`
+}
+
+// DashboardDelta adalah perubahan ringkasan dan grafik dashboard akibat satu
+// perubahan pembayaran. Summary berisi selisih, bukan nilai total.
+type DashboardDelta struct {
+	PaymentID int              `json:"payment_id"`
+	Op        string           `json:"op"`
+	Summary   DashboardSummary `json:"summary"`
+	Chart     []RevenueChange  `json:"chart"`
+}
@@ -2,11 +2,51 @@ package model
 
 import "time"
 
+// Status pembayaran yang dikenali oleh aplikasi.
+const (
+	PaymentStatusPaid      = "Lunas"
+	PaymentStatusPending   = "Tertunda"
+	PaymentStatusCancelled = "Dibatalkan"
+	// PaymentStatusOverdue diberikan oleh pekerja latar belakang kepada pembayaran
+	// tertunda yang telah melewati DueDate.
+	PaymentStatusOverdue = "Terlambat"
+	// PaymentStatusRefunded diberikan saat penyedia pembayaran mengembalikan dana.
+	PaymentStatusRefunded = "Dikembalikan"
+)
+
+// Status rekonsiliasi pembayaran terhadap mutasi rekening bank.
+const (
+	ReconciliationUnreconciled = "belum"
+	ReconciliationReconciled   = "terekonsiliasi"
+)
+
+// paymentTransitions mendaftar perubahan status yang boleh dilakukan secara otomatis.
+var paymentTransitions = map[string][]string{
+	PaymentStatusPending: {PaymentStatusPaid, PaymentStatusCancelled, PaymentStatusOverdue},
+	PaymentStatusOverdue: {PaymentStatusPaid, PaymentStatusCancelled},
+	PaymentStatusPaid:    {PaymentStatusRefunded},
+}
+
+// CanTransitionPayment melaporkan apakah status pembayaran boleh berubah dari from ke to.
+func CanTransitionPayment(from, to string) bool {
+	for _, allowed := range paymentTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
 // Payment merepresentasikan satu data pembayaran
 type Payment struct {
-	ID           int       `json:"id"`
-	CustomerName string    `json:"customer_name"`
-	Amount       float64   `json:"amount"`
-	Status       string    `json:"status"`
-	PaymentDate  time.Time `json:"payment_date"`
-}
\ No newline at end of file
+	ID           int        `json:"id"`
+	CustomerID   *int       `json:"customer_id"`
+	CustomerName string     `json:"customer_name"`
+	Amount       float64    `json:"amount"`
+	Status       string     `json:"status"`
+	PaymentDate  time.Time  `json:"payment_date"`
+	DueDate      *time.Time `json:"due_date"`
+	// ReconciliationStatus menunjukkan apakah pembayaran sudah dicocokkan dengan
+	// baris mutasi rekening bank.
+	ReconciliationStatus string `json:"reconciliation_status"`
+}
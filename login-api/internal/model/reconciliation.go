@@ -0,0 +1,50 @@
+package model
+
+import "time"
+
+// Status baris mutasi rekening.
+const (
+	StatementLineUnmatched = "belum_cocok"
+	StatementLineReview    = "perlu_tinjauan"
+	StatementLineMatched   = "cocok"
+	// StatementLineIgnored diberikan kepada dana keluar, yang tidak dicocokkan
+	// dengan pembayaran pelanggan.
+	StatementLineIgnored = "diabaikan"
+)
+
+// Cara sebuah baris mutasi dicocokkan.
+const (
+	MatchMethodAuto   = "otomatis"
+	MatchMethodManual = "manual"
+)
+
+// BankStatement adalah satu berkas mutasi rekening yang diunggah beserta ringkasan
+// hasil pencocokannya.
+type BankStatement struct {
+	ID             int       `json:"id"`
+	FileName       string    `json:"file_name"`
+	Format         string    `json:"format"`
+	AccountID      string    `json:"account_id"`
+	UploadedAt     time.Time `json:"uploaded_at"`
+	LineCount      int       `json:"line_count"`
+	MatchedCount   int       `json:"matched_count"`
+	ReviewCount    int       `json:"review_count"`
+	UnmatchedCount int       `json:"unmatched_count"`
+}
+
+// BankStatementLine adalah satu transaksi pada mutasi rekening. Candidates hanya
+// diisi untuk baris yang perlu ditinjau.
+type BankStatementLine struct {
+	ID          int        `json:"id"`
+	StatementID int        `json:"statement_id"`
+	LineNo      int        `json:"line_no"`
+	BookingDate time.Time  `json:"booking_date"`
+	Amount      float64    `json:"amount"`
+	Reference   string     `json:"reference"`
+	Description string     `json:"description"`
+	Status      string     `json:"status"`
+	PaymentID   *int       `json:"payment_id"`
+	MatchMethod string     `json:"match_method"`
+	MatchedAt   *time.Time `json:"matched_at"`
+	Candidates  []Payment  `json:"candidates,omitempty"`
+}
@@ -0,0 +1,30 @@
+package model
+
+import "time"
+
+// Periode laporan terjadwal. Laporan selalu mencakup periode lengkap terakhir
+// sebelum waktu pengiriman: kemarin atau minggu lalu (Senin sampai Minggu).
+const (
+	ReportPeriodDay  = "day"
+	ReportPeriodWeek = "week"
+)
+
+// ReportSubscription adalah langganan laporan dashboard lewat email milik seorang
+// pengguna. Schedule adalah ekspresi cron yang dievaluasi pada Timezone.
+type ReportSubscription struct {
+	ID        int        `json:"id"`
+	Email     string     `json:"email"`
+	Period    string     `json:"period"`
+	Schedule  string     `json:"schedule"`
+	Timezone  string     `json:"timezone"`
+	Active    bool       `json:"active"`
+	NextRunAt time.Time  `json:"next_run_at"`
+	LastRunAt *time.Time `json:"last_run_at"`
+	LastError string     `json:"last_error"`
+	// Attempts adalah jumlah kegagalan beruntun untuk jadwal NextRunAt, dan
+	// RetryAt waktu percobaan ulang berikutnya bila pengiriman terakhir gagal.
+	Attempts  int        `json:"attempts"`
+	RetryAt   *time.Time `json:"retry_at"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
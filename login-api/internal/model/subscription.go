@@ -0,0 +1,69 @@
+package model
+
+import "time"
+
+// Status langganan.
+const (
+	SubscriptionStatusActive    = "Aktif"
+	SubscriptionStatusPaused    = "Dijeda"
+	SubscriptionStatusCancelled = "Dibatalkan"
+)
+
+// Satuan interval jadwal langganan.
+const (
+	IntervalDay   = "day"
+	IntervalWeek  = "week"
+	IntervalMonth = "month"
+)
+
+// Subscription merepresentasikan tagihan berulang milik seorang pelanggan.
+// Jadwal "bulanan" adalah IntervalMonth x 1, "mingguan" IntervalWeek x 1, dan
+// interval kustom memakai kombinasi satuan dan jumlah lainnya.
+type Subscription struct {
+	ID             int       `json:"id"`
+	CustomerID     int       `json:"customer_id"`
+	CustomerName   string    `json:"customer_name"`
+	Description    string    `json:"description"`
+	Amount         float64   `json:"amount"`
+	IntervalUnit   string    `json:"interval_unit"`
+	IntervalCount  int       `json:"interval_count"`
+	StartDate      time.Time `json:"start_date"`
+	GeneratedCount int       `json:"generated_count"`
+	NextDueDate    time.Time `json:"next_due_date"`
+	Status         string    `json:"status"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// Occurrence mengembalikan tanggal jatuh tempo ke-n (dimulai dari 0) pada jadwal.
+// Untuk interval bulanan, tanggal yang tidak ada pada bulan tujuan dipotong ke
+// akhir bulan (31 Jan -> 28/29 Feb -> 31 Mar), tanpa bergeser permanen.
+func (s Subscription) Occurrence(n int) time.Time {
+	start := s.StartDate
+	step := n * s.IntervalCount
+
+	switch s.IntervalUnit {
+	case IntervalDay:
+		return start.AddDate(0, 0, step)
+	case IntervalWeek:
+		return start.AddDate(0, 0, 7*step)
+	default:
+		firstOfMonth := time.Date(start.Year(), start.Month()+time.Month(step), 1, 0, 0, 0, 0, start.Location())
+		lastDay := firstOfMonth.AddDate(0, 1, -1).Day()
+		day := start.Day()
+		if day > lastDay {
+			day = lastDay
+		}
+		return time.Date(firstOfMonth.Year(), firstOfMonth.Month(), day, 0, 0, 0, 0, start.Location())
+	}
+}
+
+// UpcomingPayment adalah tagihan langganan yang akan dibuat pada tanggal jatuh tempo.
+type UpcomingPayment struct {
+	SubscriptionID int       `json:"subscription_id"`
+	CustomerID     int       `json:"customer_id"`
+	CustomerName   string    `json:"customer_name"`
+	Description    string    `json:"description"`
+	Amount         float64   `json:"amount"`
+	DueDate        time.Time `json:"due_date"`
+}
@@ -0,0 +1,38 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSubscriptionOccurrence(t *testing.T) {
+	date := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name string
+		sub  Subscription
+		n    int
+		want time.Time
+	}{
+		{"harian pertama", Subscription{IntervalUnit: IntervalDay, IntervalCount: 1, StartDate: date(2026, time.March, 10)}, 0, date(2026, time.March, 10)},
+		{"setiap 3 hari", Subscription{IntervalUnit: IntervalDay, IntervalCount: 3, StartDate: date(2026, time.February, 27)}, 1, date(2026, time.March, 2)},
+		{"mingguan", Subscription{IntervalUnit: IntervalWeek, IntervalCount: 1, StartDate: date(2026, time.December, 28)}, 1, date(2027, time.January, 4)},
+		{"dua mingguan", Subscription{IntervalUnit: IntervalWeek, IntervalCount: 2, StartDate: date(2026, time.March, 2)}, 3, date(2026, time.April, 13)},
+		{"bulanan", Subscription{IntervalUnit: IntervalMonth, IntervalCount: 1, StartDate: date(2026, time.January, 15)}, 1, date(2026, time.February, 15)},
+		{"31 Januari ke Februari", Subscription{IntervalUnit: IntervalMonth, IntervalCount: 1, StartDate: date(2026, time.January, 31)}, 1, date(2026, time.February, 28)},
+		{"31 Januari ke Februari kabisat", Subscription{IntervalUnit: IntervalMonth, IntervalCount: 1, StartDate: date(2028, time.January, 31)}, 1, date(2028, time.February, 29)},
+		// Pemotongan ke akhir Februari tidak bergeser permanen.
+		{"31 Januari ke Maret", Subscription{IntervalUnit: IntervalMonth, IntervalCount: 1, StartDate: date(2026, time.January, 31)}, 2, date(2026, time.March, 31)},
+		{"31 Januari ke April", Subscription{IntervalUnit: IntervalMonth, IntervalCount: 1, StartDate: date(2026, time.January, 31)}, 3, date(2026, time.April, 30)},
+		{"30 Agustus ke Februari", Subscription{IntervalUnit: IntervalMonth, IntervalCount: 6, StartDate: date(2026, time.August, 30)}, 1, date(2027, time.February, 28)},
+		{"triwulanan lintas tahun", Subscription{IntervalUnit: IntervalMonth, IntervalCount: 3, StartDate: date(2026, time.November, 30)}, 1, date(2027, time.February, 28)},
+		{"tahunan dari 29 Februari", Subscription{IntervalUnit: IntervalMonth, IntervalCount: 12, StartDate: date(2028, time.February, 29)}, 1, date(2029, time.February, 28)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.sub.Occurrence(tt.n); !got.Equal(tt.want) {
+				t.Errorf("Occurrence(%d) = %s, want %s", tt.n, got.Format("2006-01-02"), tt.want.Format("2006-01-02"))
+			}
+		})
+	}
+}
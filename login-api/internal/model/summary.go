@@ -1,8 +1,49 @@
 package model
 
+import "time"
+
 // DashboardSummary merepresentasikan data ringkasan untuk dashboard.
 type DashboardSummary struct {
 	TotalRevenue      float64 `json:"total_revenue"`
 	CompletedPayments int64   `json:"completed_payments"`
 	PendingPayments   int64   `json:"pending_payments"`
-}
\ No newline at end of file
+	OverduePayments   int64   `json:"overdue_payments"`
+}
+
+// Periode ringkasan dashboard yang dapat dibandingkan dengan periode sebelumnya.
+const (
+	SummaryPeriodToday  = "today"
+	SummaryPeriodWeek   = "week"
+	SummaryPeriodMonth  = "month"
+	SummaryPeriodCustom = "custom"
+)
+
+// PeriodSummary adalah ringkasan dashboard untuk rentang [From, To).
+type PeriodSummary struct {
+	DashboardSummary
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+// MetricChange adalah selisih sebuah metrik terhadap periode sebelumnya. Percent
+// bernilai null bila nilai periode sebelumnya nol.
+type MetricChange struct {
+	Absolute float64  `json:"absolute"`
+	Percent  *float64 `json:"percent"`
+}
+
+// SummaryChanges memuat perubahan metrik utama terhadap periode sebelumnya.
+type SummaryChanges struct {
+	TotalRevenue      MetricChange `json:"total_revenue"`
+	CompletedPayments MetricChange `json:"completed_payments"`
+	PendingPayments   MetricChange `json:"pending_payments"`
+}
+
+// SummaryComparison membandingkan ringkasan sebuah periode dengan periode
+// sebelumnya yang setara.
+type SummaryComparison struct {
+	Period   string         `json:"period"`
+	Current  PeriodSummary  `json:"current"`
+	Previous PeriodSummary  `json:"previous"`
+	Changes  SummaryChanges `json:"changes"`
+}
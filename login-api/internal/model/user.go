@@ -1,10 +1,29 @@
 package model
 
-import "github.com/golang-jwt/jwt/v5"
+import (
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Peran pengguna.
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
+// Roles mendaftar semua peran yang dikenali.
+var Roles = []string{RoleUser, RoleAdmin}
 
 type User struct {
 	Email        string `json:"email"`
 	PasswordHash string `json:"-"`
+	Role         string `json:"role"`
+	// DisabledAt terisi berarti akun dinonaktifkan dan tidak dapat login.
+	DisabledAt *time.Time `json:"disabled_at"`
+	// SessionsRevokedAt adalah batas waktu terbit token; token yang lebih lama ditolak.
+	SessionsRevokedAt *time.Time `json:"-"`
+	CreatedAt         time.Time  `json:"created_at"`
 }
 
 type Credentials struct {
@@ -14,6 +33,7 @@ type Credentials struct {
 
 type Claims struct {
 	Email string `json:"email"`
+	Role  string `json:"role,omitempty"`
 	jwt.RegisteredClaims
 }
 
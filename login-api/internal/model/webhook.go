@@ -0,0 +1,46 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Status pengiriman webhook keluar.
+const (
+	WebhookDeliveryPending   = "tertunda"
+	WebhookDeliveryDelivered = "terkirim"
+	WebhookDeliveryFailed    = "gagal"
+)
+
+// WebhookSubscription adalah endpoint milik sistem lain (misalnya ERP) yang ingin
+// menerima event. Secret hanya ditampilkan saat langganan dibuat.
+type WebhookSubscription struct {
+	ID             int       `json:"id"`
+	OrganizationID string    `json:"organization_id"`
+	URL            string    `json:"url"`
+	Secret         string    `json:"-"`
+	EventTypes     []string  `json:"event_types"`
+	Active         bool      `json:"active"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// WebhookDelivery adalah satu pengiriman event ke sebuah WebhookSubscription.
+type WebhookDelivery struct {
+	ID             int             `json:"id"`
+	SubscriptionID int             `json:"subscription_id"`
+	EventID        string          `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at"`
+	LastStatusCode *int            `json:"last_status_code"`
+	LastError      string          `json:"last_error"`
+	CreatedAt      time.Time       `json:"created_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at"`
+
+	// URL dan Secret diisi saat pengiriman diklaim oleh pekerja.
+	URL    string `json:"-"`
+	Secret string `json:"-"`
+}
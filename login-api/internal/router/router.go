@@ -3,39 +3,141 @@ package router
 import (
 	"login-api/internal/handler"
 	"login-api/internal/middleware"
+	"login-api/internal/model"
 	"net/http"
 
 	"github.com/gorilla/mux"
 	"github.com/rs/cors"
 )
 
-func NewRouter(authHandler *handler.AuthHandler, paymentHandler *handler.PaymentHandler, dashboardHandler *handler.DashboardHandler) http.Handler {
+// Handlers mengelompokkan seluruh handler yang didaftarkan ke router.
+type Handlers struct {
+	Auth           *handler.AuthHandler
+	Payment        *handler.PaymentHandler
+	Dashboard      *handler.DashboardHandler
+	Invoice        *handler.InvoiceHandler
+	Customer       *handler.CustomerHandler
+	Subscription   *handler.SubscriptionHandler
+	Receivable     *handler.ReceivableHandler
+	Gateway        *handler.GatewayHandler
+	Webhook        *handler.WebhookHandler
+	Reconciliation *handler.ReconciliationHandler
+	Preference     *handler.PreferenceHandler
+	Live           *handler.LiveHandler
+	Alert          *handler.AlertHandler
+	Report         *handler.ReportHandler
+}
+
+// NewRouter mendaftarkan route untuk setiap handler di h. Handler selain Auth
+// boleh nil, misalnya pada mode demo; route miliknya tidak didaftarkan dan
+// permintaan ke sana dijawab 404.
+func NewRouter(h Handlers) http.Handler {
 	r := mux.NewRouter()
+	r.Use(middleware.Metrics)
 
-	loginHandler := middleware.RateLimiterMiddleware(http.HandlerFunc(authHandler.LoginHandler))
+	loginHandler := middleware.RateLimiterMiddleware(http.HandlerFunc(h.Auth.LoginHandler))
 	r.Handle("/api/login", loginHandler).Methods("POST")
-	r.HandleFunc("/api/register", authHandler.RegisterHandler).Methods("POST")
-	r.HandleFunc("/api/refresh", authHandler.RefreshTokenHandler).Methods("POST")
-	r.HandleFunc("/api/logout", authHandler.LogoutHandler).Methods("POST")
+	r.HandleFunc("/api/register", h.Auth.RegisterHandler).Methods("POST")
+	r.HandleFunc("/api/refresh", h.Auth.RefreshTokenHandler).Methods("POST")
+	r.HandleFunc("/api/logout", h.Auth.LogoutHandler).Methods("POST")
+	if h.Gateway != nil {
+		r.HandleFunc("/api/webhooks/gateway", h.Gateway.WebhookHandler).Methods("POST")
+	}
 
 	protectedRoutes := r.PathPrefix("/api").Subrouter()
-	jwtAuthMiddleware := middleware.NewJwtMiddleware(authHandler.JwtKey)
+	jwtAuthMiddleware := middleware.NewJwtMiddleware(h.Auth.JwtKey)
 	protectedRoutes.Use(jwtAuthMiddleware)
 
 	protectedRoutes.HandleFunc("/status", handler.StatusHandler).Methods("GET")
-	protectedRoutes.HandleFunc("/user/password", authHandler.ChangePasswordHandler).Methods("PUT")
-	
-	protectedRoutes.HandleFunc("/dashboard/summary", dashboardHandler.GetSummaryHandler).Methods("GET")
-	protectedRoutes.HandleFunc("/dashboard/chart", dashboardHandler.GetChartDataHandler).Methods("GET")
-	protectedRoutes.HandleFunc("/payments", paymentHandler.GetPaymentsHandler).Methods("GET")
+	protectedRoutes.HandleFunc("/user/password", h.Auth.ChangePasswordHandler).Methods("PUT")
+	if h.Preference != nil {
+		protectedRoutes.HandleFunc("/user/preferences", h.Preference.GetPreferencesHandler).Methods("GET")
+		protectedRoutes.HandleFunc("/user/preferences", h.Preference.UpdatePreferencesHandler).Methods("PUT")
+	}
+
+	if h.Dashboard != nil {
+		protectedRoutes.HandleFunc("/dashboard/summary", h.Dashboard.GetSummaryHandler).Methods("GET")
+		protectedRoutes.HandleFunc("/dashboard/chart", h.Dashboard.GetChartDataHandler).Methods("GET")
+		protectedRoutes.HandleFunc("/dashboard/breakdown/status", h.Dashboard.GetStatusBreakdownHandler).Methods("GET")
+		protectedRoutes.HandleFunc("/dashboard/breakdown/customers", h.Dashboard.GetTopCustomersHandler).Methods("GET")
+		protectedRoutes.HandleFunc("/dashboard/forecast", h.Dashboard.GetForecastHandler).Methods("GET")
+	}
+	if h.Live != nil {
+		protectedRoutes.HandleFunc("/dashboard/stream", h.Live.StreamDashboardHandler).Methods("GET")
+	}
+	if h.Receivable != nil {
+		protectedRoutes.HandleFunc("/reports/aging", h.Receivable.GetAgingReportHandler).Methods("GET")
+	}
+	if h.Report != nil {
+		protectedRoutes.HandleFunc("/reports/subscriptions", h.Report.ListReportSubscriptionsHandler).Methods("GET")
+		protectedRoutes.HandleFunc("/reports/subscriptions", h.Report.CreateReportSubscriptionHandler).Methods("POST")
+		protectedRoutes.HandleFunc("/reports/subscriptions/{id:[0-9]+}", h.Report.UpdateReportSubscriptionHandler).Methods("PUT")
+		protectedRoutes.HandleFunc("/reports/subscriptions/{id:[0-9]+}", h.Report.DeleteReportSubscriptionHandler).Methods("DELETE")
+	}
+	if h.Payment != nil {
+		protectedRoutes.HandleFunc("/payments", h.Payment.GetPaymentsHandler).Methods("GET")
+	}
+	if h.Invoice != nil {
+		protectedRoutes.HandleFunc("/payments/{id:[0-9]+}/invoice", h.Invoice.GetInvoicePDFHandler).Methods("GET")
+	}
+
+	if h.Customer != nil {
+		protectedRoutes.HandleFunc("/customers", h.Customer.ListCustomersHandler).Methods("GET")
+		protectedRoutes.HandleFunc("/customers", h.Customer.CreateCustomerHandler).Methods("POST")
+		protectedRoutes.HandleFunc("/customers/{id:[0-9]+}", h.Customer.UpdateCustomerHandler).Methods("PUT")
+		protectedRoutes.HandleFunc("/customers/{id:[0-9]+}/merge", h.Customer.MergeCustomersHandler).Methods("POST")
+		protectedRoutes.HandleFunc("/customers/{id:[0-9]+}/payments", h.Customer.GetCustomerPaymentsHandler).Methods("GET")
+	}
+
+	if h.Gateway != nil {
+		protectedRoutes.HandleFunc("/gateway-events", h.Gateway.ListEventsHandler).Methods("GET")
+		protectedRoutes.HandleFunc("/gateway-events/{id:[0-9]+}/replay", h.Gateway.ReplayEventHandler).Methods("POST")
+	}
+
+	if h.Reconciliation != nil {
+		protectedRoutes.HandleFunc("/reconciliation/statements", h.Reconciliation.ListStatementsHandler).Methods("GET")
+		protectedRoutes.HandleFunc("/reconciliation/statements", h.Reconciliation.UploadStatementHandler).Methods("POST")
+		protectedRoutes.HandleFunc("/reconciliation/statements/{id:[0-9]+}/lines", h.Reconciliation.ListLinesHandler).Methods("GET")
+		protectedRoutes.HandleFunc("/reconciliation/review", h.Reconciliation.ReviewQueueHandler).Methods("GET")
+		protectedRoutes.HandleFunc("/reconciliation/lines/{id:[0-9]+}/match", h.Reconciliation.MatchLineHandler).Methods("POST")
+		protectedRoutes.HandleFunc("/reconciliation/lines/{id:[0-9]+}/unmatch", h.Reconciliation.UnmatchLineHandler).Methods("POST")
+	}
+
+	if h.Alert != nil {
+		protectedRoutes.HandleFunc("/alerts", h.Alert.ListAlertsHandler).Methods("GET")
+		protectedRoutes.HandleFunc("/alerts/{id:[0-9]+}/acknowledge", h.Alert.AcknowledgeAlertHandler).Methods("POST")
+	}
+
+	if h.Webhook != nil {
+		// Webhook mengirim data ke URL pilihan pengguna, sehingga pengelolaannya
+		// dibatasi untuk admin.
+		webhookRoutes := protectedRoutes.NewRoute().Subrouter()
+		webhookRoutes.Use(middleware.RequireRole(model.RoleAdmin))
+		webhookRoutes.HandleFunc("/webhooks", h.Webhook.ListWebhooksHandler).Methods("GET")
+		webhookRoutes.HandleFunc("/webhooks", h.Webhook.CreateWebhookHandler).Methods("POST")
+		webhookRoutes.HandleFunc("/webhooks/{id:[0-9]+}", h.Webhook.UpdateWebhookHandler).Methods("PUT")
+		webhookRoutes.HandleFunc("/webhooks/{id:[0-9]+}", h.Webhook.DeleteWebhookHandler).Methods("DELETE")
+		webhookRoutes.HandleFunc("/webhooks/{id:[0-9]+}/deliveries", h.Webhook.ListDeliveriesHandler).Methods("GET")
+		webhookRoutes.HandleFunc("/webhook-deliveries/{id:[0-9]+}/redeliver", h.Webhook.RedeliverHandler).Methods("POST")
+	}
+
+	if h.Subscription != nil {
+		protectedRoutes.HandleFunc("/dashboard/upcoming", h.Subscription.GetUpcomingPaymentsHandler).Methods("GET")
+		protectedRoutes.HandleFunc("/subscriptions", h.Subscription.ListSubscriptionsHandler).Methods("GET")
+		protectedRoutes.HandleFunc("/subscriptions", h.Subscription.CreateSubscriptionHandler).Methods("POST")
+		protectedRoutes.HandleFunc("/subscriptions/{id:[0-9]+}/pause", h.Subscription.PauseSubscriptionHandler).Methods("POST")
+		protectedRoutes.HandleFunc("/subscriptions/{id:[0-9]+}/resume", h.Subscription.ResumeSubscriptionHandler).Methods("POST")
+		protectedRoutes.HandleFunc("/subscriptions/{id:[0-9]+}/cancel", h.Subscription.CancelSubscriptionHandler).Methods("POST")
+	}
 
 	c := cors.New(cors.Options{
 		AllowedOrigins:   []string{"http://localhost:5173"},
-		AllowedMethods:   []string{"GET", "POST", "OPTIONS", "PUT"},
-		AllowedHeaders:   []string{"Content-Type"},
+		AllowedMethods:   []string{"GET", "POST", "OPTIONS", "PUT", "DELETE"},
+		AllowedHeaders:   []string{"Content-Type", "Last-Event-ID", middleware.RequestIDHeader},
+		ExposedHeaders:   []string{"Content-Disposition", middleware.RequestIDHeader},
 		AllowCredentials: true,
 	})
 
-	handler := c.Handler(r)
+	handler := middleware.RequestLogger(c.Handler(r))
 	return handler
-}
\ No newline at end of file
+}
@@ -0,0 +1,189 @@
+package seed
+
+import (
+	"fmt"
+	"login-api/internal/model"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+)
+
+var (
+	namePrefixes = []string{"PT", "CV", "UD", "Toko", "Koperasi"}
+	nameFirst    = []string{
+		"Sinar", "Maju", "Sumber", "Nusantara", "Cahaya", "Karya", "Samudra", "Griya",
+		"Berkah", "Mitra", "Bintang", "Harapan", "Mulia", "Sentosa", "Prima", "Andalan",
+	}
+	nameSecond = []string{
+		"Jaya", "Bersama", "Rezeki", "Digital", "Timur", "Mandiri", "Logistik", "Abadi",
+		"Sejahtera", "Utama", "Makmur", "Lestari", "Persada", "Teknik", "Niaga",
+	}
+)
+
+// Dataset adalah data contoh hasil Generate.
+type Dataset struct {
+	Customers []model.Customer
+	// Payments memakai CustomerName dari Customers; CustomerID diisi saat disimpan.
+	Payments []Payment
+	// Users adalah email pengguna contoh. Pengguna pertama berperan admin.
+	Users []string
+}
+
+// Payment adalah pembayaran contoh milik Customers[Customer].
+type Payment struct {
+	Customer    int
+	Amount      float64
+	Status      string
+	PaymentDate time.Time
+	DueDate     time.Time
+}
+
+// Generate membentuk data contoh dari opts. Hasilnya hanya bergantung pada opts,
+// sehingga seed dan tanggal akhir yang sama selalu menghasilkan data yang sama.
+func Generate(opts Options) Dataset {
+	rng := rand.New(rand.NewSource(opts.Seed))
+
+	var ds Dataset
+	ds.Customers = customers(rng, opts.Customers)
+	ds.Payments = payments(rng, opts, len(ds.Customers))
+	for i := 0; i < opts.Users; i++ {
+		if i == 0 {
+			ds.Users = append(ds.Users, "admin@demo.local")
+			continue
+		}
+		ds.Users = append(ds.Users, fmt.Sprintf("user%d@demo.local", i))
+	}
+	return ds
+}
+
+// customers membentuk n pelanggan dengan nama unik. Kombinasi nama dipakai tanpa
+// pengulangan; bila n melebihi jumlah kombinasi, nama diberi nomor cabang.
+func customers(rng *rand.Rand, n int) []model.Customer {
+	total := len(namePrefixes) * len(nameFirst) * len(nameSecond)
+	order := rng.Perm(total)
+
+	list := make([]model.Customer, n)
+	for i := range list {
+		k := order[i%total]
+		prefix := namePrefixes[k%len(namePrefixes)]
+		k /= len(namePrefixes)
+		first := nameFirst[k%len(nameFirst)]
+		second := nameSecond[k/len(nameFirst)]
+
+		name := fmt.Sprintf("%s %s %s", prefix, first, second)
+		slug := strings.ToLower(first + second)
+		if i >= total {
+			branch := i/total + 1
+			name = fmt.Sprintf("%s Cabang %d", name, branch)
+			slug = fmt.Sprintf("%s%d", slug, branch)
+		}
+		list[i] = model.Customer{
+			Name:  name,
+			Email: fmt.Sprintf("keuangan@%s.co.id", slug),
+			Phone: fmt.Sprintf("08%010d", rng.Int63n(1e10)),
+		}
+	}
+	return list
+}
+
+// payments menyebar pembayaran pada opts.Months bulan sebelum opts.End. Volume
+// harian tumbuh perlahan dan turun pada akhir pekan; setiap pelanggan memiliki
+// frekuensi dan nominal khasnya sendiri.
+func payments(rng *rand.Rand, opts Options, customerCount int) []Payment {
+	if customerCount == 0 {
+		return nil
+	}
+
+	loc := opts.End.Location()
+	end := time.Date(opts.End.Year(), opts.End.Month(), opts.End.Day(), 0, 0, 0, 0, loc)
+	start := end.AddDate(0, -opts.Months, 0)
+
+	var days []time.Time
+	for d := start; d.Before(end); d = d.AddDate(0, 0, 1) {
+		days = append(days, d)
+	}
+	dayWeights := make([]float64, len(days))
+	for i, d := range days {
+		w := 1 + 0.5*float64(i)/float64(len(days))
+		switch d.Weekday() {
+		case time.Saturday:
+			w *= 0.5
+		case time.Sunday:
+			w *= 0.25
+		}
+		dayWeights[i] = w
+	}
+	pickDay := weighted(dayWeights)
+
+	// Sebagian kecil pelanggan menyumbang sebagian besar transaksi.
+	customerWeights := make([]float64, customerCount)
+	typicalAmount := make([]float64, customerCount)
+	for i := range customerWeights {
+		customerWeights[i] = 1 / math.Pow(float64(i+1), 0.8)
+		typicalAmount[i] = math.Exp(rng.NormFloat64()*0.8 + math.Log(1500000))
+	}
+	pickCustomer := weighted(customerWeights)
+
+	list := make([]Payment, opts.Payments)
+	for i := range list {
+		day := days[pickDay(rng)]
+		paymentDate := day.Add(time.Duration(8*60+rng.Intn(10*60)) * time.Minute)
+		dueDate := day.AddDate(0, 0, []int{7, 14, 30}[rng.Intn(3)])
+		customer := pickCustomer(rng)
+
+		amount := typicalAmount[customer] * (0.7 + 0.6*rng.Float64())
+		list[i] = Payment{
+			Customer:    customer,
+			Amount:      math.Max(10000, math.Round(amount/1000)*1000),
+			Status:      status(rng, !dueDate.After(end)),
+			PaymentDate: paymentDate,
+			DueDate:     dueDate,
+		}
+	}
+
+	sort.SliceStable(list, func(i, j int) bool { return list[i].PaymentDate.Before(list[j].PaymentDate) })
+	return list
+}
+
+// status memilih status pembayaran. Pembayaran yang sudah lewat jatuh tempo
+// sebagian besar lunas dan sisanya terlambat, dibatalkan, atau dikembalikan;
+// yang belum jatuh tempo sebagian besar masih tertunda.
+func status(rng *rand.Rand, pastDue bool) string {
+	p := rng.Float64()
+	if pastDue {
+		switch {
+		case p < 0.86:
+			return model.PaymentStatusPaid
+		case p < 0.94:
+			return model.PaymentStatusOverdue
+		case p < 0.98:
+			return model.PaymentStatusCancelled
+		default:
+			return model.PaymentStatusRefunded
+		}
+	}
+	switch {
+	case p < 0.40:
+		return model.PaymentStatusPaid
+	case p < 0.98:
+		return model.PaymentStatusPending
+	default:
+		return model.PaymentStatusCancelled
+	}
+}
+
+// weighted mengembalikan fungsi yang memilih indeks dengan peluang sebanding
+// dengan weights.
+func weighted(weights []float64) func(*rand.Rand) int {
+	cumulative := make([]float64, len(weights))
+	var sum float64
+	for i, w := range weights {
+		sum += w
+		cumulative[i] = sum
+	}
+	return func(rng *rand.Rand) int {
+		return sort.SearchFloat64s(cumulative, rng.Float64()*sum)
+	}
+}
@@ -0,0 +1,131 @@
+package seed
+
+import (
+	"login-api/internal/model"
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func testOptions(t *testing.T) Options {
+	t.Helper()
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := DefaultOptions
+	opts.End = time.Date(2026, time.March, 12, 0, 0, 0, 0, jakarta)
+	return opts
+}
+
+func TestGenerateDeterministic(t *testing.T) {
+	opts := testOptions(t)
+
+	first, second := Generate(opts), Generate(opts)
+	if !reflect.DeepEqual(first, second) {
+		t.Fatal("Generate() dengan opsi yang sama menghasilkan data berbeda")
+	}
+
+	other := opts
+	other.Seed++
+	if reflect.DeepEqual(first, Generate(other)) {
+		t.Error("Generate() dengan seed berbeda menghasilkan data yang sama")
+	}
+}
+
+func TestGenerateShape(t *testing.T) {
+	opts := testOptions(t)
+	ds := Generate(opts)
+
+	if len(ds.Customers) != opts.Customers || len(ds.Payments) != opts.Payments {
+		t.Fatalf("Generate() = %d pelanggan, %d pembayaran, want %d, %d", len(ds.Customers), len(ds.Payments), opts.Customers, opts.Payments)
+	}
+	want := []string{"admin@demo.local", "user1@demo.local", "user2@demo.local"}
+	if !reflect.DeepEqual(ds.Users, want) {
+		t.Errorf("Users = %v, want %v", ds.Users, want)
+	}
+
+	names := map[string]bool{}
+	for _, c := range ds.Customers {
+		if names[c.Name] {
+			t.Errorf("nama pelanggan %q muncul lebih dari sekali", c.Name)
+		}
+		names[c.Name] = true
+	}
+
+	start := opts.End.AddDate(0, -opts.Months, 0)
+	for i, p := range ds.Payments {
+		local := p.PaymentDate.In(opts.End.Location())
+		if p.PaymentDate.Before(start) || !p.PaymentDate.Before(opts.End) {
+			t.Errorf("pembayaran %d pada %v di luar rentang [%v, %v)", i, p.PaymentDate, start, opts.End)
+		}
+		if local.Hour() < 8 || local.Hour() >= 18 {
+			t.Errorf("pembayaran %d pada %v di luar jam kerja", i, local)
+		}
+		if i > 0 && p.PaymentDate.Before(ds.Payments[i-1].PaymentDate) {
+			t.Errorf("pembayaran %d tidak urut berdasarkan tanggal", i)
+		}
+		if p.Customer < 0 || p.Customer >= len(ds.Customers) || p.Amount < 10000 {
+			t.Errorf("pembayaran %d = %+v tidak valid", i, p)
+		}
+	}
+}
+
+func TestGenerateWithoutCustomers(t *testing.T) {
+	opts := testOptions(t)
+	opts.Customers = 0
+	if ds := Generate(opts); len(ds.Payments) != 0 {
+		t.Errorf("Generate() tanpa pelanggan = %d pembayaran, want 0", len(ds.Payments))
+	}
+}
+
+func TestStatusDistribution(t *testing.T) {
+	const n = 20000
+	tests := []struct {
+		name    string
+		pastDue bool
+		want    map[string]float64
+	}{
+		{"sudah jatuh tempo", true, map[string]float64{
+			model.PaymentStatusPaid: 0.86, model.PaymentStatusOverdue: 0.08,
+			model.PaymentStatusCancelled: 0.04, model.PaymentStatusRefunded: 0.02,
+		}},
+		{"belum jatuh tempo", false, map[string]float64{
+			model.PaymentStatusPaid: 0.40, model.PaymentStatusPending: 0.58, model.PaymentStatusCancelled: 0.02,
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rng := rand.New(rand.NewSource(1))
+			counts := map[string]int{}
+			for i := 0; i < n; i++ {
+				counts[status(rng, tt.pastDue)]++
+			}
+			for s := range counts {
+				if _, ok := tt.want[s]; !ok {
+					t.Errorf("status %q tidak diharapkan", s)
+				}
+			}
+			for s, p := range tt.want {
+				if got := float64(counts[s]) / n; math.Abs(got-p) > 0.015 {
+					t.Errorf("proporsi %s = %.3f, want %.2f", s, got, p)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateStatusByDueDate(t *testing.T) {
+	opts := testOptions(t)
+	for i, p := range Generate(opts).Payments {
+		pastDue := !p.DueDate.After(opts.End)
+		if pastDue && p.Status == model.PaymentStatusPending {
+			t.Errorf("pembayaran %d sudah jatuh tempo pada %v tetapi masih %s", i, p.DueDate, p.Status)
+		}
+		if !pastDue && (p.Status == model.PaymentStatusOverdue || p.Status == model.PaymentStatusRefunded) {
+			t.Errorf("pembayaran %d belum jatuh tempo (%v) tetapi %s", i, p.DueDate, p.Status)
+		}
+	}
+}
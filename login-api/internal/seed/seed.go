@@ -0,0 +1,179 @@
+// Package seed mengisi database dengan data contoh yang deterministik untuk demo
+// dan pengembangan lokal: pelanggan, pembayaran beberapa bulan terakhir, dan
+// pengguna.
+package seed
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"login-api/internal/model"
+	"login-api/internal/storage/postgres"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// DemoPassword adalah kata sandi semua pengguna contoh.
+const DemoPassword = "Demo1234!"
+
+// Options mengatur jumlah dan sebaran data contoh.
+type Options struct {
+	Customers int `json:"customers"`
+	Payments  int `json:"payments"`
+	Users     int `json:"users"`
+	// Months adalah rentang bulan sebelum End tempat pembayaran disebar.
+	Months int   `json:"months"`
+	Seed   int64 `json:"seed"`
+	// End adalah tanggal akhir sebaran pembayaran (eksklusif). Zona waktunya
+	// menentukan batas hari dan jam kerja pembayaran.
+	End time.Time `json:"end"`
+}
+
+// DefaultOptions adalah volume data contoh bawaan.
+var DefaultOptions = Options{Customers: 40, Payments: 2000, Users: 3, Months: 12, Seed: 42}
+
+// Result merangkum data yang ditambahkan oleh Run.
+type Result struct {
+	Customers int
+	Payments  int
+	Users     int
+	// Replaced adalah jumlah pembayaran contoh lama yang dihapus karena diganti.
+	Replaced int
+	// Skipped bernilai true bila data dengan opsi yang sama sudah pernah diisi.
+	Skipped bool
+}
+
+// runKey mengidentifikasi data contoh tanpa memandang tanggal akhir. Pembayaran
+// contoh ditandai dengan kunci ini pada kolom seed_run.
+func (o Options) runKey() string {
+	return fmt.Sprintf("seed=%d customers=%d payments=%d users=%d months=%d",
+		o.Seed, o.Customers, o.Payments, o.Users, o.Months)
+}
+
+// fingerprint mengidentifikasi pengisian dengan opsi yang sama persis.
+func (o Options) fingerprint() string {
+	return fmt.Sprintf("%s end=%s", o.runKey(), o.End.Format("2006-01-02"))
+}
+
+// Run menyimpan data hasil Generate(opts) dalam satu transaksi. Menjalankan Run
+// lagi dengan opsi yang sama tidak menambah data. Bila hanya tanggal akhir yang
+// berbeda, misalnya karena bawaan "besok" bergeser, pembayaran contoh dari
+// pengisian sebelumnya dengan runKey yang sama dihapus dan diganti, kecuali yang
+// sudah memiliki faktur. Pelanggan dan pengguna yang sudah ada dipakai ulang,
+// bukan ditimpa. Event outbox tidak ditulis agar webhook dan sink lain tidak
+// menerima data contoh.
+func Run(ctx context.Context, db *pgxpool.Pool, opts Options) (Result, error) {
+	ds := Generate(opts)
+	runKey := opts.runKey()
+	fingerprint := opts.fingerprint()
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return Result{}, fmt.Errorf("kesalahan saat memulai transaksi data contoh: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Pengisian bersamaan menunggu giliran agar pemeriksaan fingerprint tidak balapan.
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock($1)`, postgres.DemoSeedLockKey); err != nil {
+		return Result{}, fmt.Errorf("kesalahan saat mengambil kunci data contoh: %w", err)
+	}
+	// Pengisian terakhir untuk runKey ini menentukan data yang sedang tersimpan.
+	var current string
+	err = tx.QueryRow(ctx, `
+        SELECT fingerprint FROM demo_seed_runs
+        WHERE seed_run = $1
+        ORDER BY created_at DESC
+        LIMIT 1`, runKey).Scan(&current)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return Result{}, fmt.Errorf("kesalahan saat memeriksa riwayat data contoh: %w", err)
+	}
+	if current == fingerprint {
+		return Result{Skipped: true}, nil
+	}
+
+	// Foreign key invoices menolak penghapusan pembayaran yang sudah diberi faktur.
+	var res Result
+	tag, err := tx.Exec(ctx, `
+        DELETE FROM payments p
+        WHERE p.seed_run = $1
+          AND NOT EXISTS (SELECT 1 FROM invoices i WHERE i.payment_id = p.id)`, runKey)
+	if err != nil {
+		return Result{}, fmt.Errorf("kesalahan saat menghapus pembayaran contoh lama: %w", err)
+	}
+	res.Replaced = int(tag.RowsAffected())
+
+	customerIDs := make([]int, len(ds.Customers))
+	for i, c := range ds.Customers {
+		var inserted bool
+		err := tx.QueryRow(ctx, `
+            INSERT INTO customers (name, normalized_name, email, phone)
+            VALUES ($1, $2, $3, $4)
+            ON CONFLICT (normalized_name) DO UPDATE SET normalized_name = EXCLUDED.normalized_name
+            RETURNING id, xmax = 0`,
+			c.Name, postgres.NormalizeCustomerName(c.Name), c.Email, c.Phone,
+		).Scan(&customerIDs[i], &inserted)
+		if err != nil {
+			return Result{}, fmt.Errorf("kesalahan saat menyimpan pelanggan contoh %s: %w", c.Name, err)
+		}
+		if inserted {
+			res.Customers++
+		}
+	}
+
+	rows := make([][]interface{}, len(ds.Payments))
+	for i, p := range ds.Payments {
+		rows[i] = []interface{}{customerIDs[p.Customer], ds.Customers[p.Customer].Name, p.Amount, p.Status, p.PaymentDate, p.DueDate, runKey}
+	}
+	n, err := tx.CopyFrom(ctx,
+		pgx.Identifier{"payments"},
+		[]string{"customer_id", "customer_name", "amount", "status", "payment_date", "due_date", "seed_run"},
+		pgx.CopyFromRows(rows))
+	if err != nil {
+		return Result{}, fmt.Errorf("kesalahan saat menyimpan pembayaran contoh: %w", err)
+	}
+	res.Payments = int(n)
+
+	if len(ds.Users) > 0 {
+		hash, err := bcrypt.GenerateFromPassword([]byte(DemoPassword), bcrypt.DefaultCost)
+		if err != nil {
+			return Result{}, err
+		}
+		for i, email := range ds.Users {
+			role := model.RoleUser
+			if i == 0 {
+				role = model.RoleAdmin
+			}
+			tag, err := tx.Exec(ctx, `
+                INSERT INTO users (email, password_hash, role) VALUES ($1, $2, $3)
+                ON CONFLICT (email) DO NOTHING`,
+				email, string(hash), role)
+			if err != nil {
+				return Result{}, fmt.Errorf("kesalahan saat menyimpan pengguna contoh %s: %w", email, err)
+			}
+			res.Users += int(tag.RowsAffected())
+		}
+	}
+
+	options, err := json.Marshal(opts)
+	if err != nil {
+		return Result{}, err
+	}
+	if _, err := tx.Exec(ctx, `
+        INSERT INTO demo_seed_runs (fingerprint, seed_run, options, customers, payments, users)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        ON CONFLICT (fingerprint) DO UPDATE
+        SET seed_run = EXCLUDED.seed_run, options = EXCLUDED.options, customers = EXCLUDED.customers,
+            payments = EXCLUDED.payments, users = EXCLUDED.users, created_at = NOW()`,
+		fingerprint, runKey, options, res.Customers, res.Payments, res.Users); err != nil {
+		return Result{}, fmt.Errorf("kesalahan saat mencatat riwayat data contoh: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return Result{}, fmt.Errorf("kesalahan saat menyimpan data contoh: %w", err)
+	}
+	return res, nil
+}
@@ -0,0 +1,41 @@
+package seed
+
+import (
+	"testing"
+	"time"
+)
+
+func TestOptionsRunKeyAndFingerprint(t *testing.T) {
+	base := Options{Customers: 40, Payments: 2000, Users: 3, Months: 12, Seed: 42, End: time.Date(2026, time.March, 12, 0, 0, 0, 0, time.UTC)}
+	if got, want := base.runKey(), "seed=42 customers=40 payments=2000 users=3 months=12"; got != want {
+		t.Errorf("runKey() = %q, want %q", got, want)
+	}
+	if got, want := base.fingerprint(), "seed=42 customers=40 payments=2000 users=3 months=12 end=2026-03-12"; got != want {
+		t.Errorf("fingerprint() = %q, want %q", got, want)
+	}
+
+	tests := []struct {
+		name            string
+		change          func(*Options)
+		sameRunKey      bool
+		sameFingerprint bool
+	}{
+		{"tanggal akhir berbeda", func(o *Options) { o.End = o.End.AddDate(0, 0, 1) }, true, false},
+		{"jam pada tanggal yang sama", func(o *Options) { o.End = o.End.Add(10 * time.Hour) }, true, true},
+		{"seed berbeda", func(o *Options) { o.Seed = 7 }, false, false},
+		{"jumlah pembayaran berbeda", func(o *Options) { o.Payments = 10 }, false, false},
+		{"rentang bulan berbeda", func(o *Options) { o.Months = 6 }, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := base
+			tt.change(&o)
+			if got := o.runKey() == base.runKey(); got != tt.sameRunKey {
+				t.Errorf("runKey() sama = %v, want %v", got, tt.sameRunKey)
+			}
+			if got := o.fingerprint() == base.fingerprint(); got != tt.sameFingerprint {
+				t.Errorf("fingerprint() sama = %v, want %v", got, tt.sameFingerprint)
+			}
+		})
+	}
+}
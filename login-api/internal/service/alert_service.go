@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"login-api/internal/anomaly"
+	"login-api/internal/mailer"
+	"login-api/internal/model"
+	"login-api/internal/storage"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Rentang data yang dibandingkan oleh detektor anomali.
+const (
+	// alertBaselineDays adalah jumlah hari sebelum hari yang diperiksa yang menjadi baseline harian.
+	alertBaselineDays = 28
+	// alertAmountBaselineDays adalah jumlah hari riwayat nominal pembayaran.
+	alertAmountBaselineDays = 90
+	// alertRecentWindow adalah rentang pembayaran terbaru yang diperiksa nominalnya.
+	alertRecentWindow = 24 * time.Hour
+)
+
+// AlertService mendeteksi anomali aktivitas pembayaran secara berkala, menyimpannya
+// sebagai peringatan, dan mengirim email ke Recipients bila diisi. Webhook
+// menerima peringatan melalui event alert.created dari outbox.
+type AlertService struct {
+	Store          storage.AlertStore
+	Payments       storage.PaymentStore
+	Mailer         mailer.Mailer
+	Recipients     []string
+	OrganizationID string
+	// Location menentukan batas hari untuk perbandingan harian.
+	Location   *time.Location
+	Thresholds anomaly.Thresholds
+	Now        func() time.Time
+}
+
+// NewAlertService membuat instance AlertService baru.
+func NewAlertService(store storage.AlertStore, payments storage.PaymentStore, m mailer.Mailer, recipients []string, orgID string, loc *time.Location) *AlertService {
+	return &AlertService{
+		Store:          store,
+		Payments:       payments,
+		Mailer:         m,
+		Recipients:     recipients,
+		OrganizationID: orgID,
+		Location:       loc,
+		Thresholds:     anomaly.DefaultThresholds,
+		Now:            time.Now,
+	}
+}
+
+// Detect memeriksa hari lengkap terakhir dan pembayaran 24 jam terakhir, lalu
+// menyimpan temuan baru. Mengembalikan peringatan yang baru dibuat.
+func (s *AlertService) Detect(ctx context.Context) ([]model.Alert, error) {
+	now := s.Now()
+	today := localMidnight(now, s.Location)
+
+	findings, err := s.detectDaily(ctx, today)
+	if err != nil {
+		return nil, err
+	}
+
+	recentFrom := now.Add(-alertRecentWindow)
+	stats, err := s.Store.GetAmountStats(ctx, recentFrom.AddDate(0, 0, -alertAmountBaselineDays), recentFrom)
+	if err != nil {
+		return nil, err
+	}
+	recent, err := s.Payments.ListPaymentsBetween(ctx, recentFrom, now)
+	if err != nil {
+		return nil, err
+	}
+	findings = append(findings, anomaly.LargePayments(stats, recent, s.Thresholds)...)
+
+	var created []model.Alert
+	for _, f := range findings {
+		alert, isNew, err := s.Store.CreateAlert(ctx, s.OrganizationID, f)
+		if err != nil {
+			return created, err
+		}
+		if isNew {
+			created = append(created, alert)
+			s.notify(alert)
+		}
+	}
+	return created, nil
+}
+
+// detectDaily membandingkan kemarin dengan baseline alertBaselineDays hari sebelumnya.
+func (s *AlertService) detectDaily(ctx context.Context, today time.Time) ([]anomaly.Finding, error) {
+	days, err := s.Store.GetDailyActivity(ctx, today.AddDate(0, 0, -alertBaselineDays-1), today, s.Location)
+	if err != nil {
+		return nil, err
+	}
+	if len(days) == 0 {
+		return nil, nil
+	}
+
+	baseline, latest := days[:len(days)-1], days[len(days)-1]
+	var findings []anomaly.Finding
+	if f := anomaly.RevenueDrop(baseline, latest, s.Thresholds); f != nil {
+		findings = append(findings, *f)
+	}
+	if f := anomaly.UnsettledSpike(baseline, latest, s.Thresholds); f != nil {
+		findings = append(findings, *f)
+	}
+	return findings, nil
+}
+
+// notify mengirim email peringatan. Kegagalan hanya dicatat karena peringatan
+// tetap tersedia melalui API.
+func (s *AlertService) notify(alert model.Alert) {
+	if len(s.Recipients) == 0 {
+		return
+	}
+
+	msg := mailer.Message{
+		To:      s.Recipients,
+		Subject: fmt.Sprintf("[%s] Peringatan aktivitas pembayaran: %s", strings.ToUpper(alert.Severity), alert.Kind),
+		Text:    fmt.Sprintf("%s\n\nTerdeteksi pada %s.\nDetail: %s\n", alert.Message, alert.DetectedAt.In(s.Location).Format("02 Jan 2006 15:04 MST"), alert.Details),
+	}
+	if err := s.Mailer.Send(msg); err != nil {
+		log.Error().Err(err).Int("alert_id", alert.ID).Msg("Gagal mengirim email peringatan")
+	}
+}
+
+// RunDetector menjalankan Detect setiap interval sampai ctx dibatalkan. Aman
+// dijalankan di beberapa instance karena peringatan dide-duplikasi berdasarkan
+// fingerprint.
+func (s *AlertService) RunDetector(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if created, err := s.Detect(ctx); err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("Detektor anomali pembayaran gagal")
+		} else if len(created) > 0 {
+			log.Ctx(ctx).Warn().Int("alerts", len(created)).Msg("Anomali aktivitas pembayaran terdeteksi")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
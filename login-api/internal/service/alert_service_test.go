@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"login-api/internal/anomaly"
+	"login-api/internal/model"
+	"login-api/internal/storage/memory"
+	"testing"
+	"time"
+)
+
+// fakeAlertStore mengembalikan aktivitas harian yang sudah ditentukan dan
+// mencatat rentang yang diminta serta peringatan yang disimpan.
+type fakeAlertStore struct {
+	days []model.DailyActivity
+
+	dailyFrom, dailyTo time.Time
+	dailyLoc           *time.Location
+	statsFrom, statsTo time.Time
+	alerts             []model.Alert
+}
+
+func (f *fakeAlertStore) GetDailyActivity(ctx context.Context, from, to time.Time, loc *time.Location) ([]model.DailyActivity, error) {
+	f.dailyFrom, f.dailyTo, f.dailyLoc = from, to, loc
+	return f.days, nil
+}
+
+func (f *fakeAlertStore) GetAmountStats(ctx context.Context, from, to time.Time) (model.AmountStats, error) {
+	f.statsFrom, f.statsTo = from, to
+	return model.AmountStats{}, nil
+}
+
+func (f *fakeAlertStore) CreateAlert(ctx context.Context, orgID string, finding anomaly.Finding) (model.Alert, bool, error) {
+	for _, a := range f.alerts {
+		if a.Fingerprint == finding.Fingerprint {
+			return model.Alert{}, false, nil
+		}
+	}
+	alert := model.Alert{ID: len(f.alerts) + 1, Kind: finding.Kind, Severity: finding.Severity, Fingerprint: finding.Fingerprint, Message: finding.Message}
+	f.alerts = append(f.alerts, alert)
+	return alert, true, nil
+}
+
+func (f *fakeAlertStore) ListAlerts(ctx context.Context, orgID string, includeAcknowledged bool, limit int) ([]model.Alert, error) {
+	return f.alerts, nil
+}
+
+func (f *fakeAlertStore) AcknowledgeAlert(ctx context.Context, orgID string, id int) (model.Alert, error) {
+	return f.alerts[id-1], nil
+}
+
+func TestAlertServiceDetectLocalDay(t *testing.T) {
+	// 00.30 WIB tanggal 12 Maret masih 11 Maret menurut UTC.
+	now := time.Date(2026, time.March, 12, 0, 30, 0, 0, jakarta)
+	today := time.Date(2026, time.March, 12, 0, 0, 0, 0, jakarta)
+
+	store := &fakeAlertStore{}
+	for d := today.AddDate(0, 0, -alertBaselineDays-1); d.Before(today); d = d.AddDate(0, 0, 1) {
+		store.days = append(store.days, model.DailyActivity{Date: d, Revenue: 1000000})
+	}
+	store.days[len(store.days)-1].Revenue = 0
+
+	s := NewAlertService(store, memory.NewMemoryPaymentStore(nil), nil, nil, "default", jakarta)
+	s.Now = func() time.Time { return now }
+
+	created, err := s.Detect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := today.AddDate(0, 0, -alertBaselineDays-1); !store.dailyFrom.Equal(want) || !store.dailyTo.Equal(today) || store.dailyLoc != jakarta {
+		t.Errorf("GetDailyActivity(%v, %v, %v), want (%v, %v, %v)", store.dailyFrom, store.dailyTo, store.dailyLoc, want, today, jakarta)
+	}
+	if want := now.Add(-alertRecentWindow); !store.statsTo.Equal(want) {
+		t.Errorf("GetAmountStats sampai %v, want %v", store.statsTo, want)
+	}
+	if len(created) != 1 || created[0].Fingerprint != model.AlertRevenueDrop+":2026-03-11" {
+		t.Fatalf("Detect() = %+v, want satu peringatan %s untuk 2026-03-11", created, model.AlertRevenueDrop)
+	}
+
+	created, err = s.Detect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(created) != 0 {
+		t.Errorf("Detect() kedua = %+v, want tidak ada peringatan baru", created)
+	}
+}
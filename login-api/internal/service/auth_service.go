@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"login-api/internal/auth"
 	"login-api/internal/model"
@@ -8,6 +9,7 @@ import (
 	"login-api/internal/validator"
 	"net/mail"
 	"strings"
+	"time"
 
 	"golang.org/x/crypto/bcrypt"
 )
@@ -15,6 +17,13 @@ import (
 // ErrEmailExists adalah error kustom saat email sudah terdaftar.
 var ErrEmailExists = errors.New("email ini sudah terdaftar")
 
+// ErrAccountDisabled dikembalikan saat akun pengguna dinonaktifkan administrator.
+var ErrAccountDisabled = errors.New("akun ini telah dinonaktifkan")
+
+// ErrSessionRevoked dikembalikan saat refresh token tidak lagi berlaku, misalnya
+// karena sesi pengguna dicabut atau kata sandinya direset.
+var ErrSessionRevoked = errors.New("sesi tidak lagi berlaku, silakan login kembali")
+
 // AuthService menyediakan logika bisnis terkait autentikasi.
 type AuthService struct {
 	UserStore storage.UserStore
@@ -30,7 +39,7 @@ func NewAuthService(store storage.UserStore, jwtKey []byte) *AuthService {
 }
 
 // RegisterUser memvalidasi dan mendaftarkan pengguna baru.
-func (s *AuthService) RegisterUser(creds model.Credentials) error {
+func (s *AuthService) RegisterUser(ctx context.Context, creds model.Credentials) error {
 	creds.Email = strings.TrimSpace(creds.Email)
 	if _, err := mail.ParseAddress(creds.Email); err != nil {
 		return errors.New("format email tidak valid")
@@ -40,8 +49,10 @@ func (s *AuthService) RegisterUser(creds model.Credentials) error {
 	}
 
 	// Cek apakah pengguna sudah ada
-	if _, exists := s.UserStore.GetUser(creds.Email); exists {
+	if _, err := s.UserStore.GetUser(ctx, creds.Email); err == nil {
 		return ErrEmailExists
+	} else if !errors.Is(err, storage.ErrNotFound) {
+		return err
 	}
 
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(creds.Password), bcrypt.DefaultCost)
@@ -54,15 +65,52 @@ func (s *AuthService) RegisterUser(creds model.Credentials) error {
 		PasswordHash: string(hashedPassword),
 	}
 
-	return s.UserStore.CreateUser(newUser)
+	return s.UserStore.CreateUser(ctx, newUser)
 }
 
 // LoginUser memverifikasi kredensial dan menghasilkan token.
-func (s *AuthService) LoginUser(creds model.Credentials) (string, string, error) {
-	user, ok := s.UserStore.GetUser(creds.Email)
-	if !ok || bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(creds.Password)) != nil {
+func (s *AuthService) LoginUser(ctx context.Context, creds model.Credentials) (string, string, error) {
+	user, err := s.UserStore.GetUser(ctx, creds.Email)
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return "", "", err
+	}
+	if err != nil || bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(creds.Password)) != nil {
 		return "", "", validator.ErrInvalidCredentials
 	}
+	if user.DisabledAt != nil {
+		return "", "", ErrAccountDisabled
+	}
+
+	return auth.GenerateTokens(user.Email, user.Role, s.JwtKey)
+}
+
+// ValidateRefresh memastikan refresh token dengan claims masih boleh dipakai:
+// pengguna masih ada dan aktif, dan token diterbitkan setelah sesi terakhir
+// dicabut. Peran pada claims diperbarui dari data pengguna terkini.
+func (s *AuthService) ValidateRefresh(ctx context.Context, claims *model.Claims) error {
+	user, err := s.UserStore.GetUser(ctx, claims.Email)
+	if errors.Is(err, storage.ErrNotFound) {
+		return ErrSessionRevoked
+	}
+	if err != nil {
+		return err
+	}
+	if user.DisabledAt != nil {
+		return ErrAccountDisabled
+	}
+	if user.SessionsRevokedAt != nil && (claims.IssuedAt == nil || issuedBeforeRevocation(claims.IssuedAt.Time, *user.SessionsRevokedAt)) {
+		return ErrSessionRevoked
+	}
+
+	claims.Role = user.Role
+	return nil
+}
 
-	return auth.GenerateTokens(creds.Email, s.JwtKey)
+// issuedBeforeRevocation membandingkan iat dengan waktu pencabutan sesi. iat JWT
+// hanya berpresisi detik sedangkan sessions_revoked_at berpresisi mikrodetik,
+// jadi revokedAt dipotong ke detik agar token yang terbit pada detik yang sama
+// setelah pencabutan, misalnya login ulang setelah reset kata sandi, tidak ikut
+// ditolak.
+func issuedBeforeRevocation(issuedAt, revokedAt time.Time) bool {
+	return issuedAt.Before(revokedAt.Truncate(time.Second))
 }
\ No newline at end of file
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"login-api/internal/model"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestAuthServiceValidateRefresh(t *testing.T) {
+	// Sesi dicabut pada 09.00.00,5; iat token hanya berpresisi detik.
+	revokedAt := time.Date(2026, time.October, 19, 9, 0, 0, 500000000, time.UTC)
+	disabledAt := revokedAt
+
+	tests := []struct {
+		name     string
+		user     model.User
+		issuedAt *time.Time
+		want     error
+	}{
+		{"belum pernah dicabut", model.User{Role: model.RoleUser}, &revokedAt, nil},
+		{"terbit setelah pencabutan", model.User{Role: model.RoleUser, SessionsRevokedAt: &revokedAt}, timePtr(revokedAt.Add(time.Second)), nil},
+		// Login ulang pada detik yang sama setelah reset kata sandi tetap diterima.
+		{"terbit pada detik pencabutan", model.User{Role: model.RoleUser, SessionsRevokedAt: &revokedAt}, timePtr(revokedAt.Truncate(time.Second)), nil},
+		{"terbit sebelum pencabutan", model.User{Role: model.RoleUser, SessionsRevokedAt: &revokedAt}, timePtr(revokedAt.Add(-time.Second)), ErrSessionRevoked},
+		{"tanpa iat", model.User{Role: model.RoleUser, SessionsRevokedAt: &revokedAt}, nil, ErrSessionRevoked},
+		{"akun nonaktif", model.User{Role: model.RoleUser, DisabledAt: &disabledAt}, timePtr(revokedAt.Add(time.Hour)), ErrAccountDisabled},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.user.Email = "andi@contoh.id"
+			svc := NewAuthService(newFakeUserStore(tt.user), []byte("rahasia"))
+
+			claims := &model.Claims{Email: tt.user.Email, Role: model.RoleAdmin}
+			if tt.issuedAt != nil {
+				claims.IssuedAt = jwt.NewNumericDate(*tt.issuedAt)
+			}
+			if err := svc.ValidateRefresh(context.Background(), claims); !errors.Is(err, tt.want) {
+				t.Fatalf("ValidateRefresh() error = %v, want %v", err, tt.want)
+			}
+			// Peran selalu diambil dari data pengguna terkini.
+			if tt.want == nil && claims.Role != model.RoleUser {
+				t.Errorf("claims.Role = %q, want %q", claims.Role, model.RoleUser)
+			}
+		})
+	}
+
+	svc := NewAuthService(newFakeUserStore(), []byte("rahasia"))
+	if err := svc.ValidateRefresh(context.Background(), &model.Claims{Email: "hilang@contoh.id"}); !errors.Is(err, ErrSessionRevoked) {
+		t.Errorf("ValidateRefresh() pengguna terhapus error = %v, want %v", err, ErrSessionRevoked)
+	}
+}
+
+func timePtr(t time.Time) *time.Time { return &t }
@@ -0,0 +1,421 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"login-api/internal/cache"
+	"login-api/internal/events"
+	"login-api/internal/forecast"
+	"login-api/internal/model"
+	"login-api/internal/storage"
+	"math"
+	"strings"
+	"time"
+)
+
+// maxChartSpan membatasi rentang data grafik per granularitas agar jumlah titik
+// tetap wajar untuk ditampilkan.
+var maxChartSpan = map[string]time.Duration{
+	model.ChartIntervalHour:  31 * 24 * time.Hour,
+	model.ChartIntervalDay:   366 * 24 * time.Hour,
+	model.ChartIntervalWeek:  5 * 366 * 24 * time.Hour,
+	model.ChartIntervalMonth: 10 * 366 * 24 * time.Hour,
+}
+
+// DashboardService menyediakan data ringkasan dan grafik dashboard. Hasil
+// agregasi disimpan di Cache dan dikosongkan saat menerima event pembayaran dari
+// outbox atau notifikasi perubahan dari LiveService; TTL cache membatasi usia
+// data bila keduanya terlambat.
+type DashboardService struct {
+	Store storage.PaymentStore
+	Cache *cache.Cache
+	Now   func() time.Time
+}
+
+// NewDashboardService membuat instance DashboardService baru. cacheTTL nol
+// menonaktifkan cache.
+func NewDashboardService(store storage.PaymentStore, cacheTTL time.Duration) *DashboardService {
+	return &DashboardService{Store: store, Cache: cache.New(cacheTTL, 1000), Now: time.Now}
+}
+
+func (s *DashboardService) Name() string { return "dashboard-cache" }
+
+// Publish mengosongkan cache dashboard bila event menyangkut pembayaran atau
+// pelanggan, karena nama pelanggan ikut tampil pada rincian pelanggan teratas.
+func (s *DashboardService) Publish(_ context.Context, ev events.Event) error {
+	if strings.HasPrefix(ev.Type, "payment.") || strings.HasPrefix(ev.Type, "customer.") {
+		s.Cache.Invalidate()
+	}
+	return nil
+}
+
+// Summary mengambil ringkasan dashboard untuk seluruh data.
+func (s *DashboardService) Summary(ctx context.Context) (model.DashboardSummary, error) {
+	v, err := s.Cache.GetOrLoad("summary", func() (interface{}, error) {
+		return s.Store.GetDashboardSummary(ctx)
+	})
+	if err != nil {
+		return model.DashboardSummary{}, err
+	}
+	return v.(model.DashboardSummary), nil
+}
+
+// ChartData mengambil pendapatan lunas per interval pada zona waktu loc. Parameter
+// kosong berarti bawaan: 7 hari terakhir per hari. from dan to berformat
+// YYYY-MM-DD atau RFC 3339; tanggal tanpa jam pada to mencakup hari itu sepenuhnya.
+func (s *DashboardService) ChartData(ctx context.Context, from, to, interval string, loc *time.Location) ([]model.ChartData, error) {
+	r, err := s.chartRange(from, to, interval, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	key := fmt.Sprintf("chart|%s|%s|%s|%s", r.From.Format(time.RFC3339Nano), r.To.Format(time.RFC3339Nano), r.Interval, loc)
+	v, err := s.Cache.GetOrLoad(key, func() (interface{}, error) {
+		return s.Store.GetChartData(ctx, r)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]model.ChartData), nil
+}
+
+func (s *DashboardService) chartRange(from, to, interval string, loc *time.Location) (model.ChartRange, error) {
+	if interval == "" {
+		interval = model.ChartIntervalDay
+	}
+	maxSpan, ok := maxChartSpan[interval]
+	if !ok {
+		return model.ChartRange{}, invalid("interval harus hour, day, week, atau month")
+	}
+
+	end := endOfDay(localMidnight(s.Now(), loc))
+	if to != "" {
+		t, dateOnly, err := parseChartTime(to, loc)
+		if err != nil {
+			return model.ChartRange{}, invalid("parameter to tidak valid: gunakan YYYY-MM-DD atau RFC 3339")
+		}
+		end = t
+		if dateOnly {
+			end = endOfDay(t)
+		}
+	}
+
+	start := localMidnight(end, loc).AddDate(0, 0, -6)
+	if from != "" {
+		t, _, err := parseChartTime(from, loc)
+		if err != nil {
+			return model.ChartRange{}, invalid("parameter from tidak valid: gunakan YYYY-MM-DD atau RFC 3339")
+		}
+		start = t
+	}
+
+	switch {
+	case start.After(end):
+		return model.ChartRange{}, invalid("parameter from harus sebelum to")
+	case end.Sub(start) > maxSpan:
+		return model.ChartRange{}, invalid(fmt.Sprintf("rentang untuk interval %s maksimal %d hari", interval, int(maxSpan.Hours()/24)))
+	}
+
+	return model.ChartRange{From: start, To: end, Interval: interval, Location: loc}, nil
+}
+
+// parseChartTime membaca tanggal (YYYY-MM-DD, tengah malam di loc) atau waktu
+// RFC 3339 yang dikonversi ke loc. dateOnly bernilai true untuk format tanggal.
+func parseChartTime(value string, loc *time.Location) (t time.Time, dateOnly bool, err error) {
+	if t, err := time.ParseInLocation("2006-01-02", value, loc); err == nil {
+		return t, true, nil
+	}
+	t, err = time.Parse(time.RFC3339, value)
+	return t.In(loc), false, err
+}
+
+// localMidnight mengembalikan awal hari kalender t menurut zona waktu loc.
+func localMidnight(t time.Time, loc *time.Location) time.Time {
+	t = t.In(loc)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+}
+
+// endOfDay mengembalikan saat terakhir dari hari yang dimulai pada midnight.
+// AddDate dipakai alih-alih 24 jam agar tetap benar pada hari pergantian DST.
+func endOfDay(midnight time.Time) time.Time {
+	return midnight.AddDate(0, 0, 1).Add(-time.Nanosecond)
+}
+
+// SummaryComparison menghitung ringkasan periode period (today, week, month, atau
+// custom dengan from dan to) pada zona waktu loc beserta periode sebelumnya yang
+// setara. Periode berjalan dibandingkan sampai titik yang sama: bulan ini hingga
+// hari ini dibandingkan dengan bulan lalu sepanjang durasi yang sama.
+func (s *DashboardService) SummaryComparison(ctx context.Context, period, from, to string, loc *time.Location) (model.SummaryComparison, error) {
+	key := fmt.Sprintf("comparison|%s|%s|%s|%s", period, from, to, loc)
+	v, err := s.Cache.GetOrLoad(key, func() (interface{}, error) {
+		return s.summaryComparison(ctx, period, from, to, loc)
+	})
+	if err != nil {
+		return model.SummaryComparison{}, err
+	}
+	return v.(model.SummaryComparison), nil
+}
+
+func (s *DashboardService) summaryComparison(ctx context.Context, period, from, to string, loc *time.Location) (model.SummaryComparison, error) {
+	cur, prev, err := s.summaryPeriods(period, from, to, loc)
+	if err != nil {
+		return model.SummaryComparison{}, err
+	}
+
+	current, err := s.Store.GetPeriodSummary(ctx, cur.From, cur.To)
+	if err != nil {
+		return model.SummaryComparison{}, err
+	}
+	previous, err := s.Store.GetPeriodSummary(ctx, prev.From, prev.To)
+	if err != nil {
+		return model.SummaryComparison{}, err
+	}
+	cur.DashboardSummary = current
+	prev.DashboardSummary = previous
+
+	return model.SummaryComparison{
+		Period:   period,
+		Current:  cur,
+		Previous: prev,
+		Changes: model.SummaryChanges{
+			TotalRevenue:      change(current.TotalRevenue, previous.TotalRevenue),
+			CompletedPayments: change(float64(current.CompletedPayments), float64(previous.CompletedPayments)),
+			PendingPayments:   change(float64(current.PendingPayments), float64(previous.PendingPayments)),
+		},
+	}, nil
+}
+
+// summaryPeriods menentukan rentang periode berjalan dan periode sebelumnya.
+// Semua rentang bersifat setengah terbuka [From, To).
+func (s *DashboardService) summaryPeriods(period, from, to string, loc *time.Location) (cur, prev model.PeriodSummary, err error) {
+	now := s.Now().In(loc)
+	today := localMidnight(now, loc)
+
+	var prevStart time.Time
+	switch period {
+	case model.SummaryPeriodToday:
+		cur.From = today
+		prevStart = today.AddDate(0, 0, -1)
+	case model.SummaryPeriodWeek:
+		// Minggu dimulai hari Senin, sama dengan date_trunc('week') pada grafik.
+		cur.From = today.AddDate(0, 0, -(int(today.Weekday())+6)%7)
+		prevStart = cur.From.AddDate(0, 0, -7)
+	case model.SummaryPeriodMonth:
+		cur.From = time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, loc)
+		prevStart = cur.From.AddDate(0, -1, 0)
+	case model.SummaryPeriodCustom:
+		return customPeriods(from, to, loc)
+	default:
+		return cur, prev, invalid("period harus today, week, month, atau custom")
+	}
+
+	cur.To = now
+	prev.From = prevStart
+	prev.To = prevStart.Add(now.Sub(cur.From))
+	if prev.To.After(cur.From) {
+		prev.To = cur.From
+	}
+	return cur, prev, nil
+}
+
+// customPeriods membaca rentang from dan to (YYYY-MM-DD atau RFC 3339; tanggal
+// pada to inklusif) dan membandingkannya dengan rentang sepanjang yang sama tepat
+// sebelumnya.
+func customPeriods(from, to string, loc *time.Location) (cur, prev model.PeriodSummary, err error) {
+	if from == "" || to == "" {
+		return cur, prev, invalid("period custom membutuhkan parameter from dan to")
+	}
+
+	cur.From, _, err = parseChartTime(from, loc)
+	if err != nil {
+		return cur, prev, invalid("parameter from tidak valid: gunakan YYYY-MM-DD atau RFC 3339")
+	}
+	end, dateOnly, err := parseChartTime(to, loc)
+	if err != nil {
+		return cur, prev, invalid("parameter to tidak valid: gunakan YYYY-MM-DD atau RFC 3339")
+	}
+	if dateOnly {
+		end = end.AddDate(0, 0, 1)
+	}
+	cur.To = end
+
+	switch {
+	case !cur.From.Before(cur.To):
+		return cur, prev, invalid("parameter from harus sebelum to")
+	case cur.To.Sub(cur.From) > maxChartSpan[model.ChartIntervalMonth]:
+		return cur, prev, invalid("rentang period custom terlalu panjang")
+	}
+
+	prev.To = cur.From
+	prev.From = cur.From.Add(-cur.To.Sub(cur.From))
+	return cur, prev, nil
+}
+
+// change menghitung selisih absolut dan persentase current terhadap previous.
+func change(current, previous float64) model.MetricChange {
+	c := model.MetricChange{Absolute: current - previous}
+	if previous != 0 {
+		pct := math.Round((current-previous)/math.Abs(previous)*10000) / 100
+		c.Percent = &pct
+	}
+	return c
+}
+
+// maxTopCustomers membatasi jumlah pelanggan pada rincian pelanggan teratas.
+const maxTopCustomers = 100
+
+// StatusBreakdown menghitung jumlah dan nilai pembayaran per status dalam rentang
+// opsional from dan to.
+func (s *DashboardService) StatusBreakdown(ctx context.Context, from, to string, loc *time.Location) ([]model.StatusBreakdown, error) {
+	start, end, err := breakdownRange(from, to, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	key := fmt.Sprintf("status|%s|%s|%s", from, to, loc)
+	v, err := s.Cache.GetOrLoad(key, func() (interface{}, error) {
+		return s.Store.GetStatusBreakdown(ctx, start, end)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]model.StatusBreakdown), nil
+}
+
+// TopCustomers mengambil limit pelanggan dengan pendapatan lunas terbesar dalam
+// rentang opsional from dan to.
+func (s *DashboardService) TopCustomers(ctx context.Context, from, to string, limit int, loc *time.Location) ([]model.CustomerBreakdown, error) {
+	if limit < 1 || limit > maxTopCustomers {
+		return nil, invalid(fmt.Sprintf("limit harus antara 1 dan %d", maxTopCustomers))
+	}
+	start, end, err := breakdownRange(from, to, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	key := fmt.Sprintf("customers|%s|%s|%d|%s", from, to, limit, loc)
+	v, err := s.Cache.GetOrLoad(key, func() (interface{}, error) {
+		return s.Store.GetTopCustomers(ctx, start, end, limit)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]model.CustomerBreakdown), nil
+}
+
+// breakdownRange membaca batas opsional from dan to dengan format yang sama seperti
+// period custom. Batas yang kosong dikembalikan sebagai nil.
+func breakdownRange(from, to string, loc *time.Location) (start, end *time.Time, err error) {
+	if from != "" {
+		t, _, err := parseChartTime(from, loc)
+		if err != nil {
+			return nil, nil, invalid("parameter from tidak valid: gunakan YYYY-MM-DD atau RFC 3339")
+		}
+		start = &t
+	}
+	if to != "" {
+		t, dateOnly, err := parseChartTime(to, loc)
+		if err != nil {
+			return nil, nil, invalid("parameter to tidak valid: gunakan YYYY-MM-DD atau RFC 3339")
+		}
+		if dateOnly {
+			t = t.AddDate(0, 0, 1)
+		}
+		end = &t
+	}
+	if start != nil && end != nil && !start.Before(*end) {
+		return nil, nil, invalid("parameter from harus sebelum to")
+	}
+	return start, end, nil
+}
+
+// forecastSettings menentukan panjang riwayat, musim, serta jumlah periode
+// proyeksi bawaan dan maksimum untuk setiap interval yang dapat diproyeksikan.
+var forecastSettings = map[string]struct {
+	historyDays    int
+	season         int
+	defaultPeriods int
+	maxPeriods     int
+}{
+	model.ChartIntervalDay:  {historyDays: 182, season: 7, defaultPeriods: 14, maxPeriods: 90},
+	model.ChartIntervalWeek: {historyDays: 7 * 104, season: 0, defaultPeriods: 8, maxPeriods: 52},
+}
+
+// Forecast memproyeksikan pendapatan lunas per hari atau per minggu untuk periods
+// periode mulai dari periode berjalan pada zona waktu loc. periods nol berarti
+// jumlah bawaan. Periode berjalan tidak ikut menjadi riwayat karena datanya belum
+// lengkap.
+func (s *DashboardService) Forecast(ctx context.Context, interval string, periods int, loc *time.Location) (model.RevenueForecast, error) {
+	if interval == "" {
+		interval = model.ChartIntervalDay
+	}
+	settings, ok := forecastSettings[interval]
+	if !ok {
+		return model.RevenueForecast{}, invalid("interval proyeksi harus day atau week")
+	}
+	if periods == 0 {
+		periods = settings.defaultPeriods
+	}
+	if periods < 1 || periods > settings.maxPeriods {
+		return model.RevenueForecast{}, invalid(fmt.Sprintf("jumlah periode untuk interval %s harus antara 1 dan %d", interval, settings.maxPeriods))
+	}
+
+	key := fmt.Sprintf("forecast|%s|%d|%s", interval, periods, loc)
+	v, err := s.Cache.GetOrLoad(key, func() (interface{}, error) {
+		return s.forecast(ctx, interval, periods, settings.historyDays, settings.season, loc)
+	})
+	if err != nil {
+		return model.RevenueForecast{}, err
+	}
+	return v.(model.RevenueForecast), nil
+}
+
+func (s *DashboardService) forecast(ctx context.Context, interval string, periods, historyDays, season int, loc *time.Location) (model.RevenueForecast, error) {
+	current := localMidnight(s.Now(), loc)
+	step := 1
+	if interval == model.ChartIntervalWeek {
+		current = current.AddDate(0, 0, -(int(current.Weekday())+6)%7)
+		step = 7
+	}
+
+	history, err := s.Store.GetChartData(ctx, model.ChartRange{
+		From:     current.AddDate(0, 0, -historyDays),
+		To:       current.Add(-time.Nanosecond),
+		Interval: interval,
+		Location: loc,
+	})
+	if err != nil {
+		return model.RevenueForecast{}, err
+	}
+	// Periode kosong sebelum pembayaran pertama bukan bagian dari pola pendapatan.
+	for len(history) > 0 && history[0].Value == 0 {
+		history = history[1:]
+	}
+
+	values := make([]float64, len(history))
+	for i, p := range history {
+		values[i] = p.Value
+	}
+	result := forecast.Project(values, periods, season)
+
+	points := make([]model.ForecastPoint, periods)
+	for i, p := range result.Points {
+		points[i] = model.ForecastPoint{
+			Label: current.AddDate(0, 0, i*step).Format("2006-01-02"),
+			Value: math.Round(p.Value),
+			Lower: math.Round(p.Lower),
+			Upper: math.Round(p.Upper),
+		}
+	}
+	if history == nil {
+		history = []model.ChartData{}
+	}
+
+	return model.RevenueForecast{
+		Interval:   interval,
+		Method:     result.Method,
+		Confidence: 0.95,
+		History:    history,
+		Forecast:   points,
+	}, nil
+}
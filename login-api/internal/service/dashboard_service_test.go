@@ -0,0 +1,489 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"login-api/internal/events"
+	"login-api/internal/model"
+	"login-api/internal/storage"
+	"login-api/internal/storage/memory"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var jakarta = mustLoadLocation("Asia/Jakarta")
+
+func mustLoadLocation(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		panic(err)
+	}
+	return loc
+}
+
+// wednesday adalah Rabu, 11 Maret 2026 pukul 10.00 WIB, "sekarang" pada pengujian dashboard.
+func wednesday() time.Time { return time.Date(2026, time.March, 11, 10, 0, 0, 0, jakarta) }
+
+func TestDashboardServiceChartRange(t *testing.T) {
+	svc := &DashboardService{Now: wednesday}
+	day := func(d int) time.Time { return time.Date(2026, time.March, d, 0, 0, 0, 0, jakarta) }
+	endOf := func(d int) time.Time { return day(d + 1).Add(-time.Nanosecond) }
+
+	tests := []struct {
+		name               string
+		from, to, interval string
+		want               model.ChartRange
+	}{
+		{name: "bawaan 7 hari terakhir", want: model.ChartRange{From: day(5), To: endOf(11), Interval: model.ChartIntervalDay}},
+		{name: "to berupa tanggal mencakup hari itu", to: "2026-03-08", want: model.ChartRange{From: day(2), To: endOf(8), Interval: model.ChartIntervalDay}},
+		{
+			name: "RFC 3339 apa adanya",
+			from: "2026-03-10T22:00:00+07:00", to: "2026-03-10T17:30:00Z", interval: model.ChartIntervalHour,
+			want: model.ChartRange{From: day(10).Add(22 * time.Hour), To: day(11).Add(30 * time.Minute), Interval: model.ChartIntervalHour},
+		},
+		{
+			name: "bulan",
+			from: "2026-02-01", to: "2026-03-31", interval: model.ChartIntervalMonth,
+			want: model.ChartRange{From: time.Date(2026, time.February, 1, 0, 0, 0, 0, jakarta), To: endOf(31), Interval: model.ChartIntervalMonth},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := svc.chartRange(tt.from, tt.to, tt.interval, jakarta)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !got.From.Equal(tt.want.From) || !got.To.Equal(tt.want.To) || got.Interval != tt.want.Interval {
+				t.Errorf("chartRange() = %+v, want %+v", got, tt.want)
+			}
+			if got.Location != jakarta || got.From.Location() != jakarta || got.To.Location() != jakarta {
+				t.Errorf("chartRange() = %+v, want semua batas pada %s", got, jakarta)
+			}
+		})
+	}
+}
+
+func TestDashboardServiceChartRangeTimezone(t *testing.T) {
+	// Pukul 10.00 WIB masih 10 Maret di New York, sehingga rentang bawaan berakhir
+	// pada hari itu menurut zona waktu pengguna, bukan zona waktu server.
+	newYork := mustLoadLocation("America/New_York")
+	svc := &DashboardService{Now: wednesday}
+
+	got, err := svc.chartRange("", "", "", newYork)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantFrom := time.Date(2026, time.March, 4, 0, 0, 0, 0, newYork)
+	wantTo := time.Date(2026, time.March, 11, 0, 0, 0, 0, newYork).Add(-time.Nanosecond)
+	if !got.From.Equal(wantFrom) || !got.To.Equal(wantTo) {
+		t.Errorf("chartRange() = [%v, %v], want [%v, %v]", got.From, got.To, wantFrom, wantTo)
+	}
+}
+
+func TestEndOfDayDST(t *testing.T) {
+	// 8 Maret 2026 di New York hanya 23 jam karena DST dimulai.
+	newYork := mustLoadLocation("America/New_York")
+	midnight := localMidnight(time.Date(2026, time.March, 8, 15, 0, 0, 0, newYork), newYork)
+
+	if want := time.Date(2026, time.March, 8, 0, 0, 0, 0, newYork); !midnight.Equal(want) {
+		t.Errorf("localMidnight() = %v, want %v", midnight, want)
+	}
+	if got := endOfDay(midnight).Sub(midnight); got != 23*time.Hour-time.Nanosecond {
+		t.Errorf("panjang hari = %v, want 23 jam", got)
+	}
+}
+
+func TestDashboardServiceChartRangeValidation(t *testing.T) {
+	svc := &DashboardService{Now: wednesday}
+
+	tests := []struct {
+		name               string
+		from, to, interval string
+	}{
+		{name: "interval tidak dikenal", interval: "year"},
+		{name: "from setelah to", from: "2026-03-12", to: "2026-03-11"},
+		{name: "format tanggal salah", from: "11-03-2026"},
+		{name: "rentang jam terlalu panjang", from: "2026-01-01", to: "2026-03-11", interval: model.ChartIntervalHour},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.chartRange(tt.from, tt.to, tt.interval, jakarta)
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Errorf("chartRange() error = %v, want ValidationError", err)
+			}
+		})
+	}
+}
+
+func TestDashboardServiceSummaryPeriods(t *testing.T) {
+	at := func(month time.Month, day, hour int) time.Time {
+		return time.Date(2026, month, day, hour, 0, 0, 0, jakarta)
+	}
+	endOfMarch := func() time.Time { return at(time.March, 31, 10) }
+
+	tests := []struct {
+		name              string
+		now               func() time.Time
+		period, from, to  string
+		wantCur, wantPrev [2]time.Time
+	}{
+		{
+			name: "hari ini", now: wednesday, period: model.SummaryPeriodToday,
+			wantCur:  [2]time.Time{at(time.March, 11, 0), at(time.March, 11, 10)},
+			wantPrev: [2]time.Time{at(time.March, 10, 0), at(time.March, 10, 10)},
+		},
+		{
+			// Minggu berjalan dimulai Senin 9 Maret dan dibandingkan dengan Senin 2 Maret
+			// sampai titik yang sama, Rabu 4 Maret pukul 10.00.
+			name: "minggu", now: wednesday, period: model.SummaryPeriodWeek,
+			wantCur:  [2]time.Time{at(time.March, 9, 0), at(time.March, 11, 10)},
+			wantPrev: [2]time.Time{at(time.March, 2, 0), at(time.March, 4, 10)},
+		},
+		{
+			name: "bulan", now: wednesday, period: model.SummaryPeriodMonth,
+			wantCur:  [2]time.Time{at(time.March, 1, 0), at(time.March, 11, 10)},
+			wantPrev: [2]time.Time{at(time.February, 1, 0), at(time.February, 11, 10)},
+		},
+		{
+			// Februari lebih pendek, sehingga periode sebelumnya dipotong di awal Maret.
+			name: "bulan lebih panjang dari bulan lalu", now: endOfMarch, period: model.SummaryPeriodMonth,
+			wantCur:  [2]time.Time{at(time.March, 1, 0), at(time.March, 31, 10)},
+			wantPrev: [2]time.Time{at(time.February, 1, 0), at(time.March, 1, 0)},
+		},
+		{
+			name: "custom dengan to inklusif", now: wednesday, period: model.SummaryPeriodCustom, from: "2026-03-01", to: "2026-03-10",
+			wantCur:  [2]time.Time{at(time.March, 1, 0), at(time.March, 11, 0)},
+			wantPrev: [2]time.Time{at(time.February, 19, 0), at(time.March, 1, 0)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &DashboardService{Now: tt.now}
+			cur, prev, err := svc.summaryPeriods(tt.period, tt.from, tt.to, jakarta)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !cur.From.Equal(tt.wantCur[0]) || !cur.To.Equal(tt.wantCur[1]) {
+				t.Errorf("current = [%v, %v), want [%v, %v)", cur.From, cur.To, tt.wantCur[0], tt.wantCur[1])
+			}
+			if !prev.From.Equal(tt.wantPrev[0]) || !prev.To.Equal(tt.wantPrev[1]) {
+				t.Errorf("previous = [%v, %v), want [%v, %v)", prev.From, prev.To, tt.wantPrev[0], tt.wantPrev[1])
+			}
+		})
+	}
+
+	svc := &DashboardService{Now: wednesday}
+	for _, tt := range []struct{ period, from, to string }{
+		{period: "year"},
+		{period: model.SummaryPeriodCustom, from: "2026-03-01"},
+		{period: model.SummaryPeriodCustom, from: "2026-03-10", to: "2026-03-01"},
+	} {
+		var validationErr *ValidationError
+		if _, _, err := svc.summaryPeriods(tt.period, tt.from, tt.to, jakarta); !errors.As(err, &validationErr) {
+			t.Errorf("summaryPeriods(%q, %q, %q) error = %v, want ValidationError", tt.period, tt.from, tt.to, err)
+		}
+	}
+}
+
+func TestChange(t *testing.T) {
+	tests := []struct {
+		current, previous float64
+		wantAbs           float64
+		wantPct           *float64
+	}{
+		{150, 100, 50, floatPtr(50)},
+		{50, 200, -150, floatPtr(-75)},
+		{1, 3, -2, floatPtr(-66.67)},
+		{10, -20, 30, floatPtr(150)},
+		{500, 0, 500, nil},
+	}
+	for _, tt := range tests {
+		got := change(tt.current, tt.previous)
+		if got.Absolute != tt.wantAbs || (got.Percent == nil) != (tt.wantPct == nil) || (got.Percent != nil && *got.Percent != *tt.wantPct) {
+			t.Errorf("change(%v, %v) = %v %v, want %v %v", tt.current, tt.previous, got.Absolute, got.Percent, tt.wantAbs, tt.wantPct)
+		}
+	}
+}
+
+func floatPtr(v float64) *float64 { return &v }
+
+func TestBreakdownRange(t *testing.T) {
+	day := func(d int) *time.Time {
+		t := time.Date(2026, time.March, d, 0, 0, 0, 0, jakarta)
+		return &t
+	}
+	at := func(t time.Time) *time.Time { return &t }
+
+	tests := []struct {
+		name      string
+		from, to  string
+		wantStart *time.Time
+		wantEnd   *time.Time
+	}{
+		{name: "tanpa batas"},
+		{name: "hanya from", from: "2026-03-05", wantStart: day(5)},
+		// to berupa tanggal mencakup hari itu sepenuhnya: batas atas eksklusif tengah malam berikutnya.
+		{name: "to berupa tanggal", from: "2026-03-05", to: "2026-03-10", wantStart: day(5), wantEnd: day(11)},
+		{name: "to RFC 3339", to: "2026-03-10T12:00:00Z", wantEnd: at(time.Date(2026, time.March, 10, 19, 0, 0, 0, jakarta))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end, err := breakdownRange(tt.from, tt.to, jakarta)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !sameTime(start, tt.wantStart) || !sameTime(end, tt.wantEnd) {
+				t.Errorf("breakdownRange() = [%v, %v), want [%v, %v)", start, end, tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+
+	for _, tt := range []struct{ from, to string }{
+		{from: "05-03-2026"},
+		{to: "kemarin"},
+		{from: "2026-03-10", to: "2026-03-09"},
+	} {
+		var validationErr *ValidationError
+		if _, _, err := breakdownRange(tt.from, tt.to, jakarta); !errors.As(err, &validationErr) {
+			t.Errorf("breakdownRange(%q, %q) error = %v, want ValidationError", tt.from, tt.to, err)
+		}
+	}
+}
+
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+func intPtr(v int) *int { return &v }
+
+// dashboardPayments berpusat pada Rabu, 11 Maret 2026 pukul 10.00 WIB.
+func dashboardPayments() []model.Payment {
+	at := func(day, hour, min, sec, nsec int) time.Time {
+		return time.Date(2026, time.March, day, hour, min, sec, nsec, jakarta)
+	}
+	return []model.Payment{
+		{CustomerID: intPtr(1), CustomerName: "Andi", Amount: 100, Status: model.PaymentStatusPaid, PaymentDate: at(5, 0, 0, 0, 0)},
+		{CustomerID: intPtr(2), CustomerName: "Budi", Amount: 200, Status: model.PaymentStatusPaid, PaymentDate: at(10, 23, 59, 59, 0)},
+		{CustomerID: intPtr(1), CustomerName: "Andi", Amount: 50, Status: model.PaymentStatusPending, PaymentDate: at(11, 9, 0, 0, 0)},
+		{CustomerID: intPtr(1), CustomerName: "Andi", Amount: 300, Status: model.PaymentStatusPaid, PaymentDate: at(11, 0, 0, 0, 0)},
+		{CustomerID: intPtr(3), CustomerName: "Citra", Amount: 400, Status: model.PaymentStatusPaid, PaymentDate: at(4, 23, 59, 59, 999999999)},
+		{CustomerID: intPtr(2), CustomerName: "Budi", Amount: 70, Status: model.PaymentStatusOverdue, PaymentDate: at(2, 8, 0, 0, 0)},
+	}
+}
+
+func newTestDashboardService(store storage.PaymentStore) *DashboardService {
+	svc := NewDashboardService(store, time.Minute)
+	svc.Now = wednesday
+	return svc
+}
+
+func TestDashboardServiceSummary(t *testing.T) {
+	svc := newTestDashboardService(memory.NewMemoryPaymentStore(dashboardPayments()))
+
+	got, err := svc.Summary(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := model.DashboardSummary{TotalRevenue: 1000, CompletedPayments: 4, PendingPayments: 1, OverduePayments: 1}
+	if got != want {
+		t.Errorf("Summary() = %+v, want %+v", got, want)
+	}
+}
+
+func TestDashboardServiceChartData(t *testing.T) {
+	svc := newTestDashboardService(memory.NewMemoryPaymentStore(dashboardPayments()))
+
+	tests := []struct {
+		name               string
+		from, to, interval string
+		want               []model.ChartData
+	}{
+		{
+			// Bawaan 7 hari terakhir; pembayaran 4 Maret 23.59.59,999 berada di luar rentang.
+			name: "bawaan",
+			want: []model.ChartData{
+				{Label: "2026-03-05", Value: 100},
+				{Label: "2026-03-06", Value: 0},
+				{Label: "2026-03-07", Value: 0},
+				{Label: "2026-03-08", Value: 0},
+				{Label: "2026-03-09", Value: 0},
+				{Label: "2026-03-10", Value: 200},
+				{Label: "2026-03-11", Value: 300},
+			},
+		},
+		{
+			// 1 Maret 2026 hari Minggu, sehingga minggu pertama dimulai Senin 23 Februari.
+			name: "minggu dimulai Senin",
+			from: "2026-03-01", to: "2026-03-11", interval: model.ChartIntervalWeek,
+			want: []model.ChartData{
+				{Label: "2026-02-23", Value: 0},
+				{Label: "2026-03-02", Value: 500},
+				{Label: "2026-03-09", Value: 500},
+			},
+		},
+		{
+			name: "bulan",
+			from: "2026-02-01", to: "2026-03-31", interval: model.ChartIntervalMonth,
+			want: []model.ChartData{
+				{Label: "2026-02", Value: 0},
+				{Label: "2026-03", Value: 1000},
+			},
+		},
+		{
+			name: "jam",
+			from: "2026-03-10T22:00:00+07:00", to: "2026-03-11T00:30:00+07:00", interval: model.ChartIntervalHour,
+			want: []model.ChartData{
+				{Label: "2026-03-10T22:00", Value: 0},
+				{Label: "2026-03-10T23:00", Value: 200},
+				{Label: "2026-03-11T00:00", Value: 300},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := svc.ChartData(context.Background(), tt.from, tt.to, tt.interval, jakarta)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ChartData() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDashboardServiceSummaryComparisonWeek(t *testing.T) {
+	svc := newTestDashboardService(memory.NewMemoryPaymentStore(dashboardPayments()))
+
+	got, err := svc.SummaryComparison(context.Background(), model.SummaryPeriodWeek, "", "", jakarta)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := (model.DashboardSummary{TotalRevenue: 500, CompletedPayments: 2, PendingPayments: 1}); got.Current.DashboardSummary != want {
+		t.Errorf("Current summary = %+v, want %+v", got.Current.DashboardSummary, want)
+	}
+	if want := (model.DashboardSummary{OverduePayments: 1}); got.Previous.DashboardSummary != want {
+		t.Errorf("Previous summary = %+v, want %+v", got.Previous.DashboardSummary, want)
+	}
+	if got.Changes.TotalRevenue.Absolute != 500 || got.Changes.TotalRevenue.Percent != nil {
+		t.Errorf("Changes.TotalRevenue = %+v, want selisih 500 tanpa persentase", got.Changes.TotalRevenue)
+	}
+}
+
+func TestDashboardServiceStatusBreakdownRange(t *testing.T) {
+	svc := newTestDashboardService(memory.NewMemoryPaymentStore(dashboardPayments()))
+
+	// to berupa tanggal mencakup 10 Maret sepenuhnya, tetapi tidak 11 Maret 00.00.
+	got, err := svc.StatusBreakdown(context.Background(), "2026-03-05", "2026-03-10", jakarta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []model.StatusBreakdown{{Status: model.PaymentStatusPaid, Count: 2, Amount: 300}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("StatusBreakdown() = %+v, want %+v", got, want)
+	}
+}
+
+func TestDashboardServiceTopCustomers(t *testing.T) {
+	svc := newTestDashboardService(memory.NewMemoryPaymentStore(dashboardPayments()))
+
+	got, err := svc.TopCustomers(context.Background(), "", "", 2, jakarta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Pendapatan sama diurutkan menurut nama.
+	want := []model.CustomerBreakdown{
+		{CustomerID: intPtr(1), CustomerName: "Andi", Revenue: 400, PaymentCount: 2},
+		{CustomerID: intPtr(3), CustomerName: "Citra", Revenue: 400, PaymentCount: 1},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("TopCustomers() = %+v, want %+v", got, want)
+	}
+
+	if _, err := svc.TopCustomers(context.Background(), "", "", 0, jakarta); err == nil {
+		t.Error("TopCustomers(limit=0) error = nil, want ValidationError")
+	}
+}
+
+// countingStore menghitung pemanggilan GetDashboardSummary ke store di bawahnya.
+type countingStore struct {
+	storage.PaymentStore
+	summaryCalls int
+}
+
+func (s *countingStore) GetDashboardSummary(ctx context.Context) (model.DashboardSummary, error) {
+	s.summaryCalls++
+	return s.PaymentStore.GetDashboardSummary(ctx)
+}
+
+func TestDashboardServicePublishInvalidatesCache(t *testing.T) {
+	store := &countingStore{PaymentStore: memory.NewMemoryPaymentStore(dashboardPayments())}
+	svc := newTestDashboardService(store)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if _, err := svc.Summary(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if store.summaryCalls != 1 {
+		t.Fatalf("store dipanggil %d kali, want 1 karena hasil kedua dari cache", store.summaryCalls)
+	}
+
+	if err := svc.Publish(ctx, events.Event{Type: "report.sent"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.Summary(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if store.summaryCalls != 1 {
+		t.Errorf("event selain pembayaran mengosongkan cache")
+	}
+
+	if err := svc.Publish(ctx, events.Event{Type: events.PaymentCreated}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.Summary(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if store.summaryCalls != 2 {
+		t.Errorf("store dipanggil %d kali setelah payment.created, want 2", store.summaryCalls)
+	}
+
+	if err := svc.Publish(ctx, events.Event{Type: events.CustomerUpdated}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.Summary(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if store.summaryCalls != 3 {
+		t.Errorf("store dipanggil %d kali setelah customer.updated, want 3", store.summaryCalls)
+	}
+}
+
+func TestDashboardServiceForecastLabels(t *testing.T) {
+	svc := newTestDashboardService(memory.NewMemoryPaymentStore(dashboardPayments()))
+
+	got, err := svc.Forecast(context.Background(), model.ChartIntervalWeek, 3, jakarta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var labels []string
+	for _, p := range got.Forecast {
+		labels = append(labels, p.Label)
+	}
+	// Proyeksi mingguan dimulai dari Senin minggu berjalan.
+	if want := []string{"2026-03-09", "2026-03-16", "2026-03-23"}; !reflect.DeepEqual(labels, want) {
+		t.Errorf("label proyeksi = %v, want %v", labels, want)
+	}
+	// Minggu berjalan tidak termasuk riwayat; riwayat dimulai dari minggu pertama yang berisi pendapatan.
+	if len(got.History) != 1 || got.History[0].Label != "2026-03-02" || got.History[0].Value != 500 {
+		t.Errorf("History = %+v, want satu minggu 2026-03-02 senilai 500", got.History)
+	}
+}
@@ -0,0 +1,15 @@
+package service
+
+// ValidationError menandai error yang disebabkan oleh input pengguna yang tidak valid,
+// sehingga handler dapat membalas dengan 400 alih-alih 500.
+type ValidationError struct {
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+func invalid(message string) error {
+	return &ValidationError{Message: message}
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"login-api/internal/gateway"
+	"login-api/internal/model"
+	"login-api/internal/storage/postgres"
+	"strings"
+	"time"
+)
+
+// ErrGatewayNotConfigured dikembalikan saat secret webhook gateway belum diatur.
+var ErrGatewayNotConfigured = errors.New("webhook gateway pembayaran belum dikonfigurasi")
+
+// GatewayEventStore menyimpan dan menerapkan event gateway. Diimplementasikan
+// oleh postgres.PostgresGatewayEventStore.
+type GatewayEventStore interface {
+	RecordEvent(ctx context.Context, ev model.GatewayEvent, t postgres.GatewayTransition) (postgres.GatewayEventResult, error)
+	ReplayEvent(ctx context.Context, id int, t postgres.GatewayTransition) (postgres.GatewayEventResult, error)
+	ListEvents(ctx context.Context, limit int) ([]model.GatewayEvent, error)
+	GetEvent(ctx context.Context, id int) (model.GatewayEvent, error)
+}
+
+// GatewayService memverifikasi dan memproses webhook dari penyedia pembayaran.
+type GatewayService struct {
+	Store     GatewayEventStore
+	Secret    []byte
+	Tolerance time.Duration
+	Now       func() time.Time
+}
+
+// NewGatewayService membuat instance GatewayService baru.
+func NewGatewayService(store GatewayEventStore, secret []byte, tolerance time.Duration) *GatewayService {
+	return &GatewayService{Store: store, Secret: secret, Tolerance: tolerance, Now: time.Now}
+}
+
+// HandleWebhook memverifikasi tanda tangan body, lalu menyimpan dan menerapkan event.
+// Duplicate pada hasil bernilai true jika event dengan ID yang sama sudah pernah diterima.
+func (s *GatewayService) HandleWebhook(ctx context.Context, body []byte, signature string) (postgres.GatewayEventResult, error) {
+	if len(s.Secret) == 0 {
+		return postgres.GatewayEventResult{}, ErrGatewayNotConfigured
+	}
+	if err := gateway.VerifySignature(s.Secret, body, signature, s.Tolerance, s.Now()); err != nil {
+		return postgres.GatewayEventResult{}, err
+	}
+
+	var event gateway.Event
+	if err := json.Unmarshal(body, &event); err != nil {
+		return postgres.GatewayEventResult{}, invalid("payload event tidak valid")
+	}
+	event.ID = strings.TrimSpace(event.ID)
+	if event.ID == "" || event.Type == "" || event.Data.PaymentID <= 0 {
+		return postgres.GatewayEventResult{}, invalid("payload event wajib memiliki id, type, dan data.payment_id")
+	}
+
+	return s.Store.RecordEvent(ctx, model.GatewayEvent{
+		ProviderEventID: event.ID,
+		EventType:       event.Type,
+		PaymentID:       event.Data.PaymentID,
+		Payload:         json.RawMessage(body),
+	}, transitionFor(event))
+}
+
+// ReplayEvent menerapkan ulang event tersimpan dari payload mentahnya.
+func (s *GatewayService) ReplayEvent(ctx context.Context, id int) (model.GatewayEvent, error) {
+	stored, err := s.Store.GetEvent(ctx, id)
+	if err != nil {
+		return model.GatewayEvent{}, err
+	}
+
+	var event gateway.Event
+	if err := json.Unmarshal(stored.Payload, &event); err != nil {
+		return model.GatewayEvent{}, invalid("payload event tersimpan tidak valid")
+	}
+
+	result, err := s.Store.ReplayEvent(ctx, id, transitionFor(event))
+	if err != nil {
+		return model.GatewayEvent{}, err
+	}
+	return result.Event, nil
+}
+
+func transitionFor(event gateway.Event) postgres.GatewayTransition {
+	target, _ := gateway.TargetStatus(event.Type)
+	return postgres.GatewayTransition{TargetStatus: target, Amount: event.Data.Amount}
+}
@@ -0,0 +1,302 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"login-api/internal/model"
+	"login-api/internal/storage/postgres"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Jenis event yang dikirim ke klien dashboard live.
+const (
+	LiveEventSnapshot = "dashboard.snapshot"
+	LiveEventDelta    = "dashboard.delta"
+	// LiveEventResync meminta klien memuat ulang data karena notifikasi mungkin
+	// terlewat, misalnya setelah koneksi listener tersambung kembali.
+	LiveEventResync = "dashboard.resync"
+	// LiveEventAuthExpired dikirim sebelum stream ditutup karena token akses
+	// kedaluwarsa. EventSource tidak dapat memperbarui token sendiri, jadi klien
+	// harus memanggil /refresh lalu membuka stream kembali.
+	LiveEventAuthExpired = "auth.expired"
+)
+
+// LiveEvent adalah satu event Server-Sent Events. ID berbentuk "<boot>-<seq>"
+// dengan seq berurutan per tenant, sehingga ID dari proses sebelumnya dikenali
+// dan tidak diputar ulang.
+type LiveEvent struct {
+	ID   string
+	Type string
+	Data []byte
+}
+
+// LiveService menyebarkan perubahan pembayaran dari LISTEN/NOTIFY ke pelanggan
+// dashboard live per tenant. Event terakhir disimpan dalam buffer agar klien yang
+// tersambung kembali dengan Last-Event-ID dapat menerima event yang terlewat.
+type LiveService struct {
+	Listener       *postgres.PostgresPaymentListener
+	Dashboard      *DashboardService
+	OrganizationID string
+	// Heartbeat adalah jeda antar komentar keep-alive pada stream.
+	Heartbeat time.Duration
+	// BufferSize adalah jumlah event per tenant yang dapat diputar ulang.
+	BufferSize int
+
+	mu      sync.Mutex
+	bootID  string
+	closed  bool
+	tenants map[string]*liveTenant
+}
+
+type liveTenant struct {
+	seq         uint64
+	buffer      []LiveEvent
+	subscribers map[chan LiveEvent]struct{}
+}
+
+// liveSubscriberBuffer adalah kapasitas antrean per klien. Klien yang tertinggal
+// lebih jauh diputus dan akan menyusul lewat Last-Event-ID.
+const liveSubscriberBuffer = 64
+
+// NewLiveService membuat instance LiveService baru.
+func NewLiveService(listener *postgres.PostgresPaymentListener, dashboard *DashboardService, orgID string, heartbeat time.Duration) *LiveService {
+	return &LiveService{
+		Listener:       listener,
+		Dashboard:      dashboard,
+		OrganizationID: orgID,
+		Heartbeat:      heartbeat,
+		BufferSize:     256,
+		bootID:         strconv.FormatInt(time.Now().UnixNano(), 36),
+		tenants:        make(map[string]*liveTenant),
+	}
+}
+
+// Run mendengarkan perubahan pembayaran sampai ctx dibatalkan, lalu menutup semua
+// stream. Koneksi listener yang terputus disambung ulang dengan backoff.
+func (s *LiveService) Run(ctx context.Context) {
+	defer s.closeAll()
+
+	backoff := time.Second
+	for {
+		connected := false
+		err := s.Listener.Listen(ctx, func() {
+			connected = true
+			backoff = time.Second
+			// Perubahan selama koneksi terputus tidak diterima, jadi cache dan
+			// klien harus memuat ulang.
+			s.Dashboard.Cache.Invalidate()
+			s.broadcastAll(LiveEventResync, struct{}{})
+		}, s.handleChange)
+		if ctx.Err() != nil {
+			return
+		}
+		log.Ctx(ctx).Error().Err(err).Bool("connected", connected).Msg("Listener perubahan pembayaran terputus")
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		if backoff < 30*time.Second {
+			backoff *= 2
+		}
+	}
+}
+
+func (s *LiveService) handleChange(c model.PaymentChange) {
+	s.Dashboard.Cache.Invalidate()
+	// Tabel payments belum memiliki kolom tenant, jadi semua perubahan milik
+	// organisasi instance ini.
+	s.broadcast(s.OrganizationID, LiveEventDelta, dashboardDelta(c))
+}
+
+// dashboardDelta menghitung perubahan ringkasan dan pendapatan grafik dari satu
+// perubahan pembayaran.
+func dashboardDelta(c model.PaymentChange) model.DashboardDelta {
+	d := model.DashboardDelta{PaymentID: c.ID, Op: c.Op, Chart: []model.RevenueChange{}}
+
+	apply := func(status string, amount float64, at *time.Time, sign float64) {
+		if at == nil {
+			return
+		}
+		switch status {
+		case model.PaymentStatusPaid:
+			d.Summary.TotalRevenue += sign * amount
+			d.Summary.CompletedPayments += int64(sign)
+			d.Chart = append(d.Chart, model.RevenueChange{At: *at, Value: sign * amount})
+		case model.PaymentStatusPending:
+			d.Summary.PendingPayments += int64(sign)
+		case model.PaymentStatusOverdue:
+			d.Summary.OverduePayments += int64(sign)
+		}
+	}
+	apply(c.OldStatus, c.OldAmount, c.OldPaymentDate, -1)
+	apply(c.Status, c.Amount, c.PaymentDate, 1)
+	return d
+}
+
+// Subscribe mendaftarkan klien pada tenant. Bila lastEventID dikenali dan masih
+// ada di buffer, replay berisi event setelahnya; bila tidak, resync bernilai true
+// dan klien perlu dikirimi Snapshot. cancel harus dipanggil saat klien terputus.
+// Channel ditutup bila klien tertinggal atau layanan berhenti.
+func (s *LiveService) Subscribe(tenant, lastEventID string) (ch <-chan LiveEvent, replay []LiveEvent, resync bool, cancel func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t := s.tenant(tenant)
+	c := make(chan LiveEvent, liveSubscriberBuffer)
+	if s.closed {
+		close(c)
+		return c, nil, false, func() {}
+	}
+	t.subscribers[c] = struct{}{}
+
+	replay, resync = s.replaySince(t, lastEventID)
+
+	cancel = func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if _, ok := t.subscribers[c]; ok {
+			delete(t.subscribers, c)
+			close(c)
+		}
+	}
+	return c, replay, resync, cancel
+}
+
+// Snapshot mengambil ringkasan dashboard untuk klien yang perlu resync beserta
+// ID event terakhir tenant. ID diambil sebelum ringkasan dibaca langsung dari
+// store, bukan dari cache yang bisa saja terisi sebelum perubahan terakhir:
+// setiap delta sampai ID tersebut sudah ter-commit sehingga tercakup dalam
+// ringkasan, dan event yang masih mengantre untuk klien dengan Covers bernilai
+// true harus dilewati. Delta setelah ID selalu dikirim, sehingga perubahan yang
+// ter-commit saat ringkasan sedang dibaca tidak pernah hilang.
+func (s *LiveService) Snapshot(ctx context.Context, tenant string) (model.DashboardSummary, string, error) {
+	s.mu.Lock()
+	id := s.eventID(s.tenant(tenant).seq)
+	s.mu.Unlock()
+
+	summary, err := s.Dashboard.Store.GetDashboardSummary(ctx)
+	if err != nil {
+		return model.DashboardSummary{}, "", err
+	}
+	return summary, id, nil
+}
+
+// Covers melaporkan apakah ev sudah tercakup dalam snapshot snapshotID, yaitu
+// berasal dari proses yang sama dan nomor urutnya tidak lebih besar.
+func (s *LiveService) Covers(snapshotID string, ev LiveEvent) bool {
+	snapshotSeq, ok := s.eventSeq(snapshotID)
+	if !ok {
+		return false
+	}
+	seq, ok := s.eventSeq(ev.ID)
+	return ok && seq <= snapshotSeq
+}
+
+// replaySince mencari event setelah lastEventID di buffer tenant.
+func (s *LiveService) replaySince(t *liveTenant, lastEventID string) ([]LiveEvent, bool) {
+	last, ok := s.eventSeq(lastEventID)
+	if !ok || last > t.seq {
+		return nil, true
+	}
+	if last == t.seq {
+		return nil, false
+	}
+
+	// Event pertama setelah last harus masih ada di buffer; bila tidak, ada event
+	// yang sudah terbuang dan klien harus memuat ulang.
+	for i, ev := range t.buffer {
+		if ev.ID == s.eventID(last+1) {
+			return append([]LiveEvent(nil), t.buffer[i:]...), false
+		}
+	}
+	return nil, true
+}
+
+func (s *LiveService) broadcast(tenant, eventType string, data interface{}) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		log.Error().Err(err).Str("event_type", eventType).Msg("Gagal melakukan encode event live")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.publish(s.tenant(tenant), eventType, payload)
+}
+
+func (s *LiveService) broadcastAll(eventType string, data interface{}) {
+	payload, _ := json.Marshal(data)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, t := range s.tenants {
+		s.publish(t, eventType, payload)
+	}
+}
+
+// publish memberi ID pada event, menyimpannya di buffer, dan mengirimkannya ke
+// semua pelanggan tenant. Pemanggil harus memegang s.mu.
+func (s *LiveService) publish(t *liveTenant, eventType string, payload []byte) {
+	t.seq++
+	ev := LiveEvent{ID: s.eventID(t.seq), Type: eventType, Data: payload}
+
+	t.buffer = append(t.buffer, ev)
+	if len(t.buffer) > s.BufferSize {
+		t.buffer = t.buffer[len(t.buffer)-s.BufferSize:]
+	}
+
+	for c := range t.subscribers {
+		select {
+		case c <- ev:
+		default:
+			delete(t.subscribers, c)
+			close(c)
+		}
+	}
+}
+
+// tenant mengembalikan state tenant, membuatnya bila belum ada. Pemanggil harus
+// memegang s.mu.
+func (s *LiveService) tenant(id string) *liveTenant {
+	t, ok := s.tenants[id]
+	if !ok {
+		t = &liveTenant{subscribers: make(map[chan LiveEvent]struct{})}
+		s.tenants[id] = t
+	}
+	return t
+}
+
+func (s *LiveService) eventID(seq uint64) string {
+	return fmt.Sprintf("%s-%d", s.bootID, seq)
+}
+
+// eventSeq mengembalikan nomor urut dari ID event. ok bernilai false bila ID
+// tidak valid atau berasal dari proses sebelumnya.
+func (s *LiveService) eventSeq(id string) (seq uint64, ok bool) {
+	boot, seqStr, ok := strings.Cut(id, "-")
+	if !ok || boot != s.bootID {
+		return 0, false
+	}
+	seq, err := strconv.ParseUint(seqStr, 10, 64)
+	return seq, err == nil
+}
+
+func (s *LiveService) closeAll() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	for _, t := range s.tenants {
+		for c := range t.subscribers {
+			delete(t.subscribers, c)
+			close(c)
+		}
+	}
+}
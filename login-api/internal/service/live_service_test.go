@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"login-api/internal/model"
+	"login-api/internal/storage/memory"
+	"testing"
+	"time"
+)
+
+// applyDelta menambahkan delta dashboard pada summary seperti yang dilakukan klien.
+func applyDelta(t *testing.T, summary model.DashboardSummary, ev LiveEvent) model.DashboardSummary {
+	t.Helper()
+	var d model.DashboardDelta
+	if err := json.Unmarshal(ev.Data, &d); err != nil {
+		t.Fatal(err)
+	}
+	summary.TotalRevenue += d.Summary.TotalRevenue
+	summary.CompletedPayments += d.Summary.CompletedPayments
+	summary.PendingPayments += d.Summary.PendingPayments
+	summary.OverduePayments += d.Summary.OverduePayments
+	return summary
+}
+
+func TestLiveServiceSnapshotSkipsCoveredDeltas(t *testing.T) {
+	ctx := context.Background()
+	due := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+	store := memory.NewMemoryPaymentStore([]model.Payment{
+		{CustomerName: "Andi", Amount: 100, Status: model.PaymentStatusPaid, PaymentDate: due},
+		{CustomerName: "Budi", Amount: 50, Status: model.PaymentStatusPending, PaymentDate: due, DueDate: &due},
+	})
+	svc := NewLiveService(nil, newTestDashboardService(store), "org", time.Minute)
+
+	events, replay, resync, cancel := svc.Subscribe("org", "")
+	defer cancel()
+	if !resync || len(replay) != 0 {
+		t.Fatalf("Subscribe() replay = %v, resync = %v, want snapshot", replay, resync)
+	}
+
+	// Perubahan terjadi setelah berlangganan tetapi sebelum snapshot dibaca,
+	// sehingga sudah tercakup dalam snapshot sekaligus mengantre sebagai delta.
+	overdue, err := store.MarkOverduePayments(ctx, due.AddDate(0, 0, 1))
+	if err != nil || len(overdue) != 1 {
+		t.Fatalf("MarkOverduePayments() = %v, %v", overdue, err)
+	}
+	svc.handleChange(model.PaymentChange{
+		Op: "UPDATE", ID: overdue[0].ID, Status: model.PaymentStatusOverdue, Amount: 50, PaymentDate: &due,
+		OldStatus: model.PaymentStatusPending, OldAmount: 50, OldPaymentDate: &due,
+	})
+
+	summary, snapshotID, err := svc.Snapshot(ctx, "org")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Perubahan setelah snapshot harus tetap diterapkan.
+	paidAt := due.AddDate(0, 0, 2)
+	svc.handleChange(model.PaymentChange{Op: "INSERT", ID: 3, Status: model.PaymentStatusPaid, Amount: 25, PaymentDate: &paidAt})
+
+	for i := 0; i < 2; i++ {
+		ev := <-events
+		if covered := svc.Covers(snapshotID, ev); covered != (i == 0) {
+			t.Errorf("Covers(%s, %s) = %v, want %v", snapshotID, ev.ID, covered, i == 0)
+		}
+		if !svc.Covers(snapshotID, ev) {
+			summary = applyDelta(t, summary, ev)
+		}
+	}
+
+	want := model.DashboardSummary{TotalRevenue: 125, CompletedPayments: 2, PendingPayments: 0, OverduePayments: 1}
+	if summary != want {
+		t.Errorf("ringkasan klien = %+v, want %+v", summary, want)
+	}
+
+	// Klien yang tersambung kembali dari snapshot hanya menerima event setelahnya.
+	_, replay, resync, cancelReplay := svc.Subscribe("org", snapshotID)
+	defer cancelReplay()
+	if resync || len(replay) != 1 || svc.Covers(snapshotID, replay[0]) {
+		t.Errorf("Subscribe(%s) replay = %v, resync = %v, want satu event setelah snapshot", snapshotID, replay, resync)
+	}
+}
+
+func TestLiveServiceCovers(t *testing.T) {
+	svc := NewLiveService(nil, nil, "org", time.Minute)
+	tests := []struct {
+		snapshotID, eventID string
+		want                bool
+	}{
+		{svc.eventID(5), svc.eventID(5), true},
+		{svc.eventID(5), svc.eventID(4), true},
+		{svc.eventID(5), svc.eventID(6), false},
+		{svc.eventID(5), "lama-1", false},
+		{"lama-5", svc.eventID(1), false},
+		{"", svc.eventID(1), false},
+	}
+	for _, tt := range tests {
+		if got := svc.Covers(tt.snapshotID, LiveEvent{ID: tt.eventID}); got != tt.want {
+			t.Errorf("Covers(%q, %q) = %v, want %v", tt.snapshotID, tt.eventID, got, tt.want)
+		}
+	}
+}
+
+// racingSummaryStore menjalankan afterRead setelah ringkasan dihitung, meniru
+// perubahan yang ter-commit saat snapshot sedang dibaca.
+type racingSummaryStore struct {
+	*memory.MemoryPaymentStore
+	afterRead func()
+}
+
+func (s *racingSummaryStore) GetDashboardSummary(ctx context.Context) (model.DashboardSummary, error) {
+	summary, err := s.MemoryPaymentStore.GetDashboardSummary(ctx)
+	if s.afterRead != nil {
+		s.afterRead()
+		s.afterRead = nil
+	}
+	return summary, err
+}
+
+func TestLiveServiceSnapshotKeepsConcurrentDelta(t *testing.T) {
+	ctx := context.Background()
+	due := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+	store := &racingSummaryStore{MemoryPaymentStore: memory.NewMemoryPaymentStore([]model.Payment{
+		{CustomerName: "Andi", Amount: 100, Status: model.PaymentStatusPaid, PaymentDate: due},
+		{CustomerName: "Budi", Amount: 50, Status: model.PaymentStatusPending, PaymentDate: due, DueDate: &due},
+	})}
+	svc := NewLiveService(nil, newTestDashboardService(store), "org", time.Minute)
+
+	events, _, _, cancel := svc.Subscribe("org", "")
+	defer cancel()
+
+	// Perubahan ter-commit dan dinotifikasi setelah ringkasan dibaca, sehingga
+	// tidak tercakup dalam snapshot dan harus dikirim sebagai delta.
+	store.afterRead = func() {
+		overdue, err := store.MarkOverduePayments(ctx, due.AddDate(0, 0, 1))
+		if err != nil || len(overdue) != 1 {
+			t.Fatalf("MarkOverduePayments() = %v, %v", overdue, err)
+		}
+		svc.handleChange(model.PaymentChange{
+			Op: "UPDATE", ID: overdue[0].ID, Status: model.PaymentStatusOverdue, Amount: 50, PaymentDate: &due,
+			OldStatus: model.PaymentStatusPending, OldAmount: 50, OldPaymentDate: &due,
+		})
+	}
+
+	summary, snapshotID, err := svc.Snapshot(ctx, "org")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ev := <-events
+	if svc.Covers(snapshotID, ev) {
+		t.Fatalf("Covers(%s, %s) = true, want delta yang belum tercakup tetap dikirim", snapshotID, ev.ID)
+	}
+	summary = applyDelta(t, summary, ev)
+
+	if want := (model.DashboardSummary{TotalRevenue: 100, CompletedPayments: 1, OverduePayments: 1}); summary != want {
+		t.Errorf("ringkasan klien = %+v, want %+v", summary, want)
+	}
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"login-api/internal/events"
+	"login-api/internal/storage/postgres"
+	"math"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// OutboxRelay menerbitkan event dari tabel outbox ke semua sink. Event baru
+// ditandai terbit setelah semua sink menerimanya; bila satu sink gagal, event
+// dicoba lagi ke semua sink (at-least-once), jadi penerima harus mende-duplikasi
+// berdasarkan ID event.
+type OutboxRelay struct {
+	Store *postgres.PostgresOutboxStore
+	Sinks []events.Sink
+	// BatchSize adalah jumlah event yang diklaim per putaran.
+	BatchSize int
+	// PublishTimeout membatasi waktu penerbitan satu event ke satu sink.
+	PublishTimeout time.Duration
+	BaseBackoff    time.Duration
+	MaxBackoff     time.Duration
+	// Retention adalah lama event yang sudah terbit disimpan sebelum dihapus.
+	Retention time.Duration
+}
+
+// NewOutboxRelay membuat instance OutboxRelay baru.
+func NewOutboxRelay(store *postgres.PostgresOutboxStore, sinks ...events.Sink) *OutboxRelay {
+	return &OutboxRelay{
+		Store:          store,
+		Sinks:          sinks,
+		BatchSize:      100,
+		PublishTimeout: 10 * time.Second,
+		BaseBackoff:    5 * time.Second,
+		MaxBackoff:     10 * time.Minute,
+		Retention:      7 * 24 * time.Hour,
+	}
+}
+
+// Run menerbitkan event yang tertunda setiap interval sampai ctx dibatalkan.
+func (r *OutboxRelay) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	lastCleanup := time.Time{}
+	for {
+		r.relayPending(ctx)
+
+		if time.Since(lastCleanup) >= time.Hour {
+			if n, err := r.Store.DeletePublishedBefore(ctx, time.Now().Add(-r.Retention)); err != nil {
+				log.Ctx(ctx).Error().Err(err).Msg("Gagal membersihkan outbox")
+			} else if n > 0 {
+				log.Ctx(ctx).Info().Int64("deleted", n).Msg("Event outbox lama dibersihkan")
+			}
+			lastCleanup = time.Now()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *OutboxRelay) relayPending(ctx context.Context) {
+	// Lease harus cukup untuk menerbitkan satu batch penuh ke semua sink.
+	lease := time.Duration(r.BatchSize*len(r.Sinks))*r.PublishTimeout + time.Minute
+
+	for ctx.Err() == nil {
+		entries, err := r.Store.ClaimPending(ctx, r.BatchSize, lease)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("Gagal mengambil event outbox")
+			return
+		}
+		if len(entries) == 0 {
+			return
+		}
+
+		for _, e := range entries {
+			r.relay(ctx, e)
+		}
+	}
+}
+
+func (r *OutboxRelay) relay(ctx context.Context, e postgres.OutboxEntry) {
+	var errs []error
+	for _, sink := range r.Sinks {
+		sinkCtx, cancel := context.WithTimeout(ctx, r.PublishTimeout)
+		err := sink.Publish(sinkCtx, e.Event)
+		cancel()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", sink.Name(), err))
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		next := time.Now().Add(r.backoff(e.Attempts + 1))
+		log.Ctx(ctx).Warn().Err(err).Str("event_id", e.Event.ID).Int("attempts", e.Attempts+1).Msg("Gagal menerbitkan event outbox")
+		if err := r.Store.MarkFailed(ctx, e.ID, err.Error(), next); err != nil {
+			log.Ctx(ctx).Error().Err(err).Str("event_id", e.Event.ID).Msg("Gagal mencatat kegagalan event outbox")
+		}
+		return
+	}
+
+	if err := r.Store.MarkPublished(ctx, e.ID); err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("event_id", e.Event.ID).Msg("Gagal menandai event outbox terbit")
+	}
+}
+
+// backoff menghitung jeda sebelum percobaan berikutnya setelah attempts kali gagal.
+// Event outbox tidak pernah dibuang; percobaan berlanjut setiap MaxBackoff.
+func (r *OutboxRelay) backoff(attempts int) time.Duration {
+	d := float64(r.BaseBackoff) * math.Pow(2, float64(attempts-1))
+	if d > float64(r.MaxBackoff) {
+		d = float64(r.MaxBackoff)
+	}
+	return time.Duration(d)
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"login-api/internal/storage"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// PreferenceService mengelola preferensi pengguna, saat ini zona waktu yang
+// dipakai untuk agregasi dashboard.
+type PreferenceService struct {
+	Users           storage.UserPreferenceStore
+	DefaultLocation *time.Location
+}
+
+// NewPreferenceService membuat instance PreferenceService baru.
+func NewPreferenceService(users storage.UserPreferenceStore, defaultLocation *time.Location) *PreferenceService {
+	return &PreferenceService{Users: users, DefaultLocation: defaultLocation}
+}
+
+// LoadTimezone memuat zona waktu IANA, contoh: Asia/Jakarta atau Asia/Makassar.
+func LoadTimezone(name string) (*time.Location, error) {
+	name = strings.TrimSpace(name)
+	// "Local" bergantung pada mesin server dan tidak dikenal oleh PostgreSQL.
+	if name == "" || name == "Local" {
+		return nil, invalid("zona waktu wajib berupa nama IANA, contoh: Asia/Jakarta")
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, invalid("zona waktu " + name + " tidak dikenali")
+	}
+	return loc, nil
+}
+
+// Location menentukan zona waktu untuk sebuah permintaan: requested bila diisi,
+// lalu preferensi pengguna email, lalu zona waktu bawaan.
+func (s *PreferenceService) Location(ctx context.Context, requested, email string) (*time.Location, error) {
+	if requested != "" {
+		return LoadTimezone(requested)
+	}
+
+	if email != "" {
+		tz, err := s.Users.GetUserTimezone(ctx, email)
+		switch {
+		case err == nil && tz != "":
+			if loc, err := LoadTimezone(tz); err == nil {
+				return loc, nil
+			}
+			log.Ctx(ctx).Warn().Str("email", email).Str("timezone", tz).Msg("Zona waktu tersimpan tidak dikenali, memakai bawaan")
+		case err != nil && !errors.Is(err, storage.ErrNotFound):
+			return nil, err
+		}
+	}
+
+	return s.DefaultLocation, nil
+}
+
+// Timezone mengembalikan nama zona waktu efektif milik pengguna.
+func (s *PreferenceService) Timezone(ctx context.Context, email string) (string, error) {
+	loc, err := s.Location(ctx, "", email)
+	if err != nil {
+		return "", err
+	}
+	return loc.String(), nil
+}
+
+// SetTimezone memvalidasi lalu menyimpan zona waktu pilihan pengguna.
+func (s *PreferenceService) SetTimezone(ctx context.Context, email, name string) (string, error) {
+	loc, err := LoadTimezone(name)
+	if err != nil {
+		return "", err
+	}
+	if err := s.Users.SetUserTimezone(ctx, email, loc.String()); err != nil {
+		return "", err
+	}
+	return loc.String(), nil
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"login-api/internal/document"
+	"login-api/internal/mailer"
+	"login-api/internal/model"
+	"login-api/internal/storage"
+	"login-api/internal/storage/postgres"
+	"sort"
+	"text/template"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// ReceivableService menangani pembayaran yang belum lunas: penandaan keterlambatan,
+// laporan umur piutang, dan pengiriman email pengingat ke pelanggan.
+type ReceivableService struct {
+	PaymentStore  storage.PaymentStore
+	ReminderStore *postgres.PostgresReminderStore
+	Mailer        mailer.Mailer
+	Company       model.Company
+	// ReminderOffsets adalah jadwal pengingat dalam hari relatif terhadap jatuh tempo,
+	// terurut naik. Contoh: [-3, 0, 7] = 3 hari sebelum, pada hari H, dan 7 hari sesudah.
+	ReminderOffsets []int
+	Now             func() time.Time
+}
+
+// NewReceivableService membuat instance ReceivableService baru.
+func NewReceivableService(paymentStore storage.PaymentStore, reminderStore *postgres.PostgresReminderStore, m mailer.Mailer, company model.Company, offsets []int) *ReceivableService {
+	sorted := append([]int(nil), offsets...)
+	sort.Ints(sorted)
+
+	return &ReceivableService{
+		PaymentStore:    paymentStore,
+		ReminderStore:   reminderStore,
+		Mailer:          m,
+		Company:         company,
+		ReminderOffsets: sorted,
+		Now:             time.Now,
+	}
+}
+
+// AgingReport menghasilkan laporan umur piutang per hari ini.
+func (s *ReceivableService) AgingReport(ctx context.Context) (model.AgingReport, error) {
+	return s.PaymentStore.GetAgingReport(ctx, dateOf(s.Now()))
+}
+
+// MarkOverdue menandai pembayaran tertunda yang sudah lewat jatuh tempo sebagai Terlambat.
+func (s *ReceivableService) MarkOverdue(ctx context.Context) (int, error) {
+	marked, err := s.PaymentStore.MarkOverduePayments(ctx, dateOf(s.Now()))
+	if err != nil {
+		return 0, err
+	}
+	return len(marked), nil
+}
+
+// SendReminders mengirim paling banyak satu pengingat per pembayaran, yaitu untuk
+// jadwal terakhir yang sudah terlewati. Jadwal yang lebih awal tetapi terlewat
+// (misalnya karena server mati) tidak dikirim susulan.
+func (s *ReceivableService) SendReminders(ctx context.Context) (int, error) {
+	if len(s.ReminderOffsets) == 0 {
+		return 0, nil
+	}
+
+	today := dateOf(s.Now())
+	minOffset := s.ReminderOffsets[0]
+	maxOffset := s.ReminderOffsets[len(s.ReminderOffsets)-1]
+
+	candidates, err := s.ReminderStore.ListReminderCandidates(ctx, today, minOffset, maxOffset)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, c := range candidates {
+		daysPastDue := int(today.Sub(dateOf(*c.Payment.DueDate)).Hours() / 24)
+		offset, ok := s.applicableOffset(daysPastDue)
+		if !ok {
+			continue
+		}
+
+		claimed, err := s.ReminderStore.ClaimReminder(ctx, c.Payment.ID, offset)
+		if err != nil {
+			return sent, err
+		}
+		if !claimed {
+			continue
+		}
+
+		if err := s.Mailer.Send(s.reminderMessage(c, daysPastDue)); err != nil {
+			log.Ctx(ctx).Error().Err(err).Int("payment_id", c.Payment.ID).Msg("Gagal mengirim email pengingat")
+			if err := s.ReminderStore.ReleaseReminder(ctx, c.Payment.ID, offset); err != nil {
+				log.Ctx(ctx).Error().Err(err).Int("payment_id", c.Payment.ID).Msg("Gagal melepas klaim pengingat")
+			}
+			continue
+		}
+		sent++
+	}
+
+	return sent, nil
+}
+
+// applicableOffset mengembalikan jadwal terbesar yang sudah terlewati.
+func (s *ReceivableService) applicableOffset(daysPastDue int) (int, bool) {
+	for i := len(s.ReminderOffsets) - 1; i >= 0; i-- {
+		if s.ReminderOffsets[i] <= daysPastDue {
+			return s.ReminderOffsets[i], true
+		}
+	}
+	return 0, false
+}
+
+var reminderTemplate = template.Must(template.New("reminder").Parse(`Yth. {{.CustomerName}},
+
+{{if lt .DaysPastDue 0 -}}
+Kami ingin mengingatkan bahwa tagihan Anda akan jatuh tempo dalam {{.DaysUntilDue}} hari.
+{{- else if eq .DaysPastDue 0 -}}
+Kami ingin mengingatkan bahwa tagihan Anda jatuh tempo hari ini.
+{{- else -}}
+Tagihan Anda telah melewati jatuh tempo selama {{.DaysPastDue}} hari.
+{{- end}}
+
+Referensi pembayaran : #{{.PaymentID}}
+Jumlah               : {{.Amount}}
+Tanggal jatuh tempo  : {{.DueDate}}
+
+Abaikan pesan ini jika Anda sudah melakukan pembayaran.
+
+Hormat kami,
+{{.CompanyName}}
+`))
+
+func (s *ReceivableService) reminderMessage(c model.ReminderCandidate, daysPastDue int) mailer.Message {
+	data := struct {
+		CustomerName string
+		PaymentID    int
+		Amount       string
+		DueDate      string
+		DaysPastDue  int
+		DaysUntilDue int
+		CompanyName  string
+	}{
+		CustomerName: c.Payment.CustomerName,
+		PaymentID:    c.Payment.ID,
+		Amount:       document.FormatRupiah(c.Payment.Amount),
+		DueDate:      document.FormatDate(*c.Payment.DueDate),
+		DaysPastDue:  daysPastDue,
+		DaysUntilDue: -daysPastDue,
+		CompanyName:  s.Company.Name,
+	}
+
+	var body bytes.Buffer
+	// Template statis dan datanya sederhana; kegagalan eksekusi hanya mungkin karena bug.
+	if err := reminderTemplate.Execute(&body, data); err != nil {
+		log.Error().Err(err).Msg("Gagal menyusun isi email pengingat")
+	}
+
+	subject := fmt.Sprintf("Pengingat pembayaran #%d", c.Payment.ID)
+	if daysPastDue > 0 {
+		subject = fmt.Sprintf("Tagihan #%d telah jatuh tempo", c.Payment.ID)
+	}
+
+	return mailer.Message{To: []string{c.CustomerEmail}, Subject: subject, Text: body.String()}
+}
+
+// RunJobs menandai pembayaran terlambat dan mengirim pengingat setiap interval sampai ctx dibatalkan.
+func (s *ReceivableService) RunJobs(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if marked, err := s.MarkOverdue(ctx); err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("Gagal menandai pembayaran terlambat")
+		} else if marked > 0 {
+			log.Ctx(ctx).Info().Int("marked", marked).Msg("Pembayaran ditandai terlambat")
+		}
+
+		if sent, err := s.SendReminders(ctx); err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("Gagal memproses pengingat pembayaran")
+		} else if sent > 0 {
+			log.Ctx(ctx).Info().Int("sent", sent).Msg("Email pengingat pembayaran terkirim")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"login-api/internal/bankstatement"
+	"login-api/internal/model"
+	"login-api/internal/storage/postgres"
+	"path/filepath"
+	"strings"
+)
+
+// ReconciliationService mencocokkan mutasi rekening bank dengan pembayaran.
+type ReconciliationService struct {
+	Store          *postgres.PostgresReconciliationStore
+	OrganizationID string
+	// DateWindowDays adalah selisih hari maksimum antara tanggal mutasi dan tanggal
+	// pembayaran atau jatuh tempo agar keduanya dianggap cocok.
+	DateWindowDays int
+}
+
+// NewReconciliationService membuat instance ReconciliationService baru.
+func NewReconciliationService(store *postgres.PostgresReconciliationStore, orgID string, windowDays int) *ReconciliationService {
+	return &ReconciliationService{Store: store, OrganizationID: orgID, DateWindowDays: windowDays}
+}
+
+// ImportStatement membaca berkas mutasi lalu mencocokkannya secara otomatis.
+// format boleh kosong agar ditebak dari isi berkas.
+func (s *ReconciliationService) ImportStatement(ctx context.Context, fileName string, data []byte, format string) (model.BankStatement, error) {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format == "" && strings.EqualFold(filepath.Ext(fileName), ".csv") {
+		format = bankstatement.FormatCSV
+	}
+
+	stmt, err := bankstatement.Parse(data, format)
+	if err != nil {
+		if errors.Is(err, bankstatement.ErrUnknownFormat) {
+			return model.BankStatement{}, invalid("format mutasi harus csv, mt940, atau camt053")
+		}
+		return model.BankStatement{}, invalid("mutasi rekening tidak dapat dibaca: " + err.Error())
+	}
+
+	sum := sha256.Sum256(data)
+	return s.Store.ImportStatement(ctx, s.OrganizationID, filepath.Base(fileName), hex.EncodeToString(sum[:]), stmt, s.DateWindowDays)
+}
+
+// ListLines mengambil baris sebuah mutasi, opsional disaring berdasarkan status.
+func (s *ReconciliationService) ListLines(ctx context.Context, statementID int, status string) ([]model.BankStatementLine, error) {
+	switch status {
+	case "", model.StatementLineUnmatched, model.StatementLineReview, model.StatementLineMatched, model.StatementLineIgnored:
+	default:
+		return nil, invalid("status baris mutasi tidak dikenali")
+	}
+
+	if _, err := s.Store.GetStatement(ctx, s.OrganizationID, statementID); err != nil {
+		return nil, err
+	}
+	return s.Store.ListLines(ctx, s.OrganizationID, statementID, status)
+}
+
+// MatchLine mencocokkan baris mutasi dengan pembayaran pilihan pengguna.
+func (s *ReconciliationService) MatchLine(ctx context.Context, lineID, paymentID int) (model.BankStatementLine, error) {
+	if paymentID <= 0 {
+		return model.BankStatementLine{}, invalid("payment_id wajib diisi")
+	}
+	return s.Store.MatchLine(ctx, s.OrganizationID, lineID, paymentID, s.DateWindowDays)
+}
+
+// UnmatchLine membatalkan kecocokan baris mutasi.
+func (s *ReconciliationService) UnmatchLine(ctx context.Context, lineID int) (model.BankStatementLine, error) {
+	return s.Store.UnmatchLine(ctx, s.OrganizationID, lineID)
+}
@@ -0,0 +1,377 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"html/template"
+	"login-api/internal/cron"
+	"login-api/internal/document"
+	"login-api/internal/mailer"
+	"login-api/internal/model"
+	"login-api/internal/storage"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// reportBatchSize membatasi jumlah laporan yang dikirim dalam satu putaran penjadwal.
+const reportBatchSize = 50
+
+// defaultReportSchedules adalah jadwal bawaan per periode: setiap pukul 07.00,
+// dan khusus laporan mingguan setiap hari Senin.
+var defaultReportSchedules = map[string]string{
+	model.ReportPeriodDay:  "0 7 * * *",
+	model.ReportPeriodWeek: "0 7 * * 1",
+}
+
+// ReportService mengelola langganan laporan dashboard dan mengirimkannya lewat
+// email sesuai jadwal. Penjadwal dapat berjalan di beberapa instance; hanya
+// instance yang memegang Lock yang mengirim laporan pada setiap putaran.
+type ReportService struct {
+	Store    storage.ReportStore
+	Payments storage.PaymentStore
+	Prefs    *PreferenceService
+	Lock     storage.Locker
+	Mailer   mailer.Mailer
+	Company  model.Company
+	Now      func() time.Time
+	// MaxAttempts adalah jumlah percobaan pengiriman untuk satu jadwal sebelum
+	// laporan itu dilewati dan langganan menunggu jadwal berikutnya.
+	MaxAttempts int
+	// RetryBackoff adalah jeda sebelum percobaan kedua; jeda berikutnya berlipat dua.
+	RetryBackoff time.Duration
+}
+
+// NewReportService membuat instance ReportService baru.
+func NewReportService(store storage.ReportStore, payments storage.PaymentStore, prefs *PreferenceService, lock storage.Locker, m mailer.Mailer, company model.Company) *ReportService {
+	return &ReportService{
+		Store:    store,
+		Payments: payments,
+		Prefs:    prefs,
+		Lock:     lock,
+		Mailer:   m,
+		Company:  company,
+		Now:      time.Now,
+
+		MaxAttempts:  5,
+		RetryBackoff: 5 * time.Minute,
+	}
+}
+
+// CreateSubscription memvalidasi lalu menyimpan langganan laporan baru. Period
+// kosong berarti harian, Schedule kosong memakai jadwal bawaan periode, dan
+// Timezone kosong memakai zona waktu efektif pengguna.
+func (s *ReportService) CreateSubscription(ctx context.Context, sub model.ReportSubscription) (model.ReportSubscription, error) {
+	if err := s.prepare(ctx, &sub); err != nil {
+		return model.ReportSubscription{}, err
+	}
+	return s.Store.CreateSubscription(ctx, sub)
+}
+
+// UpdateSubscription memvalidasi lalu memperbarui langganan laporan. Waktu
+// pengiriman berikutnya dihitung ulang dari jadwal yang baru.
+func (s *ReportService) UpdateSubscription(ctx context.Context, sub model.ReportSubscription) (model.ReportSubscription, error) {
+	if err := s.prepare(ctx, &sub); err != nil {
+		return model.ReportSubscription{}, err
+	}
+	return s.Store.UpdateSubscription(ctx, sub)
+}
+
+// prepare melengkapi nilai bawaan, memvalidasi sub, dan mengisi NextRunAt.
+func (s *ReportService) prepare(ctx context.Context, sub *model.ReportSubscription) error {
+	if sub.Period == "" {
+		sub.Period = model.ReportPeriodDay
+	}
+	if _, ok := defaultReportSchedules[sub.Period]; !ok {
+		return invalid("period harus day atau week")
+	}
+	if sub.Schedule == "" {
+		sub.Schedule = defaultReportSchedules[sub.Period]
+	}
+	schedule, err := cron.Parse(sub.Schedule)
+	if err != nil {
+		return invalid("schedule tidak valid: " + err.Error())
+	}
+
+	if sub.Timezone == "" {
+		if sub.Timezone, err = s.Prefs.Timezone(ctx, sub.Email); err != nil {
+			return err
+		}
+	}
+	loc, err := LoadTimezone(sub.Timezone)
+	if err != nil {
+		return err
+	}
+	sub.Timezone = loc.String()
+
+	sub.NextRunAt = schedule.Next(s.Now().In(loc))
+	if sub.NextRunAt.IsZero() {
+		return invalid("schedule tidak pernah berjalan")
+	}
+	return nil
+}
+
+// RunDue mengirim semua laporan yang sudah jatuh tempo. Jadwal yang terlewat
+// (misalnya karena server mati) hanya dikirim sekali, lalu jadwal berikutnya
+// dihitung dari waktu sekarang. Pengiriman yang gagal dicoba ulang dengan
+// backoff hingga MaxAttempts kali selama belum melewati jadwal berikutnya.
+func (s *ReportService) RunDue(ctx context.Context) (int, error) {
+	now := s.Now()
+	subs, err := s.Store.ListDueSubscriptions(ctx, now, reportBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, sub := range subs {
+		var runErr string
+		if err := s.send(ctx, sub); err != nil {
+			log.Ctx(ctx).Error().Err(err).Int("subscription_id", sub.ID).Msg("Gagal mengirim laporan terjadwal")
+			runErr = err.Error()
+		} else {
+			sent++
+		}
+
+		// Jadwal dan zona waktu sudah divalidasi saat disimpan; nilai nol hanya
+		// terjadi bila data diubah langsung di database.
+		next := time.Time{}
+		if schedule, err := cron.Parse(sub.Schedule); err == nil {
+			if loc, err := time.LoadLocation(sub.Timezone); err == nil {
+				next = schedule.Next(now.In(loc))
+			}
+		}
+		if next.IsZero() {
+			next = now.Add(24 * time.Hour)
+			runErr = "jadwal atau zona waktu tidak valid"
+		} else if runErr != "" {
+			if retryAt, ok := s.retryAt(sub.Attempts+1, now, next); ok {
+				if err := s.Store.MarkRetry(ctx, sub.ID, now, retryAt, runErr); err != nil {
+					return sent, err
+				}
+				continue
+			}
+		}
+		if err := s.Store.MarkRun(ctx, sub.ID, now, next, runErr); err != nil {
+			return sent, err
+		}
+	}
+
+	return sent, nil
+}
+
+// retryAt menghitung waktu percobaan ulang setelah attempts kali gagal untuk
+// jadwal yang sama. ok bernilai false bila batas percobaan tercapai atau
+// percobaan ulang baru terjadi setelah jadwal berikutnya next.
+func (s *ReportService) retryAt(attempts int, now, next time.Time) (t time.Time, ok bool) {
+	if attempts >= s.MaxAttempts {
+		return time.Time{}, false
+	}
+	t = now.Add(s.RetryBackoff << (attempts - 1))
+	return t, t.Before(next)
+}
+
+// RunScheduler menjalankan RunDue setiap interval sampai ctx dibatalkan, hanya
+// bila instance ini memegang kunci advisory penjadwal laporan.
+func (s *ReportService) RunScheduler(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		acquired, err := s.Lock.TryWithLock(ctx, func() error {
+			sent, err := s.RunDue(ctx)
+			if sent > 0 {
+				log.Ctx(ctx).Info().Int("sent", sent).Msg("Laporan terjadwal terkirim")
+			}
+			return err
+		})
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("Penjadwal laporan gagal")
+		} else if !acquired {
+			log.Ctx(ctx).Debug().Msg("Penjadwal laporan sedang dijalankan instance lain")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// reportRange mengembalikan periode lengkap terakhir sebelum at pada zona waktu
+// loc sebagai rentang [from, to).
+func reportRange(period string, at time.Time, loc *time.Location) (from, to time.Time) {
+	today := localMidnight(at, loc)
+	if period == model.ReportPeriodWeek {
+		// Minggu dimulai hari Senin, sama dengan ringkasan dashboard.
+		to = today.AddDate(0, 0, -(int(today.Weekday())+6)%7)
+		return to.AddDate(0, 0, -7), to
+	}
+	return today.AddDate(0, 0, -1), today
+}
+
+// send menyusun dan mengirim laporan untuk sub. Periode laporan dihitung dari
+// waktu jadwal, bukan waktu pengiriman, agar pengiriman yang tertunda tetap
+// melaporkan periode yang dimaksud.
+func (s *ReportService) send(ctx context.Context, sub model.ReportSubscription) error {
+	loc, err := time.LoadLocation(sub.Timezone)
+	if err != nil {
+		return fmt.Errorf("zona waktu %q tidak dikenali: %w", sub.Timezone, err)
+	}
+	from, to := reportRange(sub.Period, sub.NextRunAt, loc)
+
+	summary, err := s.Payments.GetPeriodSummary(ctx, from, to)
+	if err != nil {
+		return err
+	}
+	// Laporan harian ditampilkan per jam, laporan mingguan per hari.
+	interval := model.ChartIntervalHour
+	if sub.Period == model.ReportPeriodWeek {
+		interval = model.ChartIntervalDay
+	}
+	chart, err := s.Payments.GetChartData(ctx, model.ChartRange{From: from, To: to.Add(-time.Nanosecond), Interval: interval, Location: loc})
+	if err != nil {
+		return err
+	}
+	payments, err := s.Payments.ListPaymentsBetween(ctx, from, to)
+	if err != nil {
+		return err
+	}
+
+	msg, err := s.reportMessage(sub, from, to, loc, summary, chart, payments)
+	if err != nil {
+		return err
+	}
+	return s.Mailer.Send(msg)
+}
+
+var reportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<body style="font-family: Arial, sans-serif; color: #1f2937;">
+<h2 style="margin-bottom: 4px;">{{.Title}}</h2>
+<p style="margin-top: 0; color: #6b7280;">{{.Range}} ({{.Timezone}})</p>
+
+<table cellpadding="8" style="border-collapse: collapse; margin-bottom: 24px;">
+<tr>
+<td style="border: 1px solid #e5e7eb;"><div style="color: #6b7280;">Total pendapatan</div><strong>{{.Revenue}}</strong></td>
+<td style="border: 1px solid #e5e7eb;"><div style="color: #6b7280;">Pembayaran lunas</div><strong>{{.Summary.CompletedPayments}}</strong></td>
+<td style="border: 1px solid #e5e7eb;"><div style="color: #6b7280;">Tertunda</div><strong>{{.Summary.PendingPayments}}</strong></td>
+<td style="border: 1px solid #e5e7eb;"><div style="color: #6b7280;">Terlambat</div><strong>{{.Summary.OverduePayments}}</strong></td>
+</tr>
+</table>
+
+<h3>Pendapatan lunas per {{.IntervalName}}</h3>
+<table cellpadding="4" style="border-collapse: collapse; width: 100%; max-width: 640px;">
+{{- range .Bars}}
+<tr>
+<td style="white-space: nowrap; width: 80px;">{{.Label}}</td>
+<td><div style="background: #2563eb; height: 14px; width: {{.Width}}%;"></div></td>
+<td style="white-space: nowrap; text-align: right; width: 140px;">{{.Value}}</td>
+</tr>
+{{- end}}
+</table>
+
+<p>Rincian {{.PaymentCount}} pembayaran pada periode ini terlampir dalam file CSV.</p>
+<p style="color: #6b7280;">{{.CompanyName}}</p>
+</body>
+</html>
+`))
+
+type reportBar struct {
+	Label string
+	Value string
+	// Width adalah panjang batang dalam persen terhadap nilai terbesar.
+	Width int
+}
+
+func (s *ReportService) reportMessage(sub model.ReportSubscription, from, to time.Time, loc *time.Location, summary model.DashboardSummary, chart []model.ChartData, payments []model.Payment) (mailer.Message, error) {
+	title, intervalName := "Laporan harian pembayaran", "jam"
+	rangeText := document.FormatDate(from)
+	if sub.Period == model.ReportPeriodWeek {
+		title, intervalName = "Laporan mingguan pembayaran", "hari"
+		rangeText = document.FormatDate(from) + " - " + document.FormatDate(to.AddDate(0, 0, -1))
+	}
+
+	var peak float64
+	for _, c := range chart {
+		peak = math.Max(peak, c.Value)
+	}
+	bars := make([]reportBar, len(chart))
+	for i, c := range chart {
+		bars[i] = reportBar{Label: c.Label, Value: document.FormatRupiah(c.Value)}
+		if peak > 0 {
+			bars[i].Width = int(math.Round(c.Value / peak * 100))
+		}
+	}
+
+	var html bytes.Buffer
+	err := reportTemplate.Execute(&html, struct {
+		Title        string
+		Range        string
+		Timezone     string
+		Revenue      string
+		Summary      model.DashboardSummary
+		IntervalName string
+		Bars         []reportBar
+		PaymentCount int
+		CompanyName  string
+	}{title, rangeText, loc.String(), document.FormatRupiah(summary.TotalRevenue), summary, intervalName, bars, len(payments), s.Company.Name})
+	if err != nil {
+		return mailer.Message{}, fmt.Errorf("kesalahan saat menyusun isi email laporan: %w", err)
+	}
+
+	attachment, err := paymentsCSV(payments, loc)
+	if err != nil {
+		return mailer.Message{}, err
+	}
+
+	filename := "laporan-pembayaran-" + from.Format("2006-01-02")
+	if sub.Period == model.ReportPeriodWeek {
+		filename += "_" + to.AddDate(0, 0, -1).Format("2006-01-02")
+	}
+
+	text := fmt.Sprintf("%s\n%s (%s)\n\nTotal pendapatan : %s\nPembayaran lunas : %d\nTertunda         : %d\nTerlambat        : %d\n\nRincian %d pembayaran terlampir dalam file CSV.\n",
+		title, rangeText, loc, document.FormatRupiah(summary.TotalRevenue),
+		summary.CompletedPayments, summary.PendingPayments, summary.OverduePayments, len(payments))
+
+	return mailer.Message{
+		To:      []string{sub.Email},
+		Subject: fmt.Sprintf("%s %s", title, rangeText),
+		Text:    text,
+		HTML:    html.String(),
+		Attachments: []mailer.Attachment{
+			{Filename: filename + ".csv", ContentType: "text/csv; charset=utf-8", Data: attachment},
+		},
+	}, nil
+}
+
+// paymentsCSV menulis pembayaran sebagai CSV dengan waktu pada zona waktu loc.
+func paymentsCSV(payments []model.Payment, loc *time.Location) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write([]string{"id", "pelanggan", "jumlah", "status", "tanggal_pembayaran", "jatuh_tempo", "rekonsiliasi"})
+	for _, p := range payments {
+		due := ""
+		if p.DueDate != nil {
+			due = p.DueDate.Format("2006-01-02")
+		}
+		w.Write([]string{
+			strconv.Itoa(p.ID),
+			p.CustomerName,
+			strconv.FormatFloat(p.Amount, 'f', 2, 64),
+			p.Status,
+			p.PaymentDate.In(loc).Format("2006-01-02 15:04:05"),
+			due,
+			p.ReconciliationStatus,
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, fmt.Errorf("kesalahan saat menyusun lampiran CSV laporan: %w", err)
+	}
+	return buf.Bytes(), nil
+}
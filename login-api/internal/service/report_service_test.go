@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"login-api/internal/mailer"
+	"login-api/internal/model"
+	"login-api/internal/storage"
+	"login-api/internal/storage/memory"
+	"testing"
+	"time"
+)
+
+func TestReportServiceRetryAt(t *testing.T) {
+	s := &ReportService{MaxAttempts: 4, RetryBackoff: 5 * time.Minute}
+	now := time.Date(2026, time.October, 19, 7, 0, 0, 0, time.UTC)
+	tomorrow := now.AddDate(0, 0, 1)
+
+	tests := []struct {
+		name     string
+		attempts int
+		next     time.Time
+		want     time.Time
+		wantOK   bool
+	}{
+		{"kegagalan pertama", 1, tomorrow, now.Add(5 * time.Minute), true},
+		{"jeda berlipat dua", 2, tomorrow, now.Add(10 * time.Minute), true},
+		{"percobaan terakhir", 3, tomorrow, now.Add(20 * time.Minute), true},
+		{"batas percobaan tercapai", 4, tomorrow, time.Time{}, false},
+		{"melewati jadwal berikutnya", 2, now.Add(10 * time.Minute), now.Add(10 * time.Minute), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := s.retryAt(tt.attempts, now, tt.next)
+			if ok != tt.wantOK || (ok && !got.Equal(tt.want)) {
+				t.Errorf("retryAt(%d) = %v, %v, want %v, %v", tt.attempts, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+// fakeReportStore menyimpan langganan di memori dan mencatat hasil MarkRun serta
+// MarkRetry.
+type fakeReportStore struct {
+	storage.ReportStore
+	due     []model.ReportSubscription
+	listed  bool
+	runs    map[int]string
+	retries map[int]time.Time
+}
+
+func (f *fakeReportStore) ListDueSubscriptions(ctx context.Context, now time.Time, limit int) ([]model.ReportSubscription, error) {
+	f.listed = true
+	return f.due, nil
+}
+
+func (f *fakeReportStore) MarkRun(ctx context.Context, id int, ranAt, nextRunAt time.Time, runErr string) error {
+	f.runs[id] = runErr
+	return nil
+}
+
+func (f *fakeReportStore) MarkRetry(ctx context.Context, id int, ranAt, retryAt time.Time, runErr string) error {
+	f.retries[id] = retryAt
+	return nil
+}
+
+// fakeLocker menjalankan fn hanya bila held bernilai false.
+type fakeLocker struct {
+	held bool
+}
+
+func (l *fakeLocker) WithLock(ctx context.Context, fn func() error) error {
+	return fn()
+}
+
+func (l *fakeLocker) TryWithLock(ctx context.Context, fn func() error) (bool, error) {
+	if l.held {
+		return false, nil
+	}
+	return true, fn()
+}
+
+// failingMailer gagal mengirim ke alamat yang ada di failFor.
+type failingMailer struct {
+	failFor string
+	sent    []string
+}
+
+func (m *failingMailer) Send(msg mailer.Message) error {
+	if msg.To[0] == m.failFor {
+		return errors.New("smtp tidak tersedia")
+	}
+	m.sent = append(m.sent, msg.To[0])
+	return nil
+}
+
+func TestReportServiceRunDue(t *testing.T) {
+	now := time.Date(2026, time.October, 19, 7, 0, 0, 0, jakarta)
+	store := &fakeReportStore{
+		due: []model.ReportSubscription{
+			{ID: 1, Email: "andi@contoh.id", Period: model.ReportPeriodDay, Schedule: "0 7 * * *", Timezone: "Asia/Jakarta", NextRunAt: now},
+			{ID: 2, Email: "budi@contoh.id", Period: model.ReportPeriodDay, Schedule: "0 7 * * *", Timezone: "Asia/Jakarta", NextRunAt: now},
+		},
+		runs:    map[int]string{},
+		retries: map[int]time.Time{},
+	}
+	m := &failingMailer{failFor: "budi@contoh.id"}
+	s := NewReportService(store, memory.NewMemoryPaymentStore(nil), nil, &fakeLocker{}, m, model.Company{Name: "PT Contoh"})
+	s.Now = func() time.Time { return now }
+
+	sent, err := s.RunDue(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sent != 1 || len(m.sent) != 1 || m.sent[0] != "andi@contoh.id" {
+		t.Errorf("RunDue() = %d terkirim ke %v, want 1 ke andi@contoh.id", sent, m.sent)
+	}
+	if runErr, ok := store.runs[1]; !ok || runErr != "" {
+		t.Errorf("MarkRun langganan 1 = %q, %v, want tercatat berhasil", runErr, ok)
+	}
+	if got, want := store.retries[2], now.Add(s.RetryBackoff); !got.Equal(want) {
+		t.Errorf("MarkRetry langganan 2 pada %v, want %v", got, want)
+	}
+}
+
+func TestReportServiceRunSchedulerLockHeld(t *testing.T) {
+	store := &fakeReportStore{}
+	s := NewReportService(store, memory.NewMemoryPaymentStore(nil), nil, &fakeLocker{held: true}, &failingMailer{}, model.Company{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	s.RunScheduler(ctx, time.Hour)
+
+	if store.listed {
+		t.Error("RunScheduler menjalankan RunDue padahal kunci dipegang instance lain")
+	}
+}
@@ -0,0 +1,172 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"login-api/internal/model"
+	"login-api/internal/storage/postgres"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// ErrInvalidTransition dikembalikan saat perubahan status langganan tidak diizinkan.
+var ErrInvalidTransition = errors.New("perubahan status langganan tidak diizinkan")
+
+// SubscriptionService menyediakan logika bisnis untuk langganan dan penjadwal tagihannya.
+type SubscriptionService struct {
+	Store *postgres.PostgresSubscriptionStore
+	// Location menentukan tanggal kalender "hari ini" untuk jadwal tagihan.
+	Location *time.Location
+	// Now dapat diganti untuk mengendalikan waktu, misalnya pada demo.
+	Now func() time.Time
+}
+
+// NewSubscriptionService membuat instance SubscriptionService baru.
+func NewSubscriptionService(store *postgres.PostgresSubscriptionStore, loc *time.Location) *SubscriptionService {
+	return &SubscriptionService{Store: store, Location: loc, Now: time.Now}
+}
+
+// CreateSubscription memvalidasi lalu menyimpan langganan baru.
+func (s *SubscriptionService) CreateSubscription(ctx context.Context, sub model.Subscription) (model.Subscription, error) {
+	sub.Description = strings.TrimSpace(sub.Description)
+	if sub.IntervalCount == 0 {
+		sub.IntervalCount = 1
+	}
+
+	switch {
+	case sub.CustomerID <= 0:
+		return model.Subscription{}, invalid("pelanggan wajib dipilih")
+	case sub.Amount <= 0:
+		return model.Subscription{}, invalid("jumlah tagihan harus lebih dari nol")
+	case sub.IntervalUnit != model.IntervalDay && sub.IntervalUnit != model.IntervalWeek && sub.IntervalUnit != model.IntervalMonth:
+		return model.Subscription{}, invalid("satuan interval harus day, week, atau month")
+	case sub.IntervalCount < 0 || sub.IntervalCount > 366:
+		return model.Subscription{}, invalid("jumlah interval harus antara 1 dan 366")
+	}
+
+	if sub.StartDate.IsZero() {
+		sub.StartDate = s.today()
+	}
+	sub.StartDate = dateOf(sub.StartDate)
+
+	return s.Store.CreateSubscription(ctx, sub)
+}
+
+// PauseSubscription menghentikan sementara pembuatan tagihan.
+func (s *SubscriptionService) PauseSubscription(ctx context.Context, id int) (model.Subscription, error) {
+	sub, err := s.Store.GetSubscriptionByID(ctx, id)
+	if err != nil {
+		return model.Subscription{}, err
+	}
+	if sub.Status != model.SubscriptionStatusActive {
+		return model.Subscription{}, ErrInvalidTransition
+	}
+
+	sub.Status = model.SubscriptionStatusPaused
+	return sub, s.Store.UpdateSubscriptionSchedule(ctx, sub)
+}
+
+// ResumeSubscription mengaktifkan kembali langganan yang dijeda. Jatuh tempo yang
+// terlewati selama jeda dilewati, bukan ditagihkan sekaligus.
+func (s *SubscriptionService) ResumeSubscription(ctx context.Context, id int) (model.Subscription, error) {
+	sub, err := s.Store.GetSubscriptionByID(ctx, id)
+	if err != nil {
+		return model.Subscription{}, err
+	}
+	if sub.Status != model.SubscriptionStatusPaused {
+		return model.Subscription{}, ErrInvalidTransition
+	}
+
+	today := s.today()
+	for sub.NextDueDate.Before(today) {
+		sub.GeneratedCount++
+		sub.NextDueDate = sub.Occurrence(sub.GeneratedCount)
+	}
+	sub.Status = model.SubscriptionStatusActive
+	return sub, s.Store.UpdateSubscriptionSchedule(ctx, sub)
+}
+
+// CancelSubscription menghentikan langganan secara permanen.
+func (s *SubscriptionService) CancelSubscription(ctx context.Context, id int) (model.Subscription, error) {
+	sub, err := s.Store.GetSubscriptionByID(ctx, id)
+	if err != nil {
+		return model.Subscription{}, err
+	}
+	if sub.Status == model.SubscriptionStatusCancelled {
+		return model.Subscription{}, ErrInvalidTransition
+	}
+
+	sub.Status = model.SubscriptionStatusCancelled
+	return sub, s.Store.UpdateSubscriptionSchedule(ctx, sub)
+}
+
+// UpcomingPayments menghitung tagihan langganan yang akan jatuh tempo dalam
+// rentang days hari ke depan, termasuk beberapa kemunculan dari satu langganan.
+// "Hari ini" ditentukan menurut zona waktu loc.
+func (s *SubscriptionService) UpcomingPayments(ctx context.Context, days int, loc *time.Location) ([]model.UpcomingPayment, error) {
+	today := dateOf(s.Now().In(loc))
+	until := today.AddDate(0, 0, days)
+
+	subs, err := s.Store.ListActiveSubscriptionsDueBy(ctx, until)
+	if err != nil {
+		return nil, err
+	}
+
+	upcoming := []model.UpcomingPayment{}
+	for _, sub := range subs {
+		for n := sub.GeneratedCount; ; n++ {
+			due := sub.Occurrence(n)
+			if due.After(until) {
+				break
+			}
+			upcoming = append(upcoming, model.UpcomingPayment{
+				SubscriptionID: sub.ID,
+				CustomerID:     sub.CustomerID,
+				CustomerName:   sub.CustomerName,
+				Description:    sub.Description,
+				Amount:         sub.Amount,
+				DueDate:        due,
+			})
+		}
+	}
+
+	sort.SliceStable(upcoming, func(i, j int) bool {
+		return upcoming[i].DueDate.Before(upcoming[j].DueDate)
+	})
+	return upcoming, nil
+}
+
+// RunScheduler membuat tagihan langganan yang jatuh tempo setiap interval sampai ctx dibatalkan.
+func (s *SubscriptionService) RunScheduler(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		created, err := s.Store.GenerateDuePayments(ctx, s.today())
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("Penjadwal langganan gagal membuat tagihan")
+		} else if len(created) > 0 {
+			log.Ctx(ctx).Info().Int("created", len(created)).Msg("Penjadwal langganan membuat tagihan baru")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// today mengembalikan tanggal kalender saat ini menurut s.Location.
+func (s *SubscriptionService) today() time.Time {
+	return dateOf(s.Now().In(s.Location))
+}
+
+// dateOf mengembalikan tanggal kalender t sebagai tengah malam UTC, sesuai cara
+// pgx memindai kolom DATE.
+func dateOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
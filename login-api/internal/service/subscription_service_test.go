@@ -0,0 +1,30 @@
+package service
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSubscriptionServiceToday(t *testing.T) {
+	// 10 Maret 2026 pukul 20.00 UTC sudah 11 Maret di Jakarta.
+	now := time.Date(2026, time.March, 10, 20, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		loc  *time.Location
+		want time.Time
+	}{
+		{"Jakarta", jakarta, time.Date(2026, time.March, 11, 0, 0, 0, 0, time.UTC)},
+		{"UTC", time.UTC, time.Date(2026, time.March, 10, 0, 0, 0, 0, time.UTC)},
+		{"New York", mustLoadLocation("America/New_York"), time.Date(2026, time.March, 10, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewSubscriptionService(nil, tt.loc)
+			svc.Now = func() time.Time { return now }
+			if got := svc.today(); !got.Equal(tt.want) {
+				t.Errorf("today() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"login-api/internal/model"
+	"login-api/internal/storage"
+	"login-api/internal/validator"
+	"math/big"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// UserAdminService menyediakan operasi administrasi akun untuk operator, misalnya
+// lewat loginctl: membuat pengguna, mereset kata sandi, mengatur peran,
+// menonaktifkan akun, dan mencabut sesi.
+type UserAdminService struct {
+	Users storage.UserAdminStore
+}
+
+// NewUserAdminService membuat instance UserAdminService baru.
+func NewUserAdminService(users storage.UserAdminStore) *UserAdminService {
+	return &UserAdminService{Users: users}
+}
+
+// CreateUser membuat pengguna dengan peran role. Kata sandi kosong diganti kata
+// sandi acak yang dikembalikan agar dapat disampaikan ke pengguna.
+func (s *UserAdminService) CreateUser(ctx context.Context, email, password, role string) (string, error) {
+	email = strings.TrimSpace(email)
+	if err := validator.ValidateEmail(email); err != nil {
+		return "", invalid(err.Error())
+	}
+	if role == "" {
+		role = model.RoleUser
+	}
+	if err := validateRole(role); err != nil {
+		return "", err
+	}
+	password, hash, err := passwordHash(password)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := s.Users.GetUser(ctx, email); err == nil {
+		return "", ErrEmailExists
+	} else if !errors.Is(err, storage.ErrNotFound) {
+		return "", err
+	}
+	if err := s.Users.CreateUser(ctx, model.User{Email: email, PasswordHash: hash, Role: role}); err != nil {
+		return "", err
+	}
+	return password, nil
+}
+
+// ResetPassword mengganti kata sandi pengguna dan mencabut semua sesinya. Kata
+// sandi kosong diganti kata sandi acak yang dikembalikan.
+func (s *UserAdminService) ResetPassword(ctx context.Context, email, password string) (string, error) {
+	password, hash, err := passwordHash(password)
+	if err != nil {
+		return "", err
+	}
+	if _, err := s.Users.ResetUserPassword(ctx, email, hash); err != nil {
+		return "", err
+	}
+	return password, nil
+}
+
+// ListUsers mengambil semua pengguna, terurut berdasarkan email.
+func (s *UserAdminService) ListUsers(ctx context.Context) ([]model.User, error) {
+	return s.Users.ListUsers(ctx)
+}
+
+// SetRole mengubah peran pengguna. Peran baru berlaku pada token berikutnya.
+func (s *UserAdminService) SetRole(ctx context.Context, email, role string) (model.User, error) {
+	if err := validateRole(role); err != nil {
+		return model.User{}, err
+	}
+	return s.Users.SetUserRole(ctx, email, role)
+}
+
+// SetDisabled menonaktifkan akun beserta semua sesinya, atau mengaktifkannya kembali.
+func (s *UserAdminService) SetDisabled(ctx context.Context, email string, disabled bool) (model.User, error) {
+	return s.Users.SetUserDisabled(ctx, email, disabled)
+}
+
+// RevokeSessions mencabut semua refresh token pengguna. Access token yang sudah
+// terbit tetap berlaku sampai kedaluwarsa.
+func (s *UserAdminService) RevokeSessions(ctx context.Context, email string) (model.User, error) {
+	return s.Users.RevokeUserSessions(ctx, email)
+}
+
+func validateRole(role string) error {
+	for _, r := range model.Roles {
+		if r == role {
+			return nil
+		}
+	}
+	return invalid("peran harus salah satu dari: " + strings.Join(model.Roles, ", "))
+}
+
+// passwordHash memvalidasi password, atau membuat kata sandi acak bila kosong,
+// lalu mengembalikan kata sandi tersebut beserta hash bcrypt-nya.
+func passwordHash(password string) (string, string, error) {
+	if password == "" {
+		var err error
+		if password, err = randomPassword(); err != nil {
+			return "", "", err
+		}
+	}
+	if err := validator.ValidatePassword(password); err != nil {
+		return "", "", invalid(err.Error())
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", "", err
+	}
+	return password, string(hash), nil
+}
+
+// randomPassword membuat kata sandi 16 karakter yang memenuhi
+// validator.ValidatePassword.
+func randomPassword() (string, error) {
+	classes := []string{
+		"ABCDEFGHJKLMNPQRSTUVWXYZ",
+		"abcdefghijkmnopqrstuvwxyz",
+		"23456789",
+		"!@#$%^&*-_=+",
+	}
+	all := strings.Join(classes, "")
+
+	pick := func(set string) (byte, error) {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(set))))
+		if err != nil {
+			return 0, errors.New("gagal membuat kata sandi acak")
+		}
+		return set[n.Int64()], nil
+	}
+
+	buf := make([]byte, 16)
+	for i := range buf {
+		// Empat karakter pertama menjamin setiap jenis karakter terwakili.
+		set := all
+		if i < len(classes) {
+			set = classes[i]
+		}
+		c, err := pick(set)
+		if err != nil {
+			return "", err
+		}
+		buf[i] = c
+	}
+
+	// Acak urutan agar posisi jenis karakter tidak dapat ditebak.
+	for i := len(buf) - 1; i > 0; i-- {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+		if err != nil {
+			return "", errors.New("gagal membuat kata sandi acak")
+		}
+		j := n.Int64()
+		buf[i], buf[j] = buf[j], buf[i]
+	}
+	return string(buf), nil
+}
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"login-api/internal/model"
+	"login-api/internal/storage"
+	"login-api/internal/validator"
+	"sort"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// fakeUserStore adalah storage.UserAdminStore di memori untuk pengujian.
+type fakeUserStore struct {
+	users map[string]model.User
+	now   time.Time
+}
+
+func newFakeUserStore(users ...model.User) *fakeUserStore {
+	s := &fakeUserStore{users: map[string]model.User{}, now: time.Date(2026, time.October, 19, 9, 0, 0, 500000000, time.UTC)}
+	for _, u := range users {
+		s.users[u.Email] = u
+	}
+	return s
+}
+
+func (s *fakeUserStore) GetUser(_ context.Context, email string) (model.User, error) {
+	u, ok := s.users[email]
+	if !ok {
+		return model.User{}, storage.ErrNotFound
+	}
+	return u, nil
+}
+
+func (s *fakeUserStore) CreateUser(_ context.Context, user model.User) error {
+	s.users[user.Email] = user
+	return nil
+}
+
+func (s *fakeUserStore) UpdateUser(_ context.Context, oldEmail string, user model.User) error {
+	delete(s.users, oldEmail)
+	s.users[user.Email] = user
+	return nil
+}
+
+func (s *fakeUserStore) ListUsers(_ context.Context) ([]model.User, error) {
+	var list []model.User
+	for _, u := range s.users {
+		list = append(list, u)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Email < list[j].Email })
+	return list, nil
+}
+
+func (s *fakeUserStore) update(email string, change func(u *model.User)) (model.User, error) {
+	u, ok := s.users[email]
+	if !ok {
+		return model.User{}, storage.ErrNotFound
+	}
+	change(&u)
+	s.users[email] = u
+	return u, nil
+}
+
+func (s *fakeUserStore) ResetUserPassword(_ context.Context, email, passwordHash string) (model.User, error) {
+	return s.update(email, func(u *model.User) {
+		u.PasswordHash = passwordHash
+		u.SessionsRevokedAt = &s.now
+	})
+}
+
+func (s *fakeUserStore) SetUserRole(_ context.Context, email, role string) (model.User, error) {
+	return s.update(email, func(u *model.User) { u.Role = role })
+}
+
+func (s *fakeUserStore) SetUserDisabled(_ context.Context, email string, disabled bool) (model.User, error) {
+	return s.update(email, func(u *model.User) {
+		if !disabled {
+			u.DisabledAt = nil
+			return
+		}
+		if u.DisabledAt == nil {
+			u.DisabledAt = &s.now
+		}
+		u.SessionsRevokedAt = &s.now
+	})
+}
+
+func (s *fakeUserStore) RevokeUserSessions(_ context.Context, email string) (model.User, error) {
+	return s.update(email, func(u *model.User) { u.SessionsRevokedAt = &s.now })
+}
+
+func TestUserAdminServiceCreateUser(t *testing.T) {
+	ctx := context.Background()
+	store := newFakeUserStore(model.User{Email: "ada@contoh.id", Role: model.RoleUser})
+	svc := NewUserAdminService(store)
+
+	generated, err := svc.CreateUser(ctx, " baru@contoh.id ", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := validator.ValidatePassword(generated); err != nil {
+		t.Errorf("kata sandi acak %q tidak valid: %v", generated, err)
+	}
+	u, err := store.GetUser(ctx, "baru@contoh.id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Role != model.RoleUser {
+		t.Errorf("Role = %q, want %q", u.Role, model.RoleUser)
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte(generated)); err != nil {
+		t.Errorf("hash tidak cocok dengan kata sandi yang dikembalikan: %v", err)
+	}
+
+	if _, err := svc.CreateUser(ctx, "ada@contoh.id", "", ""); !errors.Is(err, ErrEmailExists) {
+		t.Errorf("CreateUser() email terdaftar error = %v, want %v", err, ErrEmailExists)
+	}
+	var validationErr *ValidationError
+	if _, err := svc.CreateUser(ctx, "lain@contoh.id", "", "pemilik"); !errors.As(err, &validationErr) {
+		t.Errorf("CreateUser() peran tidak dikenal error = %v, want ValidationError", err)
+	}
+	if _, err := svc.CreateUser(ctx, "bukan-email", "", ""); !errors.As(err, &validationErr) {
+		t.Errorf("CreateUser() email tidak valid error = %v, want ValidationError", err)
+	}
+}
+
+func TestUserAdminServiceAccountStatus(t *testing.T) {
+	ctx := context.Background()
+	store := newFakeUserStore(model.User{Email: "andi@contoh.id", Role: model.RoleUser})
+	svc := NewUserAdminService(store)
+
+	u, err := svc.SetDisabled(ctx, "andi@contoh.id", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.DisabledAt == nil || u.SessionsRevokedAt == nil {
+		t.Errorf("SetDisabled(true) = %+v, want nonaktif dan sesi dicabut", u)
+	}
+	if u, err = svc.SetDisabled(ctx, "andi@contoh.id", false); err != nil || u.DisabledAt != nil {
+		t.Errorf("SetDisabled(false) = %+v, %v, want aktif", u, err)
+	}
+
+	if _, err := svc.RevokeSessions(ctx, "tidak-ada@contoh.id"); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("RevokeSessions() error = %v, want %v", err, storage.ErrNotFound)
+	}
+	var validationErr *ValidationError
+	if _, err := svc.SetRole(ctx, "andi@contoh.id", "pemilik"); !errors.As(err, &validationErr) {
+		t.Errorf("SetRole() error = %v, want ValidationError", err)
+	}
+	if u, err := svc.SetRole(ctx, "andi@contoh.id", model.RoleAdmin); err != nil || u.Role != model.RoleAdmin {
+		t.Errorf("SetRole() = %+v, %v, want admin", u, err)
+	}
+
+	list, err := svc.ListUsers(ctx)
+	if err != nil || len(list) != 1 || list[0].Role != model.RoleAdmin {
+		t.Errorf("ListUsers() = %+v, %v", list, err)
+	}
+}
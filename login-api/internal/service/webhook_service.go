@@ -0,0 +1,276 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"login-api/internal/events"
+	"login-api/internal/gateway"
+	"login-api/internal/model"
+	"login-api/internal/storage/postgres"
+	"math"
+	mathrand "math/rand"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Header yang dikirim bersama setiap webhook keluar. Skema tanda tangan sama
+// dengan webhook gateway masuk: "t=<unix>,v1=<hex HMAC-SHA256("<t>.<body>")>".
+const (
+	WebhookSignatureHeader = "X-Webhook-Signature"
+	WebhookEventHeader     = "X-Webhook-Event"
+	WebhookEventIDHeader   = "X-Webhook-Event-ID"
+	WebhookDeliveryHeader  = "X-Webhook-Delivery"
+)
+
+// WebhookService mengelola langganan webhook keluar dan mengirimkan event ke
+// endpoint pelanggan dengan percobaan ulang exponential backoff.
+type WebhookService struct {
+	Store          *postgres.PostgresWebhookStore
+	OrganizationID string
+	Client         *http.Client
+	// MaxAttempts adalah jumlah percobaan sebelum pengiriman ditandai gagal.
+	MaxAttempts int
+	// BaseBackoff adalah jeda sebelum percobaan kedua; jeda berikutnya berlipat dua
+	// hingga MaxBackoff.
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+}
+
+// NewWebhookService membuat instance WebhookService baru.
+func NewWebhookService(store *postgres.PostgresWebhookStore, orgID string) *WebhookService {
+	return &WebhookService{
+		Store:          store,
+		OrganizationID: orgID,
+		Client:         &http.Client{Timeout: 10 * time.Second, Transport: newWebhookTransport()},
+		MaxAttempts:    8,
+		BaseBackoff:    30 * time.Second,
+		MaxBackoff:     6 * time.Hour,
+	}
+}
+
+func (s *WebhookService) Name() string { return "webhook" }
+
+// Publish mengantrikan event untuk semua langganan webhook yang berminat.
+func (s *WebhookService) Publish(ctx context.Context, ev events.Event) error {
+	_, err := s.Store.EnqueueDeliveries(ctx, s.OrganizationID, ev)
+	return err
+}
+
+// CreateSubscription memvalidasi lalu menyimpan langganan webhook. Secret dibuat
+// otomatis bila tidak diberikan.
+func (s *WebhookService) CreateSubscription(ctx context.Context, sub model.WebhookSubscription) (model.WebhookSubscription, error) {
+	if err := validateWebhookSubscription(ctx, &sub); err != nil {
+		return model.WebhookSubscription{}, err
+	}
+	if sub.Secret == "" {
+		sub.Secret = newWebhookSecret()
+	}
+	sub.OrganizationID = s.OrganizationID
+	return s.Store.CreateSubscription(ctx, sub)
+}
+
+// UpdateSubscription memvalidasi lalu memperbarui langganan webhook.
+func (s *WebhookService) UpdateSubscription(ctx context.Context, sub model.WebhookSubscription) (model.WebhookSubscription, error) {
+	if err := validateWebhookSubscription(ctx, &sub); err != nil {
+		return model.WebhookSubscription{}, err
+	}
+	sub.OrganizationID = s.OrganizationID
+	return s.Store.UpdateSubscription(ctx, sub)
+}
+
+func validateWebhookSubscription(ctx context.Context, sub *model.WebhookSubscription) error {
+	sub.URL = strings.TrimSpace(sub.URL)
+	u, err := url.Parse(sub.URL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Hostname() == "" {
+		return invalid("url webhook harus berupa URL http atau https yang valid")
+	}
+	if err := checkWebhookHost(ctx, u.Hostname()); err != nil {
+		return err
+	}
+
+	if len(sub.EventTypes) == 0 {
+		return invalid("pilih setidaknya satu jenis event")
+	}
+	for _, t := range sub.EventTypes {
+		if t != "*" && !events.IsKnownType(t) {
+			return invalid(fmt.Sprintf("jenis event %q tidak dikenali", t))
+		}
+	}
+	return nil
+}
+
+// errWebhookAddressNotAllowed menandai alamat tujuan webhook yang berada di
+// jaringan internal.
+var errWebhookAddressNotAllowed = errors.New("alamat tujuan webhook tidak diizinkan")
+
+// checkWebhookHost me-resolve host webhook dan menolaknya bila salah satu
+// alamatnya bukan alamat publik, agar endpoint webhook tidak dapat dipakai untuk
+// menjangkau layanan internal.
+func checkWebhookHost(ctx context.Context, host string) error {
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil || len(addrs) == 0 {
+		return invalid(fmt.Sprintf("host webhook %q tidak dapat di-resolve", host))
+	}
+	for _, addr := range addrs {
+		if !isPublicAddr(addr) {
+			return invalid("url webhook tidak boleh mengarah ke alamat loopback, privat, atau link-local")
+		}
+	}
+	return nil
+}
+
+// isPublicAddr melaporkan apakah addr boleh menjadi tujuan webhook.
+func isPublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsValid() &&
+		!addr.IsLoopback() &&
+		!addr.IsPrivate() &&
+		!addr.IsLinkLocalUnicast() &&
+		!addr.IsLinkLocalMulticast() &&
+		!addr.IsInterfaceLocalMulticast() &&
+		!addr.IsMulticast() &&
+		!addr.IsUnspecified()
+}
+
+// newWebhookTransport membuat transport yang memeriksa ulang setiap alamat yang
+// benar-benar di-dial. Pemeriksaan saat penyimpanan saja tidak cukup karena DNS
+// dapat berubah setelahnya dan redirect dapat mengarah ke host lain. Proxy
+// dimatikan agar pemeriksaan berlaku pada tujuan sebenarnya.
+func newWebhookTransport() *http.Transport {
+	dialer := &net.Dialer{
+		Timeout:   5 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   controlWebhookDial,
+	}
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.Proxy = nil
+	t.DialContext = dialer.DialContext
+	return t
+}
+
+func controlWebhookDial(_, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+	if !isPublicAddr(addrPort.Addr()) {
+		return fmt.Errorf("%w: %s", errWebhookAddressNotAllowed, address)
+	}
+	return nil
+}
+
+// RunDeliveryWorker mengirim pengiriman webhook yang jatuh tempo setiap interval sampai ctx dibatalkan.
+func (s *WebhookService) RunDeliveryWorker(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		s.deliverDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *WebhookService) deliverDue(ctx context.Context) {
+	for ctx.Err() == nil {
+		// Lease harus lebih lama dari timeout klien agar pengiriman yang sedang
+		// berlangsung tidak diklaim ulang oleh instance lain.
+		deliveries, err := s.Store.ClaimDueDeliveries(ctx, 50, 2*s.Client.Timeout+time.Minute)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("Gagal mengambil antrean webhook")
+			return
+		}
+		if len(deliveries) == 0 {
+			return
+		}
+
+		for _, d := range deliveries {
+			s.attempt(ctx, d)
+		}
+	}
+}
+
+func (s *WebhookService) attempt(ctx context.Context, d model.WebhookDelivery) {
+	statusCode, err := s.send(ctx, d)
+	if err == nil {
+		if err := s.Store.MarkDelivered(ctx, d.ID, statusCode); err != nil {
+			log.Ctx(ctx).Error().Err(err).Int("delivery_id", d.ID).Msg("Gagal mencatat webhook terkirim")
+		}
+		return
+	}
+
+	var code *int
+	if statusCode != 0 {
+		code = &statusCode
+	}
+
+	var next *time.Time
+	if attempts := d.Attempts + 1; attempts < s.MaxAttempts {
+		t := time.Now().Add(s.backoff(attempts))
+		next = &t
+	}
+
+	log.Ctx(ctx).Warn().Err(err).Int("delivery_id", d.ID).Str("url", d.URL).Bool("final", next == nil).Msg("Pengiriman webhook gagal")
+	if err := s.Store.MarkAttemptFailed(ctx, d.ID, code, err.Error(), next); err != nil {
+		log.Ctx(ctx).Error().Err(err).Int("delivery_id", d.ID).Msg("Gagal mencatat kegagalan webhook")
+	}
+}
+
+func (s *WebhookService) send(ctx context.Context, d model.WebhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "login-api-webhooks/1.0")
+	req.Header.Set(WebhookSignatureHeader, gateway.Sign([]byte(d.Secret), d.Payload, time.Now()))
+	req.Header.Set(WebhookEventHeader, d.EventType)
+	req.Header.Set(WebhookEventIDHeader, d.EventID)
+	req.Header.Set(WebhookDeliveryHeader, strconv.Itoa(d.ID))
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("endpoint membalas status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// backoff menghitung jeda sebelum percobaan berikutnya setelah attempts kali gagal,
+// dengan jitter +/-20% agar pengiriman ke endpoint yang sama tidak serempak.
+func (s *WebhookService) backoff(attempts int) time.Duration {
+	d := float64(s.BaseBackoff) * math.Pow(2, float64(attempts-1))
+	if d > float64(s.MaxBackoff) {
+		d = float64(s.MaxBackoff)
+	}
+	jitter := 0.8 + 0.4*mathrand.Float64()
+	return time.Duration(d * jitter)
+}
+
+func newWebhookSecret() string {
+	b := make([]byte, 32)
+	_, _ = rand.Read(b)
+	return "whsec_" + hex.EncodeToString(b)
+}
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"login-api/internal/model"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestValidateWebhookSubscriptionRejectsInternalHosts(t *testing.T) {
+	tests := []struct {
+		url     string
+		wantErr bool
+	}{
+		{"http://127.0.0.1:5432/", true},
+		{"http://169.254.169.254/latest/meta-data", true},
+		{"http://10.0.0.5/hook", true},
+		{"http://192.168.1.1/hook", true},
+		{"http://[::1]/hook", true},
+		{"http://[::ffff:127.0.0.1]/hook", true},
+		{"http://0.0.0.0/hook", true},
+		{"ftp://8.8.8.8/hook", true},
+		{"https://8.8.8.8/hook", false},
+	}
+	for _, tt := range tests {
+		sub := model.WebhookSubscription{URL: tt.url, EventTypes: []string{"*"}}
+		err := validateWebhookSubscription(context.Background(), &sub)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("validateWebhookSubscription(%q) error = %v, wantErr %v", tt.url, err, tt.wantErr)
+		}
+	}
+}
+
+func TestWebhookTransportRefusesInternalAddresses(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("permintaan tidak seharusnya sampai ke server loopback")
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Timeout: 5 * time.Second, Transport: newWebhookTransport()}
+	_, err := client.Post(srv.URL, "application/json", nil)
+	if !errors.Is(err, errWebhookAddressNotAllowed) {
+		t.Fatalf("error = %v, want %v", err, errWebhookAddressNotAllowed)
+	}
+}
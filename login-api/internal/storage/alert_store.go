@@ -0,0 +1,23 @@
+package storage
+
+import (
+	"context"
+	"login-api/internal/anomaly"
+	"login-api/internal/model"
+	"time"
+)
+
+// AlertStore menyediakan data untuk detektor anomali dan menyimpan peringatan
+// per organisasi.
+type AlertStore interface {
+	// GetDailyActivity mengembalikan satu baris per tanggal lokal loc dalam
+	// rentang [from, to), termasuk tanggal tanpa pembayaran.
+	GetDailyActivity(ctx context.Context, from, to time.Time, loc *time.Location) ([]model.DailyActivity, error)
+	GetAmountStats(ctx context.Context, from, to time.Time) (model.AmountStats, error)
+	// CreateAlert tidak mengubah apa pun dan mengembalikan created false bila
+	// fingerprint temuan sudah tersimpan.
+	CreateAlert(ctx context.Context, orgID string, f anomaly.Finding) (alert model.Alert, created bool, err error)
+	ListAlerts(ctx context.Context, orgID string, includeAcknowledged bool, limit int) ([]model.Alert, error)
+	// AcknowledgeAlert mengembalikan ErrNotFound bila peringatan tidak ada.
+	AcknowledgeAlert(ctx context.Context, orgID string, id int) (model.Alert, error)
+}
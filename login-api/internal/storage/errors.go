@@ -0,0 +1,20 @@
+package storage
+
+import "errors"
+
+// ErrNotFound dikembalikan oleh store ketika data yang diminta tidak ada.
+var ErrNotFound = errors.New("data tidak ditemukan")
+
+// ErrDuplicate dikembalikan oleh store ketika data melanggar batasan keunikan.
+var ErrDuplicate = errors.New("data sudah ada")
+
+// ErrConflict dikembalikan oleh store ketika keadaan data saat ini tidak
+// mengizinkan perubahan yang diminta.
+var ErrConflict = errors.New("keadaan data tidak mengizinkan perubahan")
+
+// ErrMismatch dikembalikan oleh store ketika dua data yang hendak dipasangkan
+// tidak saling sesuai, misalnya nominal pembayaran berbeda dari mutasi bank.
+var ErrMismatch = errors.New("data tidak saling sesuai")
+
+// ErrUnavailable dikembalikan oleh store ketika database tidak dapat dihubungi.
+var ErrUnavailable = errors.New("database tidak tersedia")
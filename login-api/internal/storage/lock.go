@@ -0,0 +1,13 @@
+package storage
+
+import "context"
+
+// Locker adalah kunci bersama antar-instance untuk memastikan suatu pekerjaan
+// hanya dijalankan oleh satu instance pada satu waktu.
+type Locker interface {
+	// WithLock menunggu sampai kunci diperoleh, menjalankan fn, lalu melepasnya.
+	WithLock(ctx context.Context, fn func() error) error
+	// TryWithLock menjalankan fn hanya bila kunci langsung diperoleh. acquired
+	// bernilai false bila instance lain sedang memegang kunci.
+	TryWithLock(ctx context.Context, fn func() error) (acquired bool, err error)
+}
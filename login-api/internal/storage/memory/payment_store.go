@@ -0,0 +1,261 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"login-api/internal/model"
+	"login-api/internal/storage"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MemoryPaymentStore adalah storage.PaymentStore di memori. Agregat dashboard
+// dihitung langsung dari daftar pembayaran, tanpa rollup per jam.
+type MemoryPaymentStore struct {
+	mu       sync.RWMutex
+	payments []model.Payment
+}
+
+// NewMemoryPaymentStore membuat MemoryPaymentStore berisi payments. Pembayaran
+// tanpa ID diberi ID berurutan.
+func NewMemoryPaymentStore(payments []model.Payment) *MemoryPaymentStore {
+	s := &MemoryPaymentStore{payments: make([]model.Payment, len(payments))}
+	nextID := 1
+	for _, p := range payments {
+		if p.ID >= nextID {
+			nextID = p.ID + 1
+		}
+	}
+	for i, p := range payments {
+		if p.ID == 0 {
+			p.ID = nextID
+			nextID++
+		}
+		if p.ReconciliationStatus == "" {
+			p.ReconciliationStatus = model.ReconciliationUnreconciled
+		}
+		s.payments[i] = p
+	}
+	return s
+}
+
+// filter mengembalikan salinan pembayaran yang memenuhi keep.
+func (s *MemoryPaymentStore) filter(keep func(p model.Payment) bool) []model.Payment {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var list []model.Payment
+	for _, p := range s.payments {
+		if keep(p) {
+			list = append(list, p)
+		}
+	}
+	return list
+}
+
+// GetPayments mengambil semua pembayaran, terbaru lebih dulu.
+func (s *MemoryPaymentStore) GetPayments(_ context.Context) ([]model.Payment, error) {
+	list := s.filter(func(model.Payment) bool { return true })
+	sortNewestFirst(list)
+	return list, nil
+}
+
+// GetPaymentsByCustomer mengambil semua pembayaran milik satu pelanggan.
+func (s *MemoryPaymentStore) GetPaymentsByCustomer(_ context.Context, customerID int) ([]model.Payment, error) {
+	list := s.filter(func(p model.Payment) bool { return p.CustomerID != nil && *p.CustomerID == customerID })
+	sortNewestFirst(list)
+	return list, nil
+}
+
+func sortNewestFirst(list []model.Payment) {
+	sort.SliceStable(list, func(i, j int) bool { return list[i].PaymentDate.After(list[j].PaymentDate) })
+}
+
+// GetPaymentByID mengambil satu pembayaran berdasarkan ID.
+func (s *MemoryPaymentStore) GetPaymentByID(_ context.Context, id int) (model.Payment, error) {
+	list := s.filter(func(p model.Payment) bool { return p.ID == id })
+	if len(list) == 0 {
+		return model.Payment{}, storage.ErrNotFound
+	}
+	return list[0], nil
+}
+
+// ListPaymentsBetween mengambil pembayaran dengan payment_date dalam rentang [from, to).
+func (s *MemoryPaymentStore) ListPaymentsBetween(_ context.Context, from, to time.Time) ([]model.Payment, error) {
+	list := s.filter(func(p model.Payment) bool { return within(p.PaymentDate, &from, &to) })
+	sort.SliceStable(list, func(i, j int) bool {
+		if !list[i].PaymentDate.Equal(list[j].PaymentDate) {
+			return list[i].PaymentDate.Before(list[j].PaymentDate)
+		}
+		return list[i].ID < list[j].ID
+	})
+	return list, nil
+}
+
+// within melaporkan apakah t berada dalam rentang [from, to). Batas nil berarti
+// tidak dibatasi.
+func within(t time.Time, from, to *time.Time) bool {
+	return (from == nil || !t.Before(*from)) && (to == nil || t.Before(*to))
+}
+
+// GetDashboardSummary menghitung data ringkasan untuk seluruh pembayaran.
+func (s *MemoryPaymentStore) GetDashboardSummary(_ context.Context) (model.DashboardSummary, error) {
+	return summarize(s.filter(func(model.Payment) bool { return true })), nil
+}
+
+// GetPeriodSummary menghitung data ringkasan untuk pembayaran dengan payment_date
+// dalam rentang [from, to).
+func (s *MemoryPaymentStore) GetPeriodSummary(_ context.Context, from, to time.Time) (model.DashboardSummary, error) {
+	return summarize(s.filter(func(p model.Payment) bool { return within(p.PaymentDate, &from, &to) })), nil
+}
+
+func summarize(list []model.Payment) model.DashboardSummary {
+	var summary model.DashboardSummary
+	for _, p := range list {
+		switch p.Status {
+		case model.PaymentStatusPaid:
+			summary.TotalRevenue += p.Amount
+			summary.CompletedPayments++
+		case model.PaymentStatusPending:
+			summary.PendingPayments++
+		case model.PaymentStatusOverdue:
+			summary.OverduePayments++
+		}
+	}
+	return summary
+}
+
+// GetStatusBreakdown menghitung jumlah dan nilai pembayaran per status. from dan
+// to bersifat opsional dan membatasi payment_date pada rentang [from, to).
+func (s *MemoryPaymentStore) GetStatusBreakdown(_ context.Context, from, to *time.Time) ([]model.StatusBreakdown, error) {
+	byStatus := map[string]*model.StatusBreakdown{}
+	for _, p := range s.filter(func(p model.Payment) bool { return within(p.PaymentDate, from, to) }) {
+		b, ok := byStatus[p.Status]
+		if !ok {
+			b = &model.StatusBreakdown{Status: p.Status}
+			byStatus[p.Status] = b
+		}
+		b.Count++
+		b.Amount += p.Amount
+	}
+
+	var breakdown []model.StatusBreakdown
+	for _, b := range byStatus {
+		breakdown = append(breakdown, *b)
+	}
+	sort.Slice(breakdown, func(i, j int) bool {
+		if breakdown[i].Count != breakdown[j].Count {
+			return breakdown[i].Count > breakdown[j].Count
+		}
+		return breakdown[i].Status < breakdown[j].Status
+	})
+	return breakdown, nil
+}
+
+// GetTopCustomers mengambil limit pelanggan dengan pendapatan lunas terbesar. from
+// dan to bersifat opsional dan membatasi payment_date pada rentang [from, to).
+func (s *MemoryPaymentStore) GetTopCustomers(_ context.Context, from, to *time.Time, limit int) ([]model.CustomerBreakdown, error) {
+	type key struct {
+		id   int
+		name string
+	}
+	byCustomer := map[key]*model.CustomerBreakdown{}
+	for _, p := range s.filter(func(p model.Payment) bool {
+		return p.Status == model.PaymentStatusPaid && within(p.PaymentDate, from, to)
+	}) {
+		k := key{id: -1, name: p.CustomerName}
+		if p.CustomerID != nil {
+			k.id = *p.CustomerID
+		}
+		b, ok := byCustomer[k]
+		if !ok {
+			b = &model.CustomerBreakdown{CustomerID: p.CustomerID, CustomerName: p.CustomerName}
+			byCustomer[k] = b
+		}
+		b.Revenue += p.Amount
+		b.PaymentCount++
+	}
+
+	var breakdown []model.CustomerBreakdown
+	for _, b := range byCustomer {
+		breakdown = append(breakdown, *b)
+	}
+	sort.Slice(breakdown, func(i, j int) bool {
+		if breakdown[i].Revenue != breakdown[j].Revenue {
+			return breakdown[i].Revenue > breakdown[j].Revenue
+		}
+		return breakdown[i].CustomerName < breakdown[j].CustomerName
+	})
+	if len(breakdown) > limit {
+		breakdown = breakdown[:limit]
+	}
+	return breakdown, nil
+}
+
+// GetChartData mengambil pendapatan lunas yang diagregasi per interval dalam
+// rentang r. Interval tanpa pembayaran tetap muncul dengan nilai 0.
+func (s *MemoryPaymentStore) GetChartData(_ context.Context, r model.ChartRange) ([]model.ChartData, error) {
+	if !r.ValidInterval() {
+		return nil, fmt.Errorf("interval grafik %q tidak dikenali", r.Interval)
+	}
+	buckets := r.Buckets()
+	if len(buckets) == 0 {
+		return nil, nil
+	}
+
+	values := make([]float64, len(buckets))
+	end := r.Next(buckets[len(buckets)-1])
+	for _, p := range s.filter(func(p model.Payment) bool {
+		return p.Status == model.PaymentStatusPaid && within(p.PaymentDate, &buckets[0], &end)
+	}) {
+		i := sort.Search(len(buckets), func(i int) bool { return buckets[i].After(p.PaymentDate) }) - 1
+		values[i] += p.Amount
+	}
+
+	chartData := make([]model.ChartData, len(buckets))
+	for i, b := range buckets {
+		chartData[i] = model.ChartData{Label: r.Label(b), Value: values[i]}
+	}
+	return chartData, nil
+}
+
+// MarkOverduePayments mengubah status pembayaran tertunda yang due_date-nya sudah
+// lewat dari tanggal today menjadi Terlambat. Mengembalikan pembayaran yang diubah.
+func (s *MemoryPaymentStore) MarkOverduePayments(_ context.Context, today time.Time) ([]model.Payment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var marked []model.Payment
+	for i, p := range s.payments {
+		if p.Status == model.PaymentStatusPending && p.DueDate != nil && daysBetween(*p.DueDate, today) > 0 {
+			s.payments[i].Status = model.PaymentStatusOverdue
+			marked = append(marked, s.payments[i])
+		}
+	}
+	return marked, nil
+}
+
+// GetAgingReport mengelompokkan pembayaran belum lunas yang sudah lewat jatuh
+// tempo berdasarkan umur keterlambatannya per tanggal today. Seperti
+// MarkOverduePayments, pembayaran yang jatuh tempo hari ini belum terhitung.
+func (s *MemoryPaymentStore) GetAgingReport(_ context.Context, today time.Time) (model.AgingReport, error) {
+	report := model.NewAgingReport(today)
+	for _, p := range s.filter(func(p model.Payment) bool {
+		return (p.Status == model.PaymentStatusPending || p.Status == model.PaymentStatusOverdue) && p.DueDate != nil
+	}) {
+		if days := daysBetween(*p.DueDate, today); days > 0 {
+			report.Add(days, 1, p.Amount)
+		}
+	}
+	return report, nil
+}
+
+// daysBetween menghitung selisih hari kalender dari from ke to. Seperti kolom
+// DATE, jam dan zona waktu keduanya diabaikan.
+func daysBetween(from, to time.Time) int {
+	date := func(t time.Time) time.Time {
+		y, m, d := t.Date()
+		return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	}
+	return int(date(to).Sub(date(from)).Hours() / 24)
+}
@@ -0,0 +1,80 @@
+package memory
+
+import (
+	"context"
+	"login-api/internal/model"
+	"testing"
+	"time"
+)
+
+func TestMemoryPaymentStoreAgingReport(t *testing.T) {
+	today := time.Date(2026, time.March, 11, 15, 0, 0, 0, time.UTC)
+	due := func(daysAgo int) *time.Time {
+		d := time.Date(2026, time.March, 11-daysAgo, 0, 0, 0, 0, time.UTC)
+		return &d
+	}
+	store := NewMemoryPaymentStore([]model.Payment{
+		{Amount: 1, Status: model.PaymentStatusPending, DueDate: due(-1)},
+		{Amount: 2, Status: model.PaymentStatusPending, DueDate: due(0)},
+		{Amount: 4, Status: model.PaymentStatusPending, DueDate: due(1)},
+		{Amount: 8, Status: model.PaymentStatusOverdue, DueDate: due(30)},
+		{Amount: 16, Status: model.PaymentStatusOverdue, DueDate: due(31)},
+		{Amount: 32, Status: model.PaymentStatusOverdue, DueDate: due(61)},
+		{Amount: 64, Status: model.PaymentStatusOverdue, DueDate: due(90)},
+		{Amount: 128, Status: model.PaymentStatusOverdue, DueDate: due(91)},
+		{Amount: 256, Status: model.PaymentStatusPaid, DueDate: due(45)},
+		{Amount: 512, Status: model.PaymentStatusPending},
+	})
+
+	report, err := store.GetAgingReport(context.Background(), today)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Jatuh tempo hari ini, belum jatuh tempo, lunas, dan tanpa due date tidak dihitung.
+	want := map[string]struct {
+		count  int64
+		amount float64
+	}{
+		"0-30":  {2, 12},
+		"31-60": {1, 16},
+		"61-90": {2, 96},
+		"90+":   {1, 128},
+	}
+	for _, b := range report.Buckets {
+		if w := want[b.Label]; b.Count != w.count || b.Amount != w.amount {
+			t.Errorf("bucket %s = (%d, %v), want (%d, %v)", b.Label, b.Count, b.Amount, w.count, w.amount)
+		}
+	}
+	if report.TotalCount != 6 || report.TotalAmount != 252 {
+		t.Errorf("total = (%d, %v), want (6, 252)", report.TotalCount, report.TotalAmount)
+	}
+	if report.AsOf != "2026-03-11" {
+		t.Errorf("AsOf = %q, want 2026-03-11", report.AsOf)
+	}
+}
+
+func TestMemoryPaymentStoreMarkOverdueMatchesAging(t *testing.T) {
+	today := time.Date(2026, time.March, 11, 0, 0, 0, 0, time.UTC)
+	yesterday := today.AddDate(0, 0, -1)
+	store := NewMemoryPaymentStore([]model.Payment{
+		{Amount: 1, Status: model.PaymentStatusPending, DueDate: &today},
+		{Amount: 2, Status: model.PaymentStatusPending, DueDate: &yesterday},
+	})
+
+	marked, err := store.MarkOverduePayments(context.Background(), today)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(marked) != 1 || marked[0].Amount != 2 {
+		t.Fatalf("MarkOverduePayments() = %+v, want hanya pembayaran yang jatuh tempo kemarin", marked)
+	}
+
+	report, err := store.GetAgingReport(context.Background(), today)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.TotalCount != int64(len(marked)) {
+		t.Errorf("laporan umur piutang menghitung %d pembayaran, want %d seperti MarkOverduePayments", report.TotalCount, len(marked))
+	}
+}
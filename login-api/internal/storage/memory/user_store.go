@@ -0,0 +1,103 @@
+// Package memory menyediakan implementasi store yang menyimpan data di memori
+// proses. Dipakai oleh mode demo tanpa database dan untuk menguji handler serta
+// service tanpa PostgreSQL. Data hilang saat proses berhenti dan event outbox
+// tidak ditulis.
+package memory
+
+import (
+	"context"
+	"login-api/internal/model"
+	"login-api/internal/storage"
+	"sync"
+	"time"
+)
+
+// MemoryUserStore adalah storage.UserStore dan storage.UserPreferenceStore di memori.
+type MemoryUserStore struct {
+	mu        sync.RWMutex
+	users     map[string]model.User
+	timezones map[string]string
+}
+
+// NewMemoryUserStore membuat MemoryUserStore berisi users.
+func NewMemoryUserStore(users ...model.User) *MemoryUserStore {
+	s := &MemoryUserStore{users: map[string]model.User{}, timezones: map[string]string{}}
+	for _, u := range users {
+		s.put(u)
+	}
+	return s
+}
+
+func (s *MemoryUserStore) put(user model.User) {
+	if user.Role == "" {
+		user.Role = model.RoleUser
+	}
+	if user.CreatedAt.IsZero() {
+		user.CreatedAt = time.Now()
+	}
+	s.users[user.Email] = user
+}
+
+// GetUser mengambil pengguna berdasarkan email.
+func (s *MemoryUserStore) GetUser(_ context.Context, email string) (model.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	user, ok := s.users[email]
+	if !ok {
+		return model.User{}, storage.ErrNotFound
+	}
+	return user, nil
+}
+
+// CreateUser menyimpan pengguna baru. Email yang sudah terdaftar menghasilkan
+// storage.ErrDuplicate.
+func (s *MemoryUserStore) CreateUser(_ context.Context, user model.User) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.users[user.Email]; exists {
+		return storage.ErrDuplicate
+	}
+	s.put(user)
+	return nil
+}
+
+// UpdateUser mengganti email dan hash kata sandi pengguna oldEmail.
+func (s *MemoryUserStore) UpdateUser(_ context.Context, oldEmail string, user model.User) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	current, ok := s.users[oldEmail]
+	if !ok {
+		return storage.ErrNotFound
+	}
+	current.Email = user.Email
+	current.PasswordHash = user.PasswordHash
+
+	delete(s.users, oldEmail)
+	s.users[current.Email] = current
+	if tz, ok := s.timezones[oldEmail]; ok {
+		delete(s.timezones, oldEmail)
+		s.timezones[current.Email] = tz
+	}
+	return nil
+}
+
+// GetUserTimezone mengambil zona waktu pilihan pengguna.
+func (s *MemoryUserStore) GetUserTimezone(_ context.Context, email string) (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if _, ok := s.users[email]; !ok {
+		return "", storage.ErrNotFound
+	}
+	return s.timezones[email], nil
+}
+
+// SetUserTimezone menyimpan zona waktu pilihan pengguna.
+func (s *MemoryUserStore) SetUserTimezone(_ context.Context, email, tz string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.users[email]; !ok {
+		return storage.ErrNotFound
+	}
+	s.timezones[email] = tz
+	return nil
+}
@@ -0,0 +1,27 @@
+package storage
+
+import (
+	"context"
+	"login-api/internal/model"
+	"time"
+)
+
+// PaymentStore menyimpan pembayaran dan menghitung agregat dashboard darinya.
+// Semua method mengikuti pembatalan dan tenggat ctx.
+type PaymentStore interface {
+	GetPayments(ctx context.Context) ([]model.Payment, error)
+	GetPaymentsByCustomer(ctx context.Context, customerID int) ([]model.Payment, error)
+	GetPaymentByID(ctx context.Context, id int) (model.Payment, error)
+	// ListPaymentsBetween mengambil pembayaran dengan payment_date dalam rentang
+	// [from, to), urut dari yang terlama.
+	ListPaymentsBetween(ctx context.Context, from, to time.Time) ([]model.Payment, error)
+
+	GetDashboardSummary(ctx context.Context) (model.DashboardSummary, error)
+	GetPeriodSummary(ctx context.Context, from, to time.Time) (model.DashboardSummary, error)
+	GetStatusBreakdown(ctx context.Context, from, to *time.Time) ([]model.StatusBreakdown, error)
+	GetTopCustomers(ctx context.Context, from, to *time.Time, limit int) ([]model.CustomerBreakdown, error)
+	GetChartData(ctx context.Context, r model.ChartRange) ([]model.ChartData, error)
+
+	MarkOverduePayments(ctx context.Context, today time.Time) ([]model.Payment, error)
+	GetAgingReport(ctx context.Context, today time.Time) (model.AgingReport, error)
+}
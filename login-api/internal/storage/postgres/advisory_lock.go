@@ -0,0 +1,73 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog/log"
+)
+
+// Kunci advisory yang dipakai aplikasi. Nilainya bebas asalkan unik.
+const (
+	ReportSchedulerLockKey int64 = 4201
+	MigrationLockKey       int64 = 4301
+	DemoSeedLockKey        int64 = 4401
+)
+
+// AdvisoryLock adalah kunci advisory sesi PostgreSQL untuk memilih satu instance
+// yang menjalankan pekerjaan tertentu. Kunci terikat pada satu koneksi, sehingga
+// otomatis lepas bila instance pemegangnya mati.
+type AdvisoryLock struct {
+	DB  *pgxpool.Pool
+	Key int64
+}
+
+func NewAdvisoryLock(db *pgxpool.Pool, key int64) *AdvisoryLock {
+	return &AdvisoryLock{DB: db, Key: key}
+}
+
+// WithLock menunggu sampai kunci berhasil diambil, menjalankan fn, lalu melepasnya.
+func (l *AdvisoryLock) WithLock(ctx context.Context, fn func() error) error {
+	_, err := l.run(ctx, false, fn)
+	return err
+}
+
+// TryWithLock menjalankan fn bila kunci berhasil diambil tanpa menunggu, lalu
+// melepasnya. acquired bernilai false bila instance lain sedang memegang kunci.
+func (l *AdvisoryLock) TryWithLock(ctx context.Context, fn func() error) (acquired bool, err error) {
+	return l.run(ctx, true, fn)
+}
+
+// run mengambil kunci, menunggu kecuali try bernilai true, lalu menjalankan fn
+// dan melepas kunci pada koneksi yang sama.
+func (l *AdvisoryLock) run(ctx context.Context, try bool, fn func() error) (acquired bool, err error) {
+	conn, err := l.DB.Acquire(ctx)
+	if err != nil {
+		return false, fmt.Errorf("kesalahan saat mengambil koneksi untuk kunci advisory: %w", err)
+	}
+	defer conn.Release()
+
+	if try {
+		err = conn.QueryRow(ctx, `SELECT pg_try_advisory_lock($1)`, l.Key).Scan(&acquired)
+	} else {
+		_, err = conn.Exec(ctx, `SELECT pg_advisory_lock($1)`, l.Key)
+		acquired = err == nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("kesalahan saat mengambil kunci advisory: %w", err)
+	}
+	if !acquired {
+		return false, nil
+	}
+	defer func() {
+		// Context terpisah agar kunci tetap dilepas meskipun ctx sudah dibatalkan.
+		if _, err := conn.Exec(context.Background(), `SELECT pg_advisory_unlock($1)`, l.Key); err != nil {
+			log.Ctx(ctx).Error().Err(err).Int64("key", l.Key).Msg("Gagal melepas kunci advisory")
+			// Koneksi yang masih memegang kunci tidak boleh kembali ke pool.
+			conn.Conn().Close(context.Background())
+		}
+	}()
+
+	return true, fn()
+}
@@ -0,0 +1,175 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"login-api/internal/anomaly"
+	"login-api/internal/events"
+	"login-api/internal/model"
+	"login-api/internal/storage"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog/log"
+)
+
+type PostgresAlertStore struct {
+	DB *DB
+}
+
+func NewPostgresAlertStore(db *DB) *PostgresAlertStore {
+	return &PostgresAlertStore{DB: db}
+}
+
+const alertColumns = `id, kind, severity, fingerprint, message, details, detected_at, acknowledged_at`
+
+// GetDailyActivity menghitung pendapatan lunas dan jumlah pembayaran belum lunas
+// per tanggal lokal loc untuk payment_date dalam rentang [from, to). Tanggal tanpa
+// pembayaran tetap muncul dengan nilai 0. Deret hari dibentuk sebagai timestamp
+// lokal agar batas harinya tidak bergeser mengikuti zona waktu sesi database.
+func (s *PostgresAlertStore) GetDailyActivity(ctx context.Context, from, to time.Time, loc *time.Location) ([]model.DailyActivity, error) {
+	query := `
+        SELECT
+            day::date,
+            COALESCE(SUM(p.amount) FILTER (WHERE p.status = 'Lunas'), 0),
+            COUNT(p.id) FILTER (WHERE p.status IN ('Tertunda', 'Terlambat', 'Dibatalkan'))
+        FROM generate_series(
+                (($1::timestamptz AT TIME ZONE $3)::date)::timestamp,
+                (($2::timestamptz AT TIME ZONE $3)::date - 1)::timestamp,
+                '1 day'::interval
+            ) AS day
+        LEFT JOIN payments p ON p.payment_date >= (day AT TIME ZONE $3)
+                            AND p.payment_date < ((day + '1 day'::interval) AT TIME ZONE $3)
+        GROUP BY day
+        ORDER BY day;
+    `
+	rows, err := s.DB.Query(ctx, query, from, to, loc.String())
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Gagal menjalankan query untuk aktivitas pembayaran harian")
+		return nil, err
+	}
+	defer rows.Close()
+
+	var activity []model.DailyActivity
+	for rows.Next() {
+		var d model.DailyActivity
+		if err := rows.Scan(&d.Date, &d.Revenue, &d.Unsettled); err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("Gagal memindai baris aktivitas pembayaran harian")
+			return nil, err
+		}
+		activity = append(activity, d)
+	}
+
+	return activity, rows.Err()
+}
+
+// GetAmountStats menghitung rata-rata dan simpangan baku nominal pembayaran
+// dengan payment_date dalam rentang [from, to).
+func (s *PostgresAlertStore) GetAmountStats(ctx context.Context, from, to time.Time) (model.AmountStats, error) {
+	var stats model.AmountStats
+	err := s.DB.QueryRow(ctx, `
+        SELECT COALESCE(AVG(amount), 0), COALESCE(STDDEV_POP(amount), 0), COUNT(*)
+        FROM payments
+        WHERE payment_date >= $1 AND payment_date < $2`,
+		from, to,
+	).Scan(&stats.Mean, &stats.StdDev, &stats.Count)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Gagal menghitung statistik nominal pembayaran")
+		return model.AmountStats{}, err
+	}
+
+	return stats, nil
+}
+
+// CreateAlert menyimpan peringatan dari temuan f. Bila peringatan dengan
+// fingerprint yang sama sudah ada, created bernilai false dan tidak ada yang
+// berubah. Event alert.created ditulis ke outbox dalam transaksi yang sama.
+func (s *PostgresAlertStore) CreateAlert(ctx context.Context, orgID string, f anomaly.Finding) (alert model.Alert, created bool, err error) {
+	details, err := json.Marshal(f.Details)
+	if err != nil {
+		return model.Alert{}, false, fmt.Errorf("kesalahan saat menyusun detail peringatan: %w", err)
+	}
+
+	tx, err := s.DB.Begin(ctx)
+	if err != nil {
+		return model.Alert{}, false, fmt.Errorf("kesalahan saat memulai transaksi peringatan: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	alert, err = scanAlert(tx.QueryRow(ctx, `
+        INSERT INTO alerts (organization_id, kind, severity, fingerprint, message, details)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        ON CONFLICT (organization_id, fingerprint) DO NOTHING
+        RETURNING `+alertColumns,
+		orgID, f.Kind, f.Severity, f.Fingerprint, f.Message, details))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.Alert{}, false, nil
+		}
+		return model.Alert{}, false, fmt.Errorf("kesalahan saat menyimpan peringatan: %w", err)
+	}
+
+	if err := writeOutbox(ctx, tx, events.AlertCreated, alert); err != nil {
+		return model.Alert{}, false, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return model.Alert{}, false, fmt.Errorf("kesalahan saat menyimpan peringatan: %w", err)
+	}
+
+	return alert, true, nil
+}
+
+// ListAlerts mengambil peringatan terbaru organisasi. Peringatan yang sudah
+// ditindaklanjuti hanya disertakan bila includeAcknowledged bernilai true.
+func (s *PostgresAlertStore) ListAlerts(ctx context.Context, orgID string, includeAcknowledged bool, limit int) ([]model.Alert, error) {
+	rows, err := s.DB.Query(ctx, `SELECT `+alertColumns+`
+        FROM alerts
+        WHERE organization_id = $1 AND ($2 OR acknowledged_at IS NULL)
+        ORDER BY detected_at DESC, id DESC
+        LIMIT $3`,
+		orgID, includeAcknowledged, limit)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Gagal menjalankan query untuk mengambil peringatan")
+		return nil, err
+	}
+	defer rows.Close()
+
+	var alerts []model.Alert
+	for rows.Next() {
+		a, err := scanAlert(rows)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("Gagal memindai baris peringatan")
+			return nil, err
+		}
+		alerts = append(alerts, a)
+	}
+
+	return alerts, rows.Err()
+}
+
+// AcknowledgeAlert menandai peringatan sudah ditindaklanjuti. Peringatan yang
+// sudah ditandai sebelumnya dikembalikan apa adanya.
+func (s *PostgresAlertStore) AcknowledgeAlert(ctx context.Context, orgID string, id int) (model.Alert, error) {
+	alert, err := scanAlert(s.DB.QueryRow(ctx, `
+        UPDATE alerts
+        SET acknowledged_at = COALESCE(acknowledged_at, NOW())
+        WHERE organization_id = $1 AND id = $2
+        RETURNING `+alertColumns,
+		orgID, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.Alert{}, storage.ErrNotFound
+		}
+		return model.Alert{}, fmt.Errorf("kesalahan saat memperbarui peringatan: %w", err)
+	}
+
+	return alert, nil
+}
+
+func scanAlert(row pgx.Row) (model.Alert, error) {
+	var a model.Alert
+	err := row.Scan(&a.ID, &a.Kind, &a.Severity, &a.Fingerprint, &a.Message, &a.Details, &a.DetectedAt, &a.AcknowledgedAt)
+	return a, err
+}
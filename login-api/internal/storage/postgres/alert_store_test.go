@@ -0,0 +1,100 @@
+package postgres
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// newTestDB membuka koneksi ke TEST_DATABASE_URL. Test dilewati bila variabel
+// tersebut kosong. Pool dibatasi satu koneksi agar tabel sementara terlihat di
+// setiap query.
+func newTestDB(t *testing.T) *DB {
+	t.Helper()
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL tidak diisi")
+	}
+
+	cfg, err := pgxpool.ParseConfig(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.MaxConns = 1
+	pool, err := pgxpool.NewWithConfig(context.Background(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(pool.Close)
+	return NewDB(pool, 5*time.Second)
+}
+
+func TestGetDailyActivityLocalDays(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	// Zona waktu sesi sengaja berbeda dari loc agar batas hari tidak bergantung padanya.
+	setup := []string{
+		`SET TIME ZONE 'America/New_York'`,
+		`CREATE TEMP TABLE payments (
+            id SERIAL PRIMARY KEY,
+            amount NUMERIC NOT NULL,
+            status TEXT NOT NULL,
+            payment_date TIMESTAMPTZ NOT NULL
+        )`,
+	}
+	for _, q := range setup {
+		if _, err := db.Exec(ctx, q); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Cleanup(func() { db.Exec(context.Background(), `DROP TABLE IF EXISTS pg_temp.payments`) })
+
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		t.Fatal(err)
+	}
+	payments := []struct {
+		amount float64
+		status string
+		at     time.Time
+	}{
+		{100000, "Lunas", time.Date(2026, time.January, 10, 23, 30, 0, 0, jakarta)},
+		{75000, "Tertunda", time.Date(2026, time.January, 11, 0, 30, 0, 0, jakarta)},
+		{50000, "Lunas", time.Date(2026, time.January, 12, 6, 0, 0, 0, jakarta)},
+		{20000, "Terlambat", time.Date(2026, time.January, 13, 0, 0, 0, 0, jakarta)},
+	}
+	for _, p := range payments {
+		if _, err := db.Exec(ctx, `INSERT INTO payments (amount, status, payment_date) VALUES ($1, $2, $3)`, p.amount, p.status, p.at); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	store := NewPostgresAlertStore(db)
+	from := time.Date(2026, time.January, 10, 0, 0, 0, 0, jakarta)
+	got, err := store.GetDailyActivity(ctx, from, from.AddDate(0, 0, 3), jakarta)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []struct {
+		day       int
+		revenue   float64
+		unsettled int64
+	}{
+		{10, 100000, 0},
+		{11, 0, 1},
+		{12, 50000, 0},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("GetDailyActivity() = %+v, want %d hari", got, len(want))
+	}
+	for i, w := range want {
+		if got[i].Date.Day() != w.day || got[i].Revenue != w.revenue || got[i].Unsettled != w.unsettled {
+			t.Errorf("hari ke-%d = %+v, want tanggal %d, pendapatan %v, belum lunas %d", i, got[i], w.day, w.revenue, w.unsettled)
+		}
+	}
+}
@@ -0,0 +1,249 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"login-api/internal/events"
+	"login-api/internal/model"
+	"login-api/internal/storage"
+	"sort"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/rs/zerolog/log"
+)
+
+type PostgresCustomerStore struct {
+	DB *DB
+}
+
+func NewPostgresCustomerStore(db *DB) *PostgresCustomerStore {
+	return &PostgresCustomerStore{DB: db}
+}
+
+// NormalizeCustomerName menghasilkan kunci pembanding nama pelanggan: huruf kecil
+// dengan spasi berlebih dihapus. Harus selaras dengan ekspresi pada migrasi 0003.
+func NormalizeCustomerName(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// ListCustomers mengambil semua pelanggan beserta total pembayarannya.
+func (s *PostgresCustomerStore) ListCustomers(ctx context.Context) ([]model.CustomerWithTotals, error) {
+	query := `
+        SELECT
+            c.id, c.name, c.email, c.phone, c.created_at, c.updated_at,
+            COUNT(p.id) AS payment_count,
+            COALESCE(SUM(CASE WHEN p.status = 'Lunas' THEN p.amount ELSE 0 END), 0) AS total_paid,
+            COALESCE(SUM(CASE WHEN p.status = 'Tertunda' THEN p.amount ELSE 0 END), 0) AS total_outstanding
+        FROM customers c
+        LEFT JOIN payments p ON p.customer_id = c.id
+        GROUP BY c.id
+        ORDER BY total_paid DESC, c.name;
+    `
+	rows, err := s.DB.Query(ctx, query)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Gagal menjalankan query untuk mengambil pelanggan")
+		return nil, err
+	}
+	defer rows.Close()
+
+	var customers []model.CustomerWithTotals
+	for rows.Next() {
+		var c model.CustomerWithTotals
+		if err := rows.Scan(&c.ID, &c.Name, &c.Email, &c.Phone, &c.CreatedAt, &c.UpdatedAt,
+			&c.PaymentCount, &c.TotalPaid, &c.TotalOutstanding); err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("Gagal memindai baris pelanggan")
+			return nil, err
+		}
+		customers = append(customers, c)
+	}
+
+	return customers, rows.Err()
+}
+
+// GetCustomerByID mengambil satu pelanggan berdasarkan ID.
+func (s *PostgresCustomerStore) GetCustomerByID(ctx context.Context, id int) (model.Customer, error) {
+	query := `SELECT id, name, email, phone, created_at, updated_at FROM customers WHERE id = $1`
+
+	c, err := scanCustomer(s.DB.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.Customer{}, storage.ErrNotFound
+		}
+		log.Ctx(ctx).Error().Err(err).Int("customer_id", id).Msg("Gagal mengambil data pelanggan")
+		return model.Customer{}, err
+	}
+
+	return c, nil
+}
+
+// CreateCustomer menyimpan pelanggan baru.
+func (s *PostgresCustomerStore) CreateCustomer(ctx context.Context, c model.Customer) (model.Customer, error) {
+	query := `
+        INSERT INTO customers (name, normalized_name, email, phone)
+        VALUES ($1, $2, $3, $4)
+        RETURNING id, name, email, phone, created_at, updated_at
+    `
+	created, err := scanCustomer(s.DB.QueryRow(ctx, query,
+		c.Name, NormalizeCustomerName(c.Name), c.Email, c.Phone))
+	if err != nil {
+		if isUniqueViolation(err) {
+			return model.Customer{}, storage.ErrDuplicate
+		}
+		return model.Customer{}, fmt.Errorf("kesalahan saat menyimpan pelanggan ke database: %w", err)
+	}
+
+	return created, nil
+}
+
+// UpdateCustomer memperbarui data pelanggan. Nama pada pembayaran yang tertaut
+// ikut diperbarui agar customer_name tetap konsisten. Event payment.updated untuk
+// setiap pembayaran yang berubah dan customer.updated ditulis ke outbox dalam
+// transaksi yang sama.
+func (s *PostgresCustomerStore) UpdateCustomer(ctx context.Context, c model.Customer) (model.Customer, error) {
+	tx, err := s.DB.Begin(ctx)
+	if err != nil {
+		return model.Customer{}, fmt.Errorf("kesalahan saat memulai transaksi pelanggan: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+        UPDATE customers
+        SET name = $1, normalized_name = $2, email = $3, phone = $4, updated_at = NOW()
+        WHERE id = $5
+        RETURNING id, name, email, phone, created_at, updated_at
+    `
+	updated, err := scanCustomer(tx.QueryRow(ctx, query,
+		c.Name, NormalizeCustomerName(c.Name), c.Email, c.Phone, c.ID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.Customer{}, storage.ErrNotFound
+		}
+		if isUniqueViolation(err) {
+			return model.Customer{}, storage.ErrDuplicate
+		}
+		return model.Customer{}, fmt.Errorf("kesalahan saat memperbarui pelanggan di database: %w", err)
+	}
+
+	rows, err := tx.Query(ctx, `
+        UPDATE payments SET customer_name = $1
+        WHERE customer_id = $2 AND customer_name <> $1
+        RETURNING id, customer_id, customer_name, amount, status, payment_date, due_date, reconciliation_status`,
+		updated.Name, updated.ID)
+	if err != nil {
+		return model.Customer{}, fmt.Errorf("kesalahan saat memperbarui nama pelanggan pada pembayaran: %w", err)
+	}
+	renamed, err := scanPayments(rows)
+	rows.Close()
+	if err != nil {
+		return model.Customer{}, fmt.Errorf("kesalahan saat memperbarui nama pelanggan pada pembayaran: %w", err)
+	}
+	for _, p := range renamed {
+		if err := writeOutbox(ctx, tx, events.PaymentUpdated, p); err != nil {
+			return model.Customer{}, err
+		}
+	}
+	if err := writeOutbox(ctx, tx, events.CustomerUpdated, updated); err != nil {
+		return model.Customer{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return model.Customer{}, fmt.Errorf("kesalahan saat memperbarui pelanggan di database: %w", err)
+	}
+
+	return updated, nil
+}
+
+// MergeCustomers memindahkan semua pembayaran dan langganan milik sourceIDs ke
+// targetID lalu menghapus pelanggan sumber, dalam satu transaksi. Event
+// payment.updated untuk setiap pembayaran yang dipindahkan dan customer.merged
+// ditulis ke outbox dalam transaksi yang sama.
+func (s *PostgresCustomerStore) MergeCustomers(ctx context.Context, targetID int, sourceIDs []int) (model.Customer, error) {
+	tx, err := s.DB.Begin(ctx)
+	if err != nil {
+		return model.Customer{}, fmt.Errorf("kesalahan saat memulai transaksi penggabungan: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	target, err := scanCustomer(tx.QueryRow(ctx,
+		`SELECT id, name, email, phone, created_at, updated_at FROM customers WHERE id = $1 FOR UPDATE`, targetID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.Customer{}, storage.ErrNotFound
+		}
+		return model.Customer{}, fmt.Errorf("kesalahan saat mengambil pelanggan tujuan: %w", err)
+	}
+
+	sources := make(map[int]struct{}, len(sourceIDs))
+	for _, id := range sourceIDs {
+		if id != targetID {
+			sources[id] = struct{}{}
+		}
+	}
+
+	rows, err := tx.Query(ctx, `
+        UPDATE payments SET customer_id = $1, customer_name = $2
+        WHERE customer_id = ANY($3) AND customer_id <> $1
+        RETURNING id, customer_id, customer_name, amount, status, payment_date, due_date, reconciliation_status`,
+		targetID, target.Name, sourceIDs)
+	if err != nil {
+		return model.Customer{}, fmt.Errorf("kesalahan saat memindahkan pembayaran pelanggan: %w", err)
+	}
+	moved, err := scanPayments(rows)
+	rows.Close()
+	if err != nil {
+		return model.Customer{}, fmt.Errorf("kesalahan saat memindahkan pembayaran pelanggan: %w", err)
+	}
+	for _, p := range moved {
+		if err := writeOutbox(ctx, tx, events.PaymentUpdated, p); err != nil {
+			return model.Customer{}, err
+		}
+	}
+
+	if _, err := tx.Exec(ctx,
+		`UPDATE subscriptions SET customer_id = $1 WHERE customer_id = ANY($2) AND customer_id <> $1`,
+		targetID, sourceIDs); err != nil {
+		return model.Customer{}, fmt.Errorf("kesalahan saat memindahkan langganan pelanggan: %w", err)
+	}
+
+	tag, err := tx.Exec(ctx, `DELETE FROM customers WHERE id = ANY($1) AND id <> $2`, sourceIDs, targetID)
+	if err != nil {
+		return model.Customer{}, fmt.Errorf("kesalahan saat menghapus pelanggan duplikat: %w", err)
+	}
+	if tag.RowsAffected() != int64(len(sources)) {
+		// Salah satu ID sumber tidak ada; batalkan seluruh penggabungan.
+		return model.Customer{}, storage.ErrNotFound
+	}
+
+	merged := make([]int, 0, len(sources))
+	for id := range sources {
+		merged = append(merged, id)
+	}
+	sort.Ints(merged)
+	if err := writeOutbox(ctx, tx, events.CustomerMerged, struct {
+		model.Customer
+		MergedCustomerIDs []int `json:"merged_customer_ids"`
+	}{target, merged}); err != nil {
+		return model.Customer{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return model.Customer{}, fmt.Errorf("kesalahan saat menggabungkan pelanggan: %w", err)
+	}
+
+	return target, nil
+}
+
+func scanCustomer(row pgx.Row) (model.Customer, error) {
+	var c model.Customer
+	err := row.Scan(&c.ID, &c.Name, &c.Email, &c.Phone, &c.CreatedAt, &c.UpdatedAt)
+	return c, err
+}
+
+// isUniqueViolation memeriksa apakah error berasal dari pelanggaran constraint UNIQUE.
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
@@ -0,0 +1,148 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"login-api/internal/storage"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/puddle/v2"
+)
+
+// DB membungkus pgxpool.Pool untuk dipakai store. Setiap query, termasuk query
+// di dalam transaksi, dibatasi QueryTimeout di samping tenggat ctx pemanggil.
+// Error dinormalisasi: tenggat yang terlampaui dapat dikenali dengan
+// context.DeadlineExceeded dan database yang tidak dapat dihubungi dengan
+// storage.ErrUnavailable.
+type DB struct {
+	*pgxpool.Pool
+	// QueryTimeout nol atau negatif berarti query hanya dibatasi ctx pemanggil.
+	QueryTimeout time.Duration
+}
+
+func NewDB(pool *pgxpool.Pool, queryTimeout time.Duration) *DB {
+	return &DB{Pool: pool, QueryTimeout: queryTimeout}
+}
+
+func (db *DB) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	ctx, cancel := withTimeout(ctx, db.QueryTimeout)
+	defer cancel()
+	tag, err := db.Pool.Exec(ctx, sql, args...)
+	return tag, normalizeError(err)
+}
+
+func (db *DB) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	ctx, cancel := withTimeout(ctx, db.QueryTimeout)
+	rows, err := db.Pool.Query(ctx, sql, args...)
+	if err != nil {
+		cancel()
+		return nil, normalizeError(err)
+	}
+	return &timeoutRows{Rows: rows, cancel: cancel}, nil
+}
+
+func (db *DB) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	ctx, cancel := withTimeout(ctx, db.QueryTimeout)
+	return &timeoutRow{Row: db.Pool.QueryRow(ctx, sql, args...), cancel: cancel}
+}
+
+// Begin memulai transaksi yang query-nya juga dibatasi QueryTimeout.
+func (db *DB) Begin(ctx context.Context) (pgx.Tx, error) {
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, normalizeError(err)
+	}
+	return &timeoutTx{Tx: tx, timeout: db.QueryTimeout}, nil
+}
+
+type timeoutTx struct {
+	pgx.Tx
+	timeout time.Duration
+}
+
+func (tx *timeoutTx) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	ctx, cancel := withTimeout(ctx, tx.timeout)
+	defer cancel()
+	tag, err := tx.Tx.Exec(ctx, sql, args...)
+	return tag, normalizeError(err)
+}
+
+func (tx *timeoutTx) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	ctx, cancel := withTimeout(ctx, tx.timeout)
+	rows, err := tx.Tx.Query(ctx, sql, args...)
+	if err != nil {
+		cancel()
+		return nil, normalizeError(err)
+	}
+	return &timeoutRows{Rows: rows, cancel: cancel}, nil
+}
+
+func (tx *timeoutTx) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	ctx, cancel := withTimeout(ctx, tx.timeout)
+	return &timeoutRow{Row: tx.Tx.QueryRow(ctx, sql, args...), cancel: cancel}
+}
+
+func (tx *timeoutTx) CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error) {
+	ctx, cancel := withTimeout(ctx, tx.timeout)
+	defer cancel()
+	n, err := tx.Tx.CopyFrom(ctx, tableName, columnNames, rowSrc)
+	return n, normalizeError(err)
+}
+
+func (tx *timeoutTx) Commit(ctx context.Context) error {
+	return normalizeError(tx.Tx.Commit(ctx))
+}
+
+// timeoutRows melepas tenggat query saat rows ditutup.
+type timeoutRows struct {
+	pgx.Rows
+	cancel context.CancelFunc
+}
+
+func (r *timeoutRows) Close() {
+	r.Rows.Close()
+	r.cancel()
+}
+
+func (r *timeoutRows) Err() error {
+	return normalizeError(r.Rows.Err())
+}
+
+// timeoutRow melepas tenggat query setelah Scan.
+type timeoutRow struct {
+	pgx.Row
+	cancel context.CancelFunc
+}
+
+func (r *timeoutRow) Scan(dest ...interface{}) error {
+	defer r.cancel()
+	return normalizeError(r.Row.Scan(dest...))
+}
+
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// normalizeError menandai error koneksi dengan storage.ErrUnavailable dan
+// timeout dari pgconn dengan context.DeadlineExceeded. Error lain, termasuk
+// pgx.ErrNoRows, dikembalikan apa adanya.
+func normalizeError(err error) error {
+	var connectErr *pgconn.ConnectError
+	switch {
+	case err == nil, errors.Is(err, context.DeadlineExceeded), errors.Is(err, context.Canceled):
+		return err
+	case errors.As(err, &connectErr), errors.Is(err, puddle.ErrClosedPool):
+		return fmt.Errorf("%w: %w", storage.ErrUnavailable, err)
+	case pgconn.Timeout(err):
+		return fmt.Errorf("%w: %w", context.DeadlineExceeded, err)
+	default:
+		return err
+	}
+}
@@ -0,0 +1,197 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"login-api/internal/events"
+	"login-api/internal/model"
+	"login-api/internal/storage"
+	"math"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog/log"
+)
+
+type PostgresGatewayEventStore struct {
+	DB *DB
+}
+
+func NewPostgresGatewayEventStore(db *DB) *PostgresGatewayEventStore {
+	return &PostgresGatewayEventStore{DB: db}
+}
+
+// GatewayTransition adalah perubahan yang diminta oleh sebuah event. TargetStatus
+// kosong berarti jenis event tidak dikenali; Amount nil berarti jumlah tidak diperiksa.
+type GatewayTransition struct {
+	TargetStatus string
+	Amount       *float64
+}
+
+// GatewayEventResult adalah hasil pemrosesan sebuah event.
+type GatewayEventResult struct {
+	Event     model.GatewayEvent
+	Duplicate bool
+}
+
+const gatewayEventColumns = `id, provider_event_id, event_type, payment_id, payload, status, message, received_at, processed_at`
+
+// RecordEvent menyimpan event baru lalu menerapkannya ke pembayaran dalam satu transaksi.
+// Jika provider_event_id sudah pernah diterima, event tidak diproses ulang dan
+// Duplicate bernilai true.
+func (s *PostgresGatewayEventStore) RecordEvent(ctx context.Context, ev model.GatewayEvent, t GatewayTransition) (GatewayEventResult, error) {
+	tx, err := s.DB.Begin(ctx)
+	if err != nil {
+		return GatewayEventResult{}, fmt.Errorf("kesalahan saat memulai transaksi event gateway: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	stored, err := scanGatewayEvent(tx.QueryRow(ctx, `
+        INSERT INTO gateway_events (provider_event_id, event_type, payment_id, payload)
+        VALUES ($1, $2, $3, $4)
+        ON CONFLICT (provider_event_id) DO NOTHING
+        RETURNING `+gatewayEventColumns,
+		ev.ProviderEventID, ev.EventType, ev.PaymentID, ev.Payload))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return GatewayEventResult{Duplicate: true}, nil
+		}
+		return GatewayEventResult{}, fmt.Errorf("kesalahan saat menyimpan event gateway: %w", err)
+	}
+
+	result, err := applyGatewayEvent(ctx, tx, stored, t)
+	if err != nil {
+		return GatewayEventResult{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return GatewayEventResult{}, fmt.Errorf("kesalahan saat menyimpan event gateway: %w", err)
+	}
+	return result, nil
+}
+
+// ReplayEvent menerapkan ulang event yang sudah tersimpan, misalnya setelah
+// pembayaran yang dirujuk dibuat belakangan atau setelah perbaikan bug.
+func (s *PostgresGatewayEventStore) ReplayEvent(ctx context.Context, id int, t GatewayTransition) (GatewayEventResult, error) {
+	tx, err := s.DB.Begin(ctx)
+	if err != nil {
+		return GatewayEventResult{}, fmt.Errorf("kesalahan saat memulai transaksi replay event: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	stored, err := scanGatewayEvent(tx.QueryRow(ctx,
+		`SELECT `+gatewayEventColumns+` FROM gateway_events WHERE id = $1 FOR UPDATE`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return GatewayEventResult{}, storage.ErrNotFound
+		}
+		return GatewayEventResult{}, fmt.Errorf("kesalahan saat mengambil event gateway: %w", err)
+	}
+
+	result, err := applyGatewayEvent(ctx, tx, stored, t)
+	if err != nil {
+		return GatewayEventResult{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return GatewayEventResult{}, fmt.Errorf("kesalahan saat menyimpan hasil replay event: %w", err)
+	}
+	return result, nil
+}
+
+// applyGatewayEvent mengunci pembayaran, memeriksa transisi status, lalu mencatat hasilnya pada event.
+func applyGatewayEvent(ctx context.Context, tx pgx.Tx, ev model.GatewayEvent, t GatewayTransition) (GatewayEventResult, error) {
+	status, message := model.GatewayEventProcessed, ""
+
+	var (
+		current string
+		amount  float64
+	)
+	err := tx.QueryRow(ctx, `SELECT status, amount FROM payments WHERE id = $1 FOR UPDATE`, ev.PaymentID).Scan(&current, &amount)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		status, message = model.GatewayEventFailed, "pembayaran tidak ditemukan"
+	case err != nil:
+		return GatewayEventResult{}, fmt.Errorf("kesalahan saat mengambil pembayaran %d: %w", ev.PaymentID, err)
+	case t.TargetStatus == "":
+		status, message = model.GatewayEventIgnored, "jenis event tidak dikenali"
+	case current == t.TargetStatus:
+		status, message = model.GatewayEventIgnored, "status pembayaran sudah "+current
+	case !model.CanTransitionPayment(current, t.TargetStatus):
+		status, message = model.GatewayEventIgnored, fmt.Sprintf("perubahan status dari %s ke %s tidak diizinkan", current, t.TargetStatus)
+	case t.TargetStatus == model.PaymentStatusPaid && t.Amount != nil && math.Abs(*t.Amount-amount) >= 0.005:
+		status, message = model.GatewayEventFailed, fmt.Sprintf("jumlah event %.2f tidak sesuai dengan jumlah pembayaran %.2f", *t.Amount, amount)
+	default:
+		var p model.Payment
+		err := tx.QueryRow(ctx, `
+            UPDATE payments SET status = $1 WHERE id = $2
+            RETURNING id, customer_id, customer_name, amount, status, payment_date, due_date, reconciliation_status`,
+			t.TargetStatus, ev.PaymentID,
+		).Scan(&p.ID, &p.CustomerID, &p.CustomerName, &p.Amount, &p.Status, &p.PaymentDate, &p.DueDate, &p.ReconciliationStatus)
+		if err != nil {
+			return GatewayEventResult{}, fmt.Errorf("kesalahan saat memperbarui status pembayaran %d: %w", ev.PaymentID, err)
+		}
+		eventType := events.PaymentUpdated
+		if p.Status == model.PaymentStatusRefunded {
+			eventType = events.PaymentRefunded
+		}
+		if err := writeOutbox(ctx, tx, eventType, p); err != nil {
+			return GatewayEventResult{}, err
+		}
+		message = fmt.Sprintf("status pembayaran diubah dari %s ke %s", current, t.TargetStatus)
+	}
+
+	updated, err := scanGatewayEvent(tx.QueryRow(ctx, `
+        UPDATE gateway_events
+        SET status = $1, message = $2, processed_at = NOW()
+        WHERE id = $3
+        RETURNING `+gatewayEventColumns, status, message, ev.ID))
+	if err != nil {
+		return GatewayEventResult{}, fmt.Errorf("kesalahan saat memperbarui event gateway: %w", err)
+	}
+	return GatewayEventResult{Event: updated}, nil
+}
+
+// ListEvents mengambil event gateway terbaru, paling banyak limit baris.
+func (s *PostgresGatewayEventStore) ListEvents(ctx context.Context, limit int) ([]model.GatewayEvent, error) {
+	rows, err := s.DB.Query(ctx,
+		`SELECT `+gatewayEventColumns+` FROM gateway_events ORDER BY received_at DESC, id DESC LIMIT $1`, limit)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Gagal menjalankan query untuk mengambil event gateway")
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []model.GatewayEvent
+	for rows.Next() {
+		ev, err := scanGatewayEvent(rows)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("Gagal memindai baris event gateway")
+			return nil, err
+		}
+		events = append(events, ev)
+	}
+
+	return events, rows.Err()
+}
+
+// GetEvent mengambil satu event gateway berdasarkan ID.
+func (s *PostgresGatewayEventStore) GetEvent(ctx context.Context, id int) (model.GatewayEvent, error) {
+	ev, err := scanGatewayEvent(s.DB.QueryRow(ctx,
+		`SELECT `+gatewayEventColumns+` FROM gateway_events WHERE id = $1`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.GatewayEvent{}, storage.ErrNotFound
+		}
+		log.Ctx(ctx).Error().Err(err).Int("event_id", id).Msg("Gagal mengambil event gateway")
+		return model.GatewayEvent{}, err
+	}
+	return ev, nil
+}
+
+func scanGatewayEvent(row pgx.Row) (model.GatewayEvent, error) {
+	var ev model.GatewayEvent
+	err := row.Scan(&ev.ID, &ev.ProviderEventID, &ev.EventType, &ev.PaymentID, &ev.Payload,
+		&ev.Status, &ev.Message, &ev.ReceivedAt, &ev.ProcessedAt)
+	return ev, err
+}
@@ -0,0 +1,103 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"login-api/internal/model"
+	"login-api/internal/storage"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog/log"
+)
+
+type PostgresInvoiceStore struct {
+	DB     *DB
+	Prefix string
+	// Location menentukan tahun pada nomor faktur dan zona waktu IssuedAt.
+	Location *time.Location
+}
+
+func NewPostgresInvoiceStore(db *DB, prefix string, loc *time.Location) *PostgresInvoiceStore {
+	return &PostgresInvoiceStore{DB: db, Prefix: prefix, Location: loc}
+}
+
+// GetOrCreateInvoice mengembalikan faktur milik sebuah pembayaran, atau menerbitkan
+// faktur baru dengan nomor urut berikutnya milik organisasi jika belum ada.
+func (s *PostgresInvoiceStore) GetOrCreateInvoice(ctx context.Context, orgID string, paymentID int) (model.Invoice, error) {
+	tx, err := s.DB.Begin(ctx)
+	if err != nil {
+		return model.Invoice{}, fmt.Errorf("kesalahan saat memulai transaksi faktur: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Mengunci baris pembayaran menyerialkan unduhan pertama yang bersamaan: yang
+	// kedua menunggu sampai faktur dari yang pertama tersimpan lalu membacanya,
+	// alih-alih melanggar constraint UNIQUE pada invoices.payment_id.
+	var lockedID int
+	err = tx.QueryRow(ctx, `SELECT id FROM payments WHERE id = $1 FOR UPDATE`, paymentID).Scan(&lockedID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.Invoice{}, storage.ErrNotFound
+		}
+		return model.Invoice{}, fmt.Errorf("kesalahan saat mengunci pembayaran untuk faktur: %w", err)
+	}
+
+	invoice, err := scanInvoice(tx.QueryRow(ctx,
+		`SELECT id, organization_id, payment_id, invoice_number, issued_at
+         FROM invoices
+         WHERE payment_id = $1`, paymentID))
+	if err == nil {
+		invoice.IssuedAt = invoice.IssuedAt.In(s.Location)
+		return invoice, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		log.Ctx(ctx).Error().Err(err).Int("payment_id", paymentID).Msg("Gagal mengambil data faktur")
+		return model.Invoice{}, err
+	}
+
+	// Upsert ini mengunci baris urutan milik organisasi sampai transaksi selesai,
+	// sehingga penerbitan paralel tetap menghasilkan nomor yang berurutan.
+	var seq int64
+	err = tx.QueryRow(ctx,
+		`INSERT INTO invoice_sequences (organization_id, last_value)
+         VALUES ($1, 1)
+         ON CONFLICT (organization_id)
+         DO UPDATE SET last_value = invoice_sequences.last_value + 1
+         RETURNING last_value`, orgID).Scan(&seq)
+	if err != nil {
+		return model.Invoice{}, fmt.Errorf("kesalahan saat mengambil nomor urut faktur: %w", err)
+	}
+
+	issuedAt := time.Now().In(s.Location)
+	number := FormatInvoiceNumber(s.Prefix, issuedAt, seq)
+
+	invoice, err = scanInvoice(tx.QueryRow(ctx,
+		`INSERT INTO invoices (organization_id, payment_id, invoice_number, issued_at)
+         VALUES ($1, $2, $3, $4)
+         RETURNING id, organization_id, payment_id, invoice_number, issued_at`,
+		orgID, paymentID, number, issuedAt))
+	if err != nil {
+		return model.Invoice{}, fmt.Errorf("kesalahan saat menyimpan faktur ke database: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return model.Invoice{}, fmt.Errorf("kesalahan saat menyimpan faktur ke database: %w", err)
+	}
+
+	invoice.IssuedAt = invoice.IssuedAt.In(s.Location)
+	return invoice, nil
+}
+
+// FormatInvoiceNumber menyusun nomor faktur, contoh: "INV/2025/000042". Tahun
+// diambil dari issuedAt apa adanya, sehingga pemanggil menentukan zona waktunya.
+func FormatInvoiceNumber(prefix string, issuedAt time.Time, seq int64) string {
+	return fmt.Sprintf("%s/%d/%06d", prefix, issuedAt.Year(), seq)
+}
+
+func scanInvoice(row pgx.Row) (model.Invoice, error) {
+	var inv model.Invoice
+	err := row.Scan(&inv.ID, &inv.OrganizationID, &inv.PaymentID, &inv.InvoiceNumber, &inv.IssuedAt)
+	return inv, err
+}
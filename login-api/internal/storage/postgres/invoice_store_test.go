@@ -0,0 +1,36 @@
+package postgres
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatInvoiceNumber(t *testing.T) {
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 31 Desember 2025 pukul 20.00 UTC sudah 1 Januari 2026 di Jakarta.
+	newYear := time.Date(2025, time.December, 31, 20, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		prefix   string
+		issuedAt time.Time
+		seq      int64
+		want     string
+	}{
+		{"nomor pertama", "INV", time.Date(2025, time.March, 5, 9, 0, 0, 0, jakarta), 1, "INV/2025/000001"},
+		{"lebih dari enam digit", "INV", time.Date(2025, time.March, 5, 9, 0, 0, 0, jakarta), 1234567, "INV/2025/1234567"},
+		{"prefix organisasi", "ACME-INV", time.Date(2026, time.July, 1, 0, 0, 0, 0, jakarta), 42, "ACME-INV/2026/000042"},
+		{"tahun menurut UTC", "INV", newYear, 7, "INV/2025/000007"},
+		{"tahun menurut zona waktu organisasi", "INV", newYear.In(jakarta), 7, "INV/2026/000007"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatInvoiceNumber(tt.prefix, tt.issuedAt, tt.seq); got != tt.want {
+				t.Errorf("FormatInvoiceNumber() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,14 @@
+-- Skema dasar yang sudah diasumsikan oleh PostgresUserStore dan PostgresPaymentStore.
+CREATE TABLE IF NOT EXISTS users (
+    id            SERIAL PRIMARY KEY,
+    email         TEXT NOT NULL UNIQUE,
+    password_hash TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS payments (
+    id            SERIAL PRIMARY KEY,
+    customer_name TEXT NOT NULL,
+    amount        NUMERIC(15, 2) NOT NULL,
+    status        TEXT NOT NULL,
+    payment_date  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,15 @@
+-- Nomor faktur berurutan per organisasi. Baris pada invoice_sequences dikunci
+-- selama transaksi penerbitan sehingga nomor tidak pernah ganda atau melompat.
+CREATE TABLE invoice_sequences (
+    organization_id TEXT PRIMARY KEY,
+    last_value      BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE TABLE invoices (
+    id              SERIAL PRIMARY KEY,
+    organization_id TEXT NOT NULL,
+    payment_id      INTEGER NOT NULL UNIQUE REFERENCES payments (id) ON DELETE CASCADE,
+    invoice_number  TEXT NOT NULL,
+    issued_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (organization_id, invoice_number)
+);
@@ -0,0 +1,34 @@
+CREATE TABLE customers (
+    id              SERIAL PRIMARY KEY,
+    name            TEXT NOT NULL,
+    normalized_name TEXT NOT NULL UNIQUE,
+    email           TEXT NOT NULL DEFAULT '',
+    phone           TEXT NOT NULL DEFAULT '',
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE payments ADD COLUMN customer_id INTEGER REFERENCES customers (id);
+CREATE INDEX payments_customer_id_idx ON payments (customer_id);
+
+-- De-duplikasi customer_name yang sudah ada: nama yang hanya berbeda huruf besar/kecil
+-- atau spasi digabung menjadi satu pelanggan, memakai ejaan yang paling sering muncul.
+-- Duplikat yang ejaannya berbeda (salah ketik) digabung manual lewat endpoint merge.
+INSERT INTO customers (name, normalized_name)
+SELECT DISTINCT ON (normalized_name) name, normalized_name
+FROM (
+    SELECT
+        btrim(regexp_replace(customer_name, '\s+', ' ', 'g')) AS name,
+        lower(btrim(regexp_replace(customer_name, '\s+', ' ', 'g'))) AS normalized_name,
+        COUNT(*) AS occurrences
+    FROM payments
+    WHERE btrim(customer_name) <> ''
+    GROUP BY 1, 2
+) spellings
+ORDER BY normalized_name, occurrences DESC, name;
+
+UPDATE payments p
+SET customer_id = c.id,
+    customer_name = c.name
+FROM customers c
+WHERE c.normalized_name = lower(btrim(regexp_replace(p.customer_name, '\s+', ' ', 'g')));
@@ -0,0 +1,22 @@
+CREATE TABLE subscriptions (
+    id              SERIAL PRIMARY KEY,
+    customer_id     INTEGER NOT NULL REFERENCES customers (id),
+    description     TEXT NOT NULL DEFAULT '',
+    amount          NUMERIC(15, 2) NOT NULL CHECK (amount > 0),
+    interval_unit   TEXT NOT NULL CHECK (interval_unit IN ('day', 'week', 'month')),
+    interval_count  INTEGER NOT NULL CHECK (interval_count > 0),
+    start_date      DATE NOT NULL,
+    -- generated_count adalah jumlah tagihan yang sudah dibuat; next_due_date selalu
+    -- sama dengan kemunculan ke-generated_count dari jadwal yang berawal di start_date.
+    generated_count INTEGER NOT NULL DEFAULT 0,
+    next_due_date   DATE NOT NULL,
+    status          TEXT NOT NULL DEFAULT 'Aktif',
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX subscriptions_due_idx ON subscriptions (next_due_date) WHERE status = 'Aktif';
+
+ALTER TABLE payments ADD COLUMN subscription_id INTEGER REFERENCES subscriptions (id);
+CREATE UNIQUE INDEX payments_subscription_period_idx ON payments (subscription_id, payment_date)
+    WHERE subscription_id IS NOT NULL;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS payment_reminders;
+UPDATE payments SET status = 'Tertunda' WHERE status = 'Terlambat';
+ALTER TABLE payments DROP COLUMN IF EXISTS due_date;
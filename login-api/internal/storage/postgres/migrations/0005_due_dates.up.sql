@@ -0,0 +1,17 @@
+ALTER TABLE payments ADD COLUMN due_date DATE;
+
+-- Pembayaran tertunda yang sudah ada dianggap jatuh tempo pada tanggal pembayarannya.
+UPDATE payments SET due_date = payment_date::date WHERE status = 'Tertunda';
+UPDATE payments SET due_date = payment_date::date WHERE subscription_id IS NOT NULL AND due_date IS NULL;
+
+CREATE INDEX payments_unpaid_due_date_idx ON payments (due_date)
+    WHERE status IN ('Tertunda', 'Terlambat');
+
+-- Satu baris per pengingat yang terkirim; offset_days relatif terhadap due_date
+-- (negatif berarti sebelum jatuh tempo).
+CREATE TABLE payment_reminders (
+    payment_id  INTEGER NOT NULL REFERENCES payments (id) ON DELETE CASCADE,
+    offset_days INTEGER NOT NULL,
+    sent_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (payment_id, offset_days)
+);
@@ -0,0 +1,16 @@
+-- Event webhook mentah dari penyedia pembayaran. provider_event_id unik sehingga
+-- pengiriman ulang dari penyedia tidak diproses dua kali; payload disimpan utuh
+-- agar event dapat diputar ulang (replay).
+CREATE TABLE gateway_events (
+    id                SERIAL PRIMARY KEY,
+    provider_event_id TEXT NOT NULL UNIQUE,
+    event_type        TEXT NOT NULL,
+    payment_id        INTEGER NOT NULL,
+    payload           JSONB NOT NULL,
+    status            TEXT NOT NULL DEFAULT 'diterima',
+    message           TEXT NOT NULL DEFAULT '',
+    received_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    processed_at      TIMESTAMPTZ
+);
+
+CREATE INDEX gateway_events_payment_id_idx ON gateway_events (payment_id);
@@ -0,0 +1,33 @@
+CREATE TABLE webhook_subscriptions (
+    id              SERIAL PRIMARY KEY,
+    organization_id TEXT NOT NULL,
+    url             TEXT NOT NULL,
+    secret          TEXT NOT NULL,
+    -- Daftar jenis event, atau '*' untuk semua event.
+    event_types     TEXT[] NOT NULL,
+    active          BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX webhook_subscriptions_org_idx ON webhook_subscriptions (organization_id) WHERE active;
+
+-- Log pengiriman. Setiap percobaan ulang memperbarui baris yang sama; pengiriman
+-- ulang manual membuat baris baru dengan event_id yang sama.
+CREATE TABLE webhook_deliveries (
+    id               SERIAL PRIMARY KEY,
+    subscription_id  INTEGER NOT NULL REFERENCES webhook_subscriptions (id) ON DELETE CASCADE,
+    event_id         TEXT NOT NULL,
+    event_type       TEXT NOT NULL,
+    payload          JSONB NOT NULL,
+    status           TEXT NOT NULL DEFAULT 'tertunda',
+    attempts         INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_status_code INTEGER,
+    last_error       TEXT NOT NULL DEFAULT '',
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    delivered_at     TIMESTAMPTZ
+);
+
+CREATE INDEX webhook_deliveries_due_idx ON webhook_deliveries (next_attempt_at) WHERE status = 'tertunda';
+CREATE INDEX webhook_deliveries_subscription_idx ON webhook_deliveries (subscription_id, created_at DESC);
@@ -0,0 +1,17 @@
+-- Outbox transaksional: event ditulis dalam transaksi yang sama dengan perubahan
+-- datanya, lalu diterbitkan oleh relay. Baris yang sudah terbit disimpan sementara
+-- sebagai jejak audit dan dibersihkan secara berkala.
+CREATE TABLE outbox_events (
+    id              BIGSERIAL PRIMARY KEY,
+    event_id        TEXT NOT NULL UNIQUE,
+    event_type      TEXT NOT NULL,
+    payload         JSONB NOT NULL,
+    occurred_at     TIMESTAMPTZ NOT NULL,
+    attempts        INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_error      TEXT NOT NULL DEFAULT '',
+    published_at    TIMESTAMPTZ
+);
+
+CREATE INDEX outbox_events_pending_idx ON outbox_events (next_attempt_at, id) WHERE published_at IS NULL;
+CREATE INDEX outbox_events_published_idx ON outbox_events (published_at) WHERE published_at IS NOT NULL;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS bank_statement_lines;
+DROP TABLE IF EXISTS bank_statements;
+ALTER TABLE payments DROP COLUMN IF EXISTS reconciliation_status;
@@ -0,0 +1,36 @@
+ALTER TABLE payments ADD COLUMN reconciliation_status TEXT NOT NULL DEFAULT 'belum';
+
+-- Berkas mutasi rekening yang diunggah. checksum mencegah berkas yang sama
+-- diimpor dua kali.
+CREATE TABLE bank_statements (
+    id              SERIAL PRIMARY KEY,
+    organization_id TEXT NOT NULL,
+    file_name       TEXT NOT NULL,
+    format          TEXT NOT NULL,
+    account_id      TEXT NOT NULL DEFAULT '',
+    checksum        TEXT NOT NULL,
+    uploaded_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (organization_id, checksum)
+);
+
+-- Status baris: belum_cocok, perlu_tinjauan (lebih dari satu kandidat),
+-- cocok, atau diabaikan (dana keluar).
+CREATE TABLE bank_statement_lines (
+    id                    SERIAL PRIMARY KEY,
+    statement_id          INTEGER NOT NULL REFERENCES bank_statements (id) ON DELETE CASCADE,
+    line_no               INTEGER NOT NULL,
+    booking_date          DATE NOT NULL,
+    amount                NUMERIC(15, 2) NOT NULL,
+    reference             TEXT NOT NULL DEFAULT '',
+    description           TEXT NOT NULL DEFAULT '',
+    status                TEXT NOT NULL,
+    payment_id            INTEGER REFERENCES payments (id) ON DELETE SET NULL,
+    match_method          TEXT NOT NULL DEFAULT '',
+    candidate_payment_ids INTEGER[] NOT NULL DEFAULT '{}',
+    matched_at            TIMESTAMPTZ,
+    UNIQUE (statement_id, line_no)
+);
+
+-- Satu pembayaran hanya boleh dicocokkan dengan satu baris mutasi.
+CREATE UNIQUE INDEX bank_statement_lines_payment_idx ON bank_statement_lines (payment_id) WHERE payment_id IS NOT NULL;
+CREATE INDEX bank_statement_lines_review_idx ON bank_statement_lines (statement_id) WHERE status = 'perlu_tinjauan';
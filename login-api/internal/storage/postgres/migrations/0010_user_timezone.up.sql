@@ -0,0 +1,3 @@
+-- Zona waktu IANA pilihan pengguna untuk agregasi dashboard. Kosong berarti
+-- memakai zona waktu bawaan aplikasi.
+ALTER TABLE users ADD COLUMN timezone TEXT NOT NULL DEFAULT '';
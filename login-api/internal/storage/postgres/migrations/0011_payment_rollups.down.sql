@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS payments_payment_date_idx;
+DROP TRIGGER IF EXISTS payments_rollup ON payments;
+DROP FUNCTION IF EXISTS payments_rollup_trigger();
+DROP FUNCTION IF EXISTS payment_rollup_add(TIMESTAMPTZ, TEXT, BIGINT, NUMERIC);
+DROP TABLE IF EXISTS payment_hourly_rollups;
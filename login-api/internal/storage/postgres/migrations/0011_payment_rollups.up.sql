@@ -0,0 +1,44 @@
+-- Agregat pembayaran per jam dan status untuk dashboard. Tabel dipelihara secara
+-- inkremental oleh trigger pada payments sehingga ringkasan dan grafik cukup
+-- membaca baris rollup, bukan memindai seluruh tabel payments. Granularitas jam
+-- memungkinkan agregasi harian pada zona waktu mana pun yang selisihnya jam penuh.
+CREATE TABLE payment_hourly_rollups (
+    hour          TIMESTAMPTZ NOT NULL,
+    status        TEXT NOT NULL,
+    payment_count BIGINT NOT NULL DEFAULT 0,
+    amount        NUMERIC(18, 2) NOT NULL DEFAULT 0,
+    PRIMARY KEY (hour, status)
+);
+
+CREATE FUNCTION payment_rollup_add(p_time TIMESTAMPTZ, p_status TEXT, p_count BIGINT, p_amount NUMERIC)
+RETURNS VOID AS $$
+    INSERT INTO payment_hourly_rollups (hour, status, payment_count, amount)
+    VALUES (date_trunc('hour', p_time AT TIME ZONE 'UTC') AT TIME ZONE 'UTC', p_status, p_count, p_amount)
+    ON CONFLICT (hour, status) DO UPDATE
+    SET payment_count = payment_hourly_rollups.payment_count + EXCLUDED.payment_count,
+        amount = payment_hourly_rollups.amount + EXCLUDED.amount;
+$$ LANGUAGE sql;
+
+CREATE FUNCTION payments_rollup_trigger() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        PERFORM payment_rollup_add(OLD.payment_date, OLD.status, -1, -OLD.amount);
+    END IF;
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        PERFORM payment_rollup_add(NEW.payment_date, NEW.status, 1, NEW.amount);
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER payments_rollup
+AFTER INSERT OR DELETE OR UPDATE OF payment_date, status, amount ON payments
+FOR EACH ROW EXECUTE FUNCTION payments_rollup_trigger();
+
+INSERT INTO payment_hourly_rollups (hour, status, payment_count, amount)
+SELECT date_trunc('hour', payment_date AT TIME ZONE 'UTC') AT TIME ZONE 'UTC', status, COUNT(*), SUM(amount)
+FROM payments
+GROUP BY 1, 2;
+
+-- Sisa rentang yang tidak jatuh pada jam penuh dibaca langsung dari payments.
+CREATE INDEX IF NOT EXISTS payments_payment_date_idx ON payments (payment_date);
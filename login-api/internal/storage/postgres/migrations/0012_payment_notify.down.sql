@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS payments_notify ON payments;
+DROP FUNCTION IF EXISTS payments_notify_trigger();
@@ -0,0 +1,29 @@
+-- Memberi tahu listener aplikasi setiap kali pembayaran berubah. Notifikasi hanya
+-- terkirim setelah transaksi commit dan memuat nilai lama serta baru agar
+-- penerima dapat menghitung perubahan ringkasan tanpa query tambahan.
+CREATE FUNCTION payments_notify_trigger() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        PERFORM pg_notify('payment_changes', json_build_object(
+            'op', 'delete', 'id', OLD.id,
+            'old_status', OLD.status, 'old_amount', OLD.amount, 'old_payment_date', OLD.payment_date
+        )::text);
+    ELSIF TG_OP = 'UPDATE' THEN
+        PERFORM pg_notify('payment_changes', json_build_object(
+            'op', 'update', 'id', NEW.id,
+            'status', NEW.status, 'amount', NEW.amount, 'payment_date', NEW.payment_date,
+            'old_status', OLD.status, 'old_amount', OLD.amount, 'old_payment_date', OLD.payment_date
+        )::text);
+    ELSE
+        PERFORM pg_notify('payment_changes', json_build_object(
+            'op', 'insert', 'id', NEW.id,
+            'status', NEW.status, 'amount', NEW.amount, 'payment_date', NEW.payment_date
+        )::text);
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER payments_notify
+AFTER INSERT OR DELETE OR UPDATE OF payment_date, status, amount ON payments
+FOR EACH ROW EXECUTE FUNCTION payments_notify_trigger();
@@ -0,0 +1,17 @@
+-- Peringatan anomali aktivitas pembayaran. fingerprint mengidentifikasi kejadian
+-- yang sama (misalnya penurunan pendapatan pada satu tanggal) sehingga detektor
+-- yang berjalan berulang kali atau di beberapa instance tidak membuat duplikat.
+CREATE TABLE alerts (
+    id              SERIAL PRIMARY KEY,
+    organization_id TEXT NOT NULL,
+    kind            TEXT NOT NULL,
+    severity        TEXT NOT NULL,
+    fingerprint     TEXT NOT NULL,
+    message         TEXT NOT NULL,
+    details         JSONB NOT NULL DEFAULT '{}',
+    detected_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    acknowledged_at TIMESTAMPTZ,
+    UNIQUE (organization_id, fingerprint)
+);
+
+CREATE INDEX alerts_open_idx ON alerts (organization_id, detected_at DESC) WHERE acknowledged_at IS NULL;
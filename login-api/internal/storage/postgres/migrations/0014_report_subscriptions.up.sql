@@ -0,0 +1,18 @@
+-- Langganan laporan dashboard terjadwal per pengguna. schedule adalah ekspresi
+-- cron lima kolom yang dievaluasi pada timezone; next_run_at dihitung ulang
+-- setiap kali laporan dikirim sehingga penjadwal cukup mencari baris yang jatuh tempo.
+CREATE TABLE report_subscriptions (
+    id          SERIAL PRIMARY KEY,
+    user_id     INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    period      TEXT NOT NULL CHECK (period IN ('day', 'week')),
+    schedule    TEXT NOT NULL,
+    timezone    TEXT NOT NULL,
+    active      BOOLEAN NOT NULL DEFAULT TRUE,
+    next_run_at TIMESTAMPTZ NOT NULL,
+    last_run_at TIMESTAMPTZ,
+    last_error  TEXT NOT NULL DEFAULT '',
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX report_subscriptions_due_idx ON report_subscriptions (next_run_at) WHERE active;
@@ -0,0 +1,5 @@
+ALTER TABLE users
+    DROP COLUMN IF EXISTS role,
+    DROP COLUMN IF EXISTS disabled_at,
+    DROP COLUMN IF EXISTS sessions_revoked_at,
+    DROP COLUMN IF EXISTS created_at;
@@ -0,0 +1,8 @@
+-- Data administrasi akun. disabled_at terisi berarti akun tidak dapat login.
+-- Refresh token yang diterbitkan sebelum sessions_revoked_at ditolak, sehingga
+-- sesi berakhir paling lambat saat access token yang tersisa kedaluwarsa.
+ALTER TABLE users
+    ADD COLUMN role                TEXT NOT NULL DEFAULT 'user',
+    ADD COLUMN disabled_at         TIMESTAMPTZ,
+    ADD COLUMN sessions_revoked_at TIMESTAMPTZ,
+    ADD COLUMN created_at          TIMESTAMPTZ NOT NULL DEFAULT NOW();
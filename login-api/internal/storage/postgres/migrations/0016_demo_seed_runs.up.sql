@@ -0,0 +1,10 @@
+-- Catatan pengisian data contoh oleh loginctl seed. Pengisian dengan opsi yang
+-- sama dikenali dari fingerprint sehingga menjalankannya ulang tidak menambah data.
+CREATE TABLE demo_seed_runs (
+    fingerprint TEXT PRIMARY KEY,
+    options     JSONB NOT NULL,
+    customers   INTEGER NOT NULL,
+    payments    INTEGER NOT NULL,
+    users       INTEGER NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
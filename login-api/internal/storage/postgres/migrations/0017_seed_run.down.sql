@@ -0,0 +1,3 @@
+ALTER TABLE demo_seed_runs DROP COLUMN IF EXISTS seed_run;
+DROP INDEX IF EXISTS payments_seed_run_idx;
+ALTER TABLE payments DROP COLUMN IF EXISTS seed_run;
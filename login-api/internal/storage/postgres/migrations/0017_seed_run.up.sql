@@ -0,0 +1,7 @@
+-- Pembayaran contoh ditandai dengan kunci pengisiannya (opsi selain tanggal
+-- akhir) sehingga loginctl seed mengganti pembayaran contoh sebelumnya alih-alih
+-- menambahkannya lagi ketika tanggal akhir bergeser.
+ALTER TABLE payments ADD COLUMN seed_run TEXT;
+CREATE INDEX payments_seed_run_idx ON payments (seed_run) WHERE seed_run IS NOT NULL;
+
+ALTER TABLE demo_seed_runs ADD COLUMN seed_run TEXT;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS report_subscriptions_due_idx;
+CREATE INDEX report_subscriptions_due_idx ON report_subscriptions (next_run_at) WHERE active;
+
+ALTER TABLE report_subscriptions DROP COLUMN IF EXISTS retry_at;
+ALTER TABLE report_subscriptions DROP COLUMN IF EXISTS attempts;
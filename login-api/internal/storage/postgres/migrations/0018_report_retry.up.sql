@@ -0,0 +1,8 @@
+-- Pengiriman laporan yang gagal dicoba ulang tanpa menggeser next_run_at, agar
+-- laporan tetap mencakup periode jadwal semula. attempts menghitung kegagalan
+-- beruntun untuk jadwal saat ini dan retry_at adalah waktu percobaan berikutnya.
+ALTER TABLE report_subscriptions ADD COLUMN attempts INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE report_subscriptions ADD COLUMN retry_at TIMESTAMPTZ;
+
+DROP INDEX IF EXISTS report_subscriptions_due_idx;
+CREATE INDEX report_subscriptions_due_idx ON report_subscriptions (COALESCE(retry_at, next_run_at)) WHERE active;
@@ -0,0 +1,3 @@
+ALTER TABLE invoices DROP CONSTRAINT invoices_payment_id_fkey;
+ALTER TABLE invoices ADD CONSTRAINT invoices_payment_id_fkey
+    FOREIGN KEY (payment_id) REFERENCES payments (id) ON DELETE CASCADE;
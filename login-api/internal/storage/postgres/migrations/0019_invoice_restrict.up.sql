@@ -0,0 +1,5 @@
+-- Nomor faktur yang sudah terbit tidak boleh hilang bersama pembayarannya.
+-- Pembayaran yang sudah memiliki faktur tidak dapat dihapus.
+ALTER TABLE invoices DROP CONSTRAINT invoices_payment_id_fkey;
+ALTER TABLE invoices ADD CONSTRAINT invoices_payment_id_fkey
+    FOREIGN KEY (payment_id) REFERENCES payments (id) ON DELETE RESTRICT;
@@ -0,0 +1,8 @@
+// Package migrations menyematkan berkas migrasi SQL ke dalam binary. Setiap
+// migrasi terdiri dari NNNN_nama.up.sql dan NNNN_nama.down.sql.
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS
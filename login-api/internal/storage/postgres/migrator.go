@@ -0,0 +1,320 @@
+package postgres
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"login-api/internal/storage/postgres/migrations"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog/log"
+)
+
+// Migration adalah satu migrasi skema beserta SQL naik dan turunnya. Checksum
+// adalah SHA-256 dari SQL naik, dicatat saat migrasi diterapkan agar perubahan
+// berkas migrasi yang sudah berjalan dapat terdeteksi.
+type Migration struct {
+	Version  int
+	Name     string
+	Up       string
+	Down     string
+	Checksum string
+}
+
+// MigrationStatus adalah status sebuah migrasi pada database. AppliedAt nil
+// berarti migrasi belum diterapkan.
+type MigrationStatus struct {
+	Migration
+	AppliedAt *time.Time
+	// ChecksumMismatch bernilai true bila berkas migrasi berubah setelah diterapkan.
+	ChecksumMismatch bool
+}
+
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// LoadMigrations membaca migrasi dari fsys, terurut berdasarkan versi. Setiap
+// versi wajib memiliki berkas up; berkas down boleh tidak ada.
+func LoadMigrations(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("kesalahan saat membaca direktori migrasi: %w", err)
+	}
+
+	byVersion := map[int]*Migration{}
+	seen := map[string]string{}
+	for _, e := range entries {
+		match := migrationFilePattern.FindStringSubmatch(e.Name())
+		if match == nil {
+			continue
+		}
+		version, _ := strconv.Atoi(match[1])
+		content, err := fs.ReadFile(fsys, e.Name())
+		if err != nil {
+			return nil, fmt.Errorf("kesalahan saat membaca migrasi %s: %w", e.Name(), err)
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		} else if m.Name != match[2] {
+			return nil, fmt.Errorf("versi migrasi %d dipakai oleh %s dan %s", version, m.Name, match[2])
+		}
+		// "1_init.up.sql" dan "0001_init.up.sql" sama-sama versi 1.
+		key := fmt.Sprintf("%d.%s", version, match[3])
+		if other, ok := seen[key]; ok {
+			return nil, fmt.Errorf("berkas migrasi %s dan %s memiliki versi yang sama", other, e.Name())
+		}
+		seen[key] = e.Name()
+		if match[3] == "up" {
+			m.Up = string(content)
+			sum := sha256.Sum256(content)
+			m.Checksum = hex.EncodeToString(sum[:])
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	list := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Checksum == "" {
+			return nil, fmt.Errorf("migrasi %04d_%s tidak memiliki berkas up", m.Version, m.Name)
+		}
+		list = append(list, *m)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Version < list[j].Version })
+	return list, nil
+}
+
+// Migrator menerapkan dan membatalkan migrasi skema. Versi yang sudah diterapkan
+// dicatat di tabel schema_migrations. Semua operasi dijalankan di bawah kunci
+// advisory sehingga beberapa instance yang dimulai bersamaan tidak menjalankan
+// migrasi yang sama dua kali.
+type Migrator struct {
+	DB         *pgxpool.Pool
+	Migrations []Migration
+	Lock       *AdvisoryLock
+}
+
+// NewMigrator membuat Migrator untuk migrasi yang disematkan di binary.
+func NewMigrator(db *pgxpool.Pool) (*Migrator, error) {
+	list, err := LoadMigrations(migrations.FS)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{DB: db, Migrations: list, Lock: NewAdvisoryLock(db, MigrationLockKey)}, nil
+}
+
+// appliedMigration adalah baris schema_migrations.
+type appliedMigration struct {
+	Checksum  string
+	AppliedAt time.Time
+}
+
+// Status mengembalikan status setiap migrasi.
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	var statuses []MigrationStatus
+	err := m.Lock.WithLock(ctx, func() error {
+		applied, err := m.applied(ctx)
+		if err != nil {
+			return err
+		}
+		for _, mig := range m.Migrations {
+			st := MigrationStatus{Migration: mig}
+			if a, ok := applied[mig.Version]; ok {
+				appliedAt := a.AppliedAt
+				st.AppliedAt = &appliedAt
+				st.ChecksumMismatch = a.Checksum != mig.Checksum
+			}
+			statuses = append(statuses, st)
+		}
+		return nil
+	})
+	return statuses, err
+}
+
+// Up menerapkan semua migrasi yang belum diterapkan secara berurutan, masing-masing
+// dalam transaksinya sendiri. Up menolak berjalan bila ada migrasi yang berkasnya
+// berubah setelah diterapkan.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var done []Migration
+	err := m.Lock.WithLock(ctx, func() error {
+		applied, err := m.applied(ctx)
+		if err != nil {
+			return err
+		}
+		if err := m.verify(applied); err != nil {
+			return err
+		}
+
+		for _, mig := range m.Migrations {
+			if _, ok := applied[mig.Version]; ok {
+				continue
+			}
+			if err := m.apply(ctx, mig); err != nil {
+				return err
+			}
+			done = append(done, mig)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Down membatalkan steps migrasi terakhir yang sudah diterapkan, dimulai dari
+// versi tertinggi.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var done []Migration
+	err := m.Lock.WithLock(ctx, func() error {
+		applied, err := m.applied(ctx)
+		if err != nil {
+			return err
+		}
+
+		for i := len(m.Migrations) - 1; i >= 0 && len(done) < steps; i-- {
+			mig := m.Migrations[i]
+			if _, ok := applied[mig.Version]; !ok {
+				continue
+			}
+			if mig.Down == "" {
+				return fmt.Errorf("migrasi %04d_%s tidak dapat dibatalkan karena tidak memiliki berkas down", mig.Version, mig.Name)
+			}
+			if err := m.revert(ctx, mig); err != nil {
+				return err
+			}
+			done = append(done, mig)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Baseline mencatat semua migrasi sampai version sebagai sudah diterapkan tanpa
+// menjalankannya. Dipakai sekali untuk database yang skemanya dibuat sebelum
+// schema_migrations ada.
+func (m *Migrator) Baseline(ctx context.Context, version int) ([]Migration, error) {
+	var done []Migration
+	err := m.Lock.WithLock(ctx, func() error {
+		applied, err := m.applied(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, mig := range m.Migrations {
+			if mig.Version > version {
+				break
+			}
+			if _, ok := applied[mig.Version]; ok {
+				continue
+			}
+			if _, err := m.DB.Exec(ctx,
+				`INSERT INTO schema_migrations (version, name, checksum) VALUES ($1, $2, $3)`,
+				mig.Version, mig.Name, mig.Checksum); err != nil {
+				return fmt.Errorf("kesalahan saat mencatat baseline migrasi %04d_%s: %w", mig.Version, mig.Name, err)
+			}
+			done = append(done, mig)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// applied membuat tabel schema_migrations bila belum ada lalu membaca isinya.
+func (m *Migrator) applied(ctx context.Context) (map[int]appliedMigration, error) {
+	_, err := m.DB.Exec(ctx, `
+        CREATE TABLE IF NOT EXISTS schema_migrations (
+            version    INTEGER PRIMARY KEY,
+            name       TEXT NOT NULL,
+            checksum   TEXT NOT NULL,
+            applied_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+        )`)
+	if err != nil {
+		return nil, fmt.Errorf("kesalahan saat membuat tabel schema_migrations: %w", err)
+	}
+
+	rows, err := m.DB.Query(ctx, `SELECT version, checksum, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("kesalahan saat membaca schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := map[int]appliedMigration{}
+	for rows.Next() {
+		var version int
+		var a appliedMigration
+		if err := rows.Scan(&version, &a.Checksum, &a.AppliedAt); err != nil {
+			return nil, fmt.Errorf("kesalahan saat memindai baris schema_migrations: %w", err)
+		}
+		applied[version] = a
+	}
+	return applied, rows.Err()
+}
+
+// verify memastikan migrasi yang sudah diterapkan tidak berubah. Versi di database
+// yang tidak dikenal binary ini hanya dicatat, karena wajar terjadi saat binary
+// lama masih berjalan ketika versi baru di-deploy.
+func (m *Migrator) verify(applied map[int]appliedMigration) error {
+	known := map[int]bool{}
+	for _, mig := range m.Migrations {
+		known[mig.Version] = true
+		if a, ok := applied[mig.Version]; ok && a.Checksum != mig.Checksum {
+			return fmt.Errorf("checksum migrasi %04d_%s berubah setelah diterapkan", mig.Version, mig.Name)
+		}
+	}
+	for version := range applied {
+		if !known[version] {
+			log.Warn().Int("version", version).Msg("Database memiliki migrasi yang tidak dikenal binary ini")
+		}
+	}
+	return nil
+}
+
+func (m *Migrator) apply(ctx context.Context, mig Migration) error {
+	tx, err := m.DB.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("kesalahan saat memulai transaksi migrasi: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, mig.Up); err != nil {
+		return fmt.Errorf("kesalahan saat menerapkan migrasi %04d_%s: %w", mig.Version, mig.Name, err)
+	}
+	if _, err := tx.Exec(ctx,
+		`INSERT INTO schema_migrations (version, name, checksum) VALUES ($1, $2, $3)`,
+		mig.Version, mig.Name, mig.Checksum); err != nil {
+		return fmt.Errorf("kesalahan saat mencatat migrasi %04d_%s: %w", mig.Version, mig.Name, err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("kesalahan saat menerapkan migrasi %04d_%s: %w", mig.Version, mig.Name, err)
+	}
+
+	log.Ctx(ctx).Info().Int("version", mig.Version).Str("name", mig.Name).Msg("Migrasi diterapkan")
+	return nil
+}
+
+func (m *Migrator) revert(ctx context.Context, mig Migration) error {
+	tx, err := m.DB.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("kesalahan saat memulai transaksi migrasi: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, mig.Down); err != nil {
+		return fmt.Errorf("kesalahan saat membatalkan migrasi %04d_%s: %w", mig.Version, mig.Name, err)
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM schema_migrations WHERE version = $1`, mig.Version); err != nil {
+		return fmt.Errorf("kesalahan saat menghapus catatan migrasi %04d_%s: %w", mig.Version, mig.Name, err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("kesalahan saat membatalkan migrasi %04d_%s: %w", mig.Version, mig.Name, err)
+	}
+
+	log.Ctx(ctx).Info().Int("version", mig.Version).Str("name", mig.Name).Msg("Migrasi dibatalkan")
+	return nil
+}
@@ -0,0 +1,115 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"login-api/internal/events"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type PostgresOutboxStore struct {
+	DB *DB
+}
+
+func NewPostgresOutboxStore(db *DB) *PostgresOutboxStore {
+	return &PostgresOutboxStore{DB: db}
+}
+
+// OutboxEntry adalah event di outbox yang menunggu diterbitkan.
+type OutboxEntry struct {
+	ID       int64
+	Event    events.Event
+	Attempts int
+}
+
+// writeOutbox menyimpan event ke outbox memakai transaksi tx, sehingga event hanya
+// ada bila perubahan data yang memicunya ikut tersimpan.
+func writeOutbox(ctx context.Context, tx pgx.Tx, eventType string, data interface{}) error {
+	ev, err := events.New(eventType, data)
+	if err != nil {
+		return fmt.Errorf("kesalahan saat menyusun event %s: %w", eventType, err)
+	}
+
+	_, err = tx.Exec(ctx, `
+        INSERT INTO outbox_events (event_id, event_type, payload, occurred_at)
+        VALUES ($1, $2, $3, $4)`,
+		ev.ID, ev.Type, []byte(ev.Data), ev.OccurredAt)
+	if err != nil {
+		return fmt.Errorf("kesalahan saat menyimpan event %s ke outbox: %w", eventType, err)
+	}
+	return nil
+}
+
+// ClaimPending mengambil paling banyak limit event yang belum terbit dan sudah
+// waktunya dicoba, lalu menunda next_attempt_at selama lease agar relay lain
+// tidak mengambil event yang sama selama penerbitan berlangsung.
+func (s *PostgresOutboxStore) ClaimPending(ctx context.Context, limit int, lease time.Duration) ([]OutboxEntry, error) {
+	rows, err := s.DB.Query(ctx, `
+        WITH claimed AS (
+            UPDATE outbox_events
+            SET next_attempt_at = NOW() + make_interval(secs => $2::float8)
+            WHERE id IN (
+                SELECT id FROM outbox_events
+                WHERE published_at IS NULL AND next_attempt_at <= NOW()
+                ORDER BY id
+                LIMIT $1
+                FOR UPDATE SKIP LOCKED
+            )
+            RETURNING id, event_id, event_type, payload, occurred_at, attempts
+        )
+        SELECT * FROM claimed ORDER BY id`,
+		limit, lease.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("kesalahan saat mengklaim event outbox: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []OutboxEntry
+	for rows.Next() {
+		var e OutboxEntry
+		var payload []byte
+		if err := rows.Scan(&e.ID, &e.Event.ID, &e.Event.Type, &payload, &e.Event.OccurredAt, &e.Attempts); err != nil {
+			return nil, fmt.Errorf("kesalahan saat memindai event outbox: %w", err)
+		}
+		e.Event.Data = payload
+		entries = append(entries, e)
+	}
+
+	return entries, rows.Err()
+}
+
+// MarkPublished menandai event sudah diterbitkan ke semua sink.
+func (s *PostgresOutboxStore) MarkPublished(ctx context.Context, id int64) error {
+	_, err := s.DB.Exec(ctx, `
+        UPDATE outbox_events
+        SET published_at = NOW(), attempts = attempts + 1, last_error = ''
+        WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("kesalahan saat menandai event outbox terbit: %w", err)
+	}
+	return nil
+}
+
+// MarkFailed mencatat percobaan penerbitan yang gagal dan jadwal percobaan berikutnya.
+func (s *PostgresOutboxStore) MarkFailed(ctx context.Context, id int64, errMsg string, nextAttempt time.Time) error {
+	_, err := s.DB.Exec(ctx, `
+        UPDATE outbox_events
+        SET attempts = attempts + 1, last_error = $2, next_attempt_at = $3
+        WHERE id = $1`, id, errMsg, nextAttempt)
+	if err != nil {
+		return fmt.Errorf("kesalahan saat mencatat kegagalan event outbox: %w", err)
+	}
+	return nil
+}
+
+// DeletePublishedBefore menghapus event yang sudah terbit sebelum waktu before.
+func (s *PostgresOutboxStore) DeletePublishedBefore(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := s.DB.Exec(ctx,
+		`DELETE FROM outbox_events WHERE published_at IS NOT NULL AND published_at < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("kesalahan saat membersihkan outbox: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
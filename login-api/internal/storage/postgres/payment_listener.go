@@ -0,0 +1,62 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"login-api/internal/model"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog/log"
+)
+
+// PaymentChangesChannel adalah kanal NOTIFY yang diisi trigger payments_notify.
+const PaymentChangesChannel = "payment_changes"
+
+// PostgresPaymentListener menerima notifikasi perubahan pembayaran melalui
+// LISTEN/NOTIFY pada satu koneksi khusus dari pool.
+type PostgresPaymentListener struct {
+	DB *pgxpool.Pool
+}
+
+func NewPostgresPaymentListener(db *pgxpool.Pool) *PostgresPaymentListener {
+	return &PostgresPaymentListener{DB: db}
+}
+
+// Listen menjalankan LISTEN lalu memanggil handle untuk setiap perubahan
+// pembayaran sampai ctx dibatalkan atau koneksi terputus. ready dipanggil sekali
+// setelah LISTEN aktif; perubahan sebelum titik itu tidak diterima.
+func (l *PostgresPaymentListener) Listen(ctx context.Context, ready func(), handle func(model.PaymentChange)) error {
+	conn, err := l.DB.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("kesalahan saat mengambil koneksi listener: %w", err)
+	}
+	defer func() {
+		// Koneksi dikembalikan ke pool, jadi langganan kanal harus dilepas dulu.
+		// Bila koneksi sudah rusak, Exec gagal dan pool membuangnya saat Release.
+		unlistenCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_, _ = conn.Exec(unlistenCtx, "UNLISTEN "+PaymentChangesChannel)
+		conn.Release()
+	}()
+
+	if _, err := conn.Exec(ctx, "LISTEN "+PaymentChangesChannel); err != nil {
+		return fmt.Errorf("kesalahan saat menjalankan LISTEN: %w", err)
+	}
+	ready()
+
+	for {
+		n, err := conn.Conn().WaitForNotification(ctx)
+		if err != nil {
+			return fmt.Errorf("kesalahan saat menunggu notifikasi pembayaran: %w", err)
+		}
+
+		var change model.PaymentChange
+		if err := json.Unmarshal([]byte(n.Payload), &change); err != nil {
+			log.Ctx(ctx).Error().Err(err).Str("payload", n.Payload).Msg("Gagal membaca notifikasi perubahan pembayaran")
+			continue
+		}
+		handle(change)
+	}
+}
@@ -2,92 +2,318 @@ package postgres
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"login-api/internal/events"
 	"login-api/internal/model"
+	"login-api/internal/storage"
+	"time"
 
-	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5"
 	"github.com/rs/zerolog/log"
 )
 
 type PostgresPaymentStore struct {
-	DB *pgxpool.Pool
+	DB *DB
 }
 
-func NewPostgresPaymentStore(db *pgxpool.Pool) *PostgresPaymentStore {
+func NewPostgresPaymentStore(db *DB) *PostgresPaymentStore {
 	return &PostgresPaymentStore{DB: db}
 }
 
 // GetPayments mengambil semua data pembayaran dari database.
-func (s *PostgresPaymentStore) GetPayments() ([]model.Payment, error) {
-	query := `SELECT id, customer_name, amount, status, payment_date 
+func (s *PostgresPaymentStore) GetPayments(ctx context.Context) ([]model.Payment, error) {
+	query := `SELECT id, customer_id, customer_name, amount, status, payment_date, due_date, reconciliation_status
               FROM payments 
               ORDER BY payment_date DESC`
 
-	rows, err := s.DB.Query(context.Background(), query)
+	rows, err := s.DB.Query(ctx, query)
 	if err != nil {
-		log.Error().Err(err).Msg("Gagal menjalankan query untuk mengambil pembayaran")
+		log.Ctx(ctx).Error().Err(err).Msg("Gagal menjalankan query untuk mengambil pembayaran")
 		return nil, err
 	}
 	defer rows.Close()
 
+	return scanPayments(rows)
+}
+
+// GetPaymentsByCustomer mengambil semua pembayaran milik satu pelanggan.
+func (s *PostgresPaymentStore) GetPaymentsByCustomer(ctx context.Context, customerID int) ([]model.Payment, error) {
+	query := `SELECT id, customer_id, customer_name, amount, status, payment_date, due_date, reconciliation_status
+              FROM payments
+              WHERE customer_id = $1
+              ORDER BY payment_date DESC`
+
+	rows, err := s.DB.Query(ctx, query, customerID)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Int("customer_id", customerID).Msg("Gagal menjalankan query untuk mengambil pembayaran pelanggan")
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanPayments(rows)
+}
+
+func scanPayments(rows pgx.Rows) ([]model.Payment, error) {
 	var payments []model.Payment
 	for rows.Next() {
 		var p model.Payment
-		if err := rows.Scan(&p.ID, &p.CustomerName, &p.Amount, &p.Status, &p.PaymentDate); err != nil {
+		if err := rows.Scan(&p.ID, &p.CustomerID, &p.CustomerName, &p.Amount, &p.Status, &p.PaymentDate, &p.DueDate, &p.ReconciliationStatus); err != nil {
 			log.Error().Err(err).Msg("Gagal memindai baris pembayaran")
 			return nil, err
 		}
 		payments = append(payments, p)
 	}
 
-	return payments, nil
+	return payments, rows.Err()
 }
 
-// GetDashboardSummary menghitung data ringkasan dari tabel payments.
-func (s *PostgresPaymentStore) GetDashboardSummary() (model.DashboardSummary, error) {
-	var summary model.DashboardSummary
-	query := `
-        SELECT 
+// GetPaymentByID mengambil satu data pembayaran berdasarkan ID.
+func (s *PostgresPaymentStore) GetPaymentByID(ctx context.Context, id int) (model.Payment, error) {
+	query := `SELECT id, customer_id, customer_name, amount, status, payment_date, due_date, reconciliation_status
+              FROM payments
+              WHERE id = $1`
+
+	var p model.Payment
+	err := s.DB.QueryRow(ctx, query, id).Scan(&p.ID, &p.CustomerID, &p.CustomerName, &p.Amount, &p.Status, &p.PaymentDate, &p.DueDate, &p.ReconciliationStatus)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.Payment{}, storage.ErrNotFound
+		}
+		log.Ctx(ctx).Error().Err(err).Int("payment_id", id).Msg("Gagal mengambil data pembayaran")
+		return model.Payment{}, err
+	}
+
+	return p, nil
+}
+
+// ListPaymentsBetween mengambil pembayaran dengan payment_date dalam rentang [from, to).
+func (s *PostgresPaymentStore) ListPaymentsBetween(ctx context.Context, from, to time.Time) ([]model.Payment, error) {
+	rows, err := s.DB.Query(ctx, `
+        SELECT id, customer_id, customer_name, amount, status, payment_date, due_date, reconciliation_status
+        FROM payments
+        WHERE payment_date >= $1 AND payment_date < $2
+        ORDER BY payment_date, id`,
+		from, to)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Gagal menjalankan query untuk pembayaran dalam rentang")
+		return nil, err
+	}
+	defer rows.Close()
+
+	var payments []model.Payment
+	for rows.Next() {
+		var p model.Payment
+		if err := rows.Scan(&p.ID, &p.CustomerID, &p.CustomerName, &p.Amount, &p.Status, &p.PaymentDate, &p.DueDate, &p.ReconciliationStatus); err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("Gagal memindai baris pembayaran dalam rentang")
+			return nil, err
+		}
+		payments = append(payments, p)
+	}
+
+	return payments, rows.Err()
+}
+
+// summaryColumns adalah agregat yang membentuk model.DashboardSummary dari relasi
+// berkolom status, payment_count, dan amount.
+const summaryColumns = `
             COALESCE(SUM(CASE WHEN status = 'Lunas' THEN amount ELSE 0 END), 0) as total_revenue,
-            COALESCE(SUM(CASE WHEN status = 'Lunas' THEN 1 ELSE 0 END), 0) as completed_payments,
-            COALESCE(SUM(CASE WHEN status = 'Tertunda' THEN 1 ELSE 0 END), 0) as pending_payments
-        FROM payments;
-    `
-	err := s.DB.QueryRow(context.Background(), query).Scan(
+            COALESCE(SUM(CASE WHEN status = 'Lunas' THEN payment_count ELSE 0 END), 0) as completed_payments,
+            COALESCE(SUM(CASE WHEN status = 'Tertunda' THEN payment_count ELSE 0 END), 0) as pending_payments,
+            COALESCE(SUM(CASE WHEN status = 'Terlambat' THEN payment_count ELSE 0 END), 0) as overdue_payments`
+
+// rolledPayments adalah pembayaran dalam rentang [$1, $2) sebagai relasi berkolom
+// status, payment_count, dan amount. Jam penuh [$3, $4) dibaca dari
+// payment_hourly_rollups; sisa di kedua ujung dibaca langsung dari payments.
+// Parameternya dibentuk oleh rollupArgs.
+const rolledPayments = `(
+            SELECT status, payment_count, amount
+            FROM payment_hourly_rollups
+            WHERE hour >= $3 AND hour < $4
+            UNION ALL
+            SELECT status, 1, amount
+            FROM payments
+            WHERE (payment_date >= $1 AND payment_date < $3)
+               OR (payment_date >= $4 AND payment_date < $2)
+        ) AS p`
+
+// Batas rentang yang dipakai bila from atau to tidak ditentukan.
+var (
+	rollupMinTime = time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC)
+	rollupMaxTime = time.Date(9999, 1, 1, 0, 0, 0, 0, time.UTC)
+)
+
+// rollupArgs membagi rentang [from, to) menjadi parameter rolledPayments: ujung
+// awal, ujung akhir, serta jam penuh pertama dan terakhir di antaranya.
+func rollupArgs(from, to time.Time) []interface{} {
+	fullFrom := from.Truncate(time.Hour)
+	if fullFrom.Before(from) {
+		fullFrom = fullFrom.Add(time.Hour)
+	}
+	if fullFrom.After(to) {
+		fullFrom = to
+	}
+	fullTo := to.Truncate(time.Hour)
+	if fullTo.Before(fullFrom) {
+		fullTo = fullFrom
+	}
+	return []interface{}{from, to, fullFrom, fullTo}
+}
+
+// optionalRange mengganti batas nil dengan batas terluar.
+func optionalRange(from, to *time.Time) (time.Time, time.Time) {
+	f, t := rollupMinTime, rollupMaxTime
+	if from != nil {
+		f = *from
+	}
+	if to != nil {
+		t = *to
+	}
+	return f, t
+}
+
+// GetDashboardSummary menghitung data ringkasan dari rollup pembayaran per jam.
+func (s *PostgresPaymentStore) GetDashboardSummary(ctx context.Context) (model.DashboardSummary, error) {
+	query := `SELECT ` + summaryColumns + ` FROM payment_hourly_rollups;`
+	return s.querySummary(ctx, query)
+}
+
+// GetPeriodSummary menghitung data ringkasan untuk pembayaran dengan payment_date
+// dalam rentang [from, to).
+func (s *PostgresPaymentStore) GetPeriodSummary(ctx context.Context, from, to time.Time) (model.DashboardSummary, error) {
+	query := `SELECT ` + summaryColumns + ` FROM ` + rolledPayments + `;`
+	return s.querySummary(ctx, query, rollupArgs(from, to)...)
+}
+
+func (s *PostgresPaymentStore) querySummary(ctx context.Context, query string, args ...interface{}) (model.DashboardSummary, error) {
+	var summary model.DashboardSummary
+	err := s.DB.QueryRow(ctx, query, args...).Scan(
 		&summary.TotalRevenue,
 		&summary.CompletedPayments,
 		&summary.PendingPayments,
+		&summary.OverduePayments,
 	)
 
 	if err != nil {
-		log.Error().Err(err).Msg("Gagal menjalankan query untuk ringkasan dashboard")
+		log.Ctx(ctx).Error().Err(err).Msg("Gagal menjalankan query untuk ringkasan dashboard")
 		return model.DashboardSummary{}, err
 	}
 
 	return summary, nil
 }
 
-// GetChartData mengambil data pembayaran yang diagregasi per hari untuk 7 hari terakhir.
-func (s *PostgresPaymentStore) GetChartData() ([]model.ChartData, error) {
+// GetStatusBreakdown menghitung jumlah dan nilai pembayaran per status. from dan
+// to bersifat opsional dan membatasi payment_date pada rentang [from, to).
+func (s *PostgresPaymentStore) GetStatusBreakdown(ctx context.Context, from, to *time.Time) ([]model.StatusBreakdown, error) {
+	query := `
+        SELECT status, SUM(payment_count), COALESCE(SUM(amount), 0)
+        FROM ` + rolledPayments + `
+        GROUP BY status
+        HAVING SUM(payment_count) > 0
+        ORDER BY SUM(payment_count) DESC, status;
+    `
+	rows, err := s.DB.Query(ctx, query, rollupArgs(optionalRange(from, to))...)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Gagal menjalankan query untuk rincian status pembayaran")
+		return nil, err
+	}
+	defer rows.Close()
+
+	var breakdown []model.StatusBreakdown
+	for rows.Next() {
+		var b model.StatusBreakdown
+		if err := rows.Scan(&b.Status, &b.Count, &b.Amount); err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("Gagal memindai baris rincian status pembayaran")
+			return nil, err
+		}
+		breakdown = append(breakdown, b)
+	}
+
+	return breakdown, rows.Err()
+}
+
+// GetTopCustomers mengambil limit pelanggan dengan pendapatan lunas terbesar. from
+// dan to bersifat opsional dan membatasi payment_date pada rentang [from, to).
+func (s *PostgresPaymentStore) GetTopCustomers(ctx context.Context, from, to *time.Time, limit int) ([]model.CustomerBreakdown, error) {
+	query := `
+        SELECT customer_id, customer_name, SUM(amount), COUNT(*)
+        FROM payments
+        WHERE status = 'Lunas'
+          AND ($1::timestamptz IS NULL OR payment_date >= $1)
+          AND ($2::timestamptz IS NULL OR payment_date < $2)
+        GROUP BY customer_id, customer_name
+        ORDER BY SUM(amount) DESC, customer_name
+        LIMIT $3;
+    `
+	rows, err := s.DB.Query(ctx, query, from, to, limit)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Gagal menjalankan query untuk pelanggan teratas")
+		return nil, err
+	}
+	defer rows.Close()
+
+	var breakdown []model.CustomerBreakdown
+	for rows.Next() {
+		var b model.CustomerBreakdown
+		if err := rows.Scan(&b.CustomerID, &b.CustomerName, &b.Revenue, &b.PaymentCount); err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("Gagal memindai baris pelanggan teratas")
+			return nil, err
+		}
+		breakdown = append(breakdown, b)
+	}
+
+	return breakdown, rows.Err()
+}
+
+// chartLabelFormats adalah format TO_CHAR label grafik untuk setiap interval.
+// Label minggu adalah tanggal Senin awal minggu tersebut.
+var chartLabelFormats = map[string]string{
+	model.ChartIntervalHour:  `YYYY-MM-DD"T"HH24:00`,
+	model.ChartIntervalDay:   `YYYY-MM-DD`,
+	model.ChartIntervalWeek:  `YYYY-MM-DD`,
+	model.ChartIntervalMonth: `YYYY-MM`,
+}
+
+// GetChartData mengambil pendapatan lunas yang diagregasi per interval dalam
+// rentang r. Interval tanpa pembayaran tetap muncul dengan nilai 0. Deret dibentuk
+// dari waktu lokal r.Location, lalu setiap batasnya dikonversi kembali ke waktu
+// absolut sehingga hari pergantian DST tetap benar. Bila batas interval jatuh
+// pada jam penuh, nilai dibaca dari payment_hourly_rollups; zona waktu dengan
+// selisih bukan jam penuh membaca payments langsung.
+func (s *PostgresPaymentStore) GetChartData(ctx context.Context, r model.ChartRange) ([]model.ChartData, error) {
+	labelFormat, ok := chartLabelFormats[r.Interval]
+	if !ok {
+		return nil, fmt.Errorf("interval grafik %q tidak dikenali", r.Interval)
+	}
+
+	join := `payment_hourly_rollups ON hour >= (bucket AT TIME ZONE $5)
+                    AND hour < ((bucket + ('1 ' || $3)::interval) AT TIME ZONE $5)`
+	if !hourAligned(r.From) || !hourAligned(r.To) {
+		join = `payments ON payment_date >= (bucket AT TIME ZONE $5)
+                    AND payment_date < ((bucket + ('1 ' || $3)::interval) AT TIME ZONE $5)`
+	}
+
 	query := `
         SELECT 
-            TO_CHAR(date_series, 'YYYY-MM-DD') as label,
+            TO_CHAR(bucket, $4) as label,
             COALESCE(SUM(amount), 0) as value
         FROM 
             generate_series(
-                CURRENT_DATE - INTERVAL '6 days', 
-                CURRENT_DATE, 
-                '1 day'
-            ) AS date_series
+                date_trunc($3, $1::timestamp),
+                date_trunc($3, $2::timestamp),
+                ('1 ' || $3)::interval
+            ) AS bucket
         LEFT JOIN 
-            payments ON DATE(payment_date) = date_series AND status = 'Lunas'
+            ` + join + `
+                    AND status = 'Lunas'
         GROUP BY 
-            date_series
+            bucket
         ORDER BY 
-            date_series;
+            bucket;
     `
-	rows, err := s.DB.Query(context.Background(), query)
+	rows, err := s.DB.Query(ctx, query, r.From, r.To, r.Interval, labelFormat, r.Location.String())
 	if err != nil {
-		log.Error().Err(err).Msg("Gagal menjalankan query untuk data grafik")
+		log.Ctx(ctx).Error().Err(err).Msg("Gagal menjalankan query untuk data grafik")
 		return nil, err
 	}
 	defer rows.Close()
@@ -96,10 +322,97 @@ func (s *PostgresPaymentStore) GetChartData() ([]model.ChartData, error) {
 	for rows.Next() {
 		var cd model.ChartData
 		if err := rows.Scan(&cd.Label, &cd.Value); err != nil {
-			log.Error().Err(err).Msg("Gagal memindai baris data grafik")
+			log.Ctx(ctx).Error().Err(err).Msg("Gagal memindai baris data grafik")
 			return nil, err
 		}
 		chartData = append(chartData, cd)
 	}
 	return chartData, nil
-}
\ No newline at end of file
+}
+
+// hourAligned melaporkan apakah selisih zona waktu t terhadap UTC berupa jam penuh.
+func hourAligned(t time.Time) bool {
+	_, offset := t.Zone()
+	return offset%3600 == 0
+}
+
+// MarkOverduePayments mengubah status pembayaran tertunda yang due_date-nya sudah
+// lewat dari tanggal today menjadi Terlambat. Mengembalikan pembayaran yang diubah.
+// Event payment.updated untuk setiap pembayaran ditulis ke outbox dalam transaksi yang sama.
+func (s *PostgresPaymentStore) MarkOverduePayments(ctx context.Context, today time.Time) ([]model.Payment, error) {
+	tx, err := s.DB.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("kesalahan saat memulai transaksi penandaan terlambat: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+        UPDATE payments
+        SET status = 'Terlambat'
+        WHERE status = 'Tertunda' AND due_date < $1::date
+        RETURNING id, customer_id, customer_name, amount, status, payment_date, due_date, reconciliation_status
+    `
+	rows, err := tx.Query(ctx, query, today)
+	if err != nil {
+		return nil, fmt.Errorf("kesalahan saat menandai pembayaran terlambat: %w", err)
+	}
+	marked, err := scanPayments(rows)
+	rows.Close()
+	if err != nil {
+		return nil, fmt.Errorf("kesalahan saat menandai pembayaran terlambat: %w", err)
+	}
+
+	for _, p := range marked {
+		if err := writeOutbox(ctx, tx, events.PaymentUpdated, p); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("kesalahan saat menyimpan penandaan terlambat: %w", err)
+	}
+	return marked, nil
+}
+
+// GetAgingReport mengelompokkan pembayaran belum lunas yang sudah lewat jatuh
+// tempo berdasarkan umur keterlambatannya per tanggal today. Seperti
+// MarkOverduePayments, pembayaran yang jatuh tempo hari ini belum terhitung.
+func (s *PostgresPaymentStore) GetAgingReport(ctx context.Context, today time.Time) (model.AgingReport, error) {
+	report := model.NewAgingReport(today)
+
+	query := `
+        SELECT
+            CASE
+                WHEN $1::date - due_date <= 30 THEN 0
+                WHEN $1::date - due_date <= 60 THEN 1
+                WHEN $1::date - due_date <= 90 THEN 2
+                ELSE 3
+            END AS bucket,
+            COUNT(*),
+            COALESCE(SUM(amount), 0)
+        FROM payments
+        WHERE status IN ('Tertunda', 'Terlambat') AND due_date < $1::date
+        GROUP BY bucket
+    `
+	rows, err := s.DB.Query(ctx, query, today)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Gagal menjalankan query untuk laporan umur piutang")
+		return model.AgingReport{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			idx    int
+			count  int64
+			amount float64
+		)
+		if err := rows.Scan(&idx, &count, &amount); err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("Gagal memindai baris laporan umur piutang")
+			return model.AgingReport{}, err
+		}
+		report.Add(report.Buckets[idx].MinDays, count, amount)
+	}
+
+	return report, rows.Err()
+}
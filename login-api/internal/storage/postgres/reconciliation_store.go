@@ -0,0 +1,434 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"login-api/internal/bankstatement"
+	"login-api/internal/events"
+	"login-api/internal/model"
+	"login-api/internal/storage"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog/log"
+)
+
+type PostgresReconciliationStore struct {
+	DB *DB
+}
+
+func NewPostgresReconciliationStore(db *DB) *PostgresReconciliationStore {
+	return &PostgresReconciliationStore{DB: db}
+}
+
+const bankStatementColumns = `
+    bs.id, bs.file_name, bs.format, bs.account_id, bs.uploaded_at,
+    COUNT(l.id),
+    COUNT(l.id) FILTER (WHERE l.status = 'cocok'),
+    COUNT(l.id) FILTER (WHERE l.status = 'perlu_tinjauan'),
+    COUNT(l.id) FILTER (WHERE l.status = 'belum_cocok')`
+
+const statementLineColumns = `
+    l.id, l.statement_id, l.line_no, l.booking_date, l.amount, l.reference, l.description,
+    l.status, l.payment_id, l.match_method, l.matched_at, l.candidate_payment_ids`
+
+// ImportStatement menyimpan mutasi rekening lalu mencocokkan setiap baris dana
+// masuk dengan pembayaran yang belum direkonsiliasi, yang nominalnya sama dan
+// tanggal pembayaran atau jatuh temponya berjarak paling banyak windowDays hari.
+// Seluruh proses berjalan dalam satu transaksi. storage.ErrDuplicate dikembalikan
+// bila berkas dengan checksum yang sama sudah pernah diimpor.
+func (s *PostgresReconciliationStore) ImportStatement(ctx context.Context, orgID, fileName, checksum string, stmt bankstatement.Statement, windowDays int) (model.BankStatement, error) {
+	tx, err := s.DB.Begin(ctx)
+	if err != nil {
+		return model.BankStatement{}, fmt.Errorf("kesalahan saat memulai transaksi impor mutasi: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var statementID int
+	err = tx.QueryRow(ctx, `
+        INSERT INTO bank_statements (organization_id, file_name, format, account_id, checksum)
+        VALUES ($1, $2, $3, $4, $5)
+        ON CONFLICT (organization_id, checksum) DO NOTHING
+        RETURNING id`,
+		orgID, fileName, stmt.Format, stmt.AccountID, checksum,
+	).Scan(&statementID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.BankStatement{}, storage.ErrDuplicate
+		}
+		return model.BankStatement{}, fmt.Errorf("kesalahan saat menyimpan mutasi rekening: %w", err)
+	}
+
+	for i, line := range stmt.Lines {
+		status, method := model.StatementLineIgnored, ""
+		var (
+			paymentID    *int
+			candidateIDs = []int{}
+		)
+
+		if line.Amount > 0 {
+			candidates, err := lockCandidates(ctx, tx, orgID, line, windowDays)
+			if err != nil {
+				return model.BankStatement{}, err
+			}
+
+			decision := bankstatement.Decide(line, candidates)
+			switch {
+			case decision.Match != nil:
+				status, method = model.StatementLineMatched, model.MatchMethodAuto
+				paymentID = &decision.Match.PaymentID
+				if err := setReconciliationStatus(ctx, tx, decision.Match.PaymentID, model.ReconciliationReconciled); err != nil {
+					return model.BankStatement{}, err
+				}
+			case len(decision.Review) > 0:
+				status = model.StatementLineReview
+				for _, c := range decision.Review {
+					candidateIDs = append(candidateIDs, c.PaymentID)
+				}
+			default:
+				status = model.StatementLineUnmatched
+			}
+		}
+
+		_, err := tx.Exec(ctx, `
+            INSERT INTO bank_statement_lines (statement_id, line_no, booking_date, amount, reference, description,
+                                              status, payment_id, match_method, candidate_payment_ids, matched_at)
+            VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, CASE WHEN $8::int IS NULL THEN NULL ELSE NOW() END)`,
+			statementID, i+1, line.BookingDate, line.Amount, line.Reference, line.Description,
+			status, paymentID, method, candidateIDs)
+		if err != nil {
+			return model.BankStatement{}, fmt.Errorf("kesalahan saat menyimpan baris mutasi %d: %w", i+1, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return model.BankStatement{}, fmt.Errorf("kesalahan saat menyimpan impor mutasi: %w", err)
+	}
+
+	return s.GetStatement(ctx, orgID, statementID)
+}
+
+// lockCandidates mengambil dan mengunci pembayaran yang dapat dicocokkan dengan line.
+func lockCandidates(ctx context.Context, tx pgx.Tx, orgID string, line bankstatement.Line, windowDays int) ([]bankstatement.Candidate, error) {
+	rows, err := tx.Query(ctx, `
+        SELECT p.id, p.payment_date, p.customer_name, COALESCE(i.invoice_number, '')
+        FROM payments p
+        LEFT JOIN invoices i ON i.payment_id = p.id AND i.organization_id = $4
+        WHERE p.reconciliation_status = 'belum'
+          AND p.status <> 'Dibatalkan'
+          AND ABS(p.amount - $1) < 0.005
+          AND (p.payment_date::date BETWEEN $2::date - $3::int AND $2::date + $3::int
+               OR p.due_date BETWEEN $2::date - $3::int AND $2::date + $3::int)
+        ORDER BY p.payment_date, p.id
+        FOR UPDATE OF p`,
+		line.Amount, line.BookingDate, windowDays, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("kesalahan saat mencari kandidat pembayaran: %w", err)
+	}
+	defer rows.Close()
+
+	var candidates []bankstatement.Candidate
+	for rows.Next() {
+		var c bankstatement.Candidate
+		if err := rows.Scan(&c.PaymentID, &c.PaymentDate, &c.CustomerName, &c.InvoiceNumber); err != nil {
+			return nil, fmt.Errorf("kesalahan saat memindai kandidat pembayaran: %w", err)
+		}
+		candidates = append(candidates, c)
+	}
+	return candidates, rows.Err()
+}
+
+// setReconciliationStatus memperbarui status rekonsiliasi pembayaran dan menulis
+// event payment.updated ke outbox dalam transaksi tx.
+func setReconciliationStatus(ctx context.Context, tx pgx.Tx, paymentID int, status string) error {
+	var p model.Payment
+	err := tx.QueryRow(ctx, `
+        UPDATE payments SET reconciliation_status = $1 WHERE id = $2
+        RETURNING id, customer_id, customer_name, amount, status, payment_date, due_date, reconciliation_status`,
+		status, paymentID,
+	).Scan(&p.ID, &p.CustomerID, &p.CustomerName, &p.Amount, &p.Status, &p.PaymentDate, &p.DueDate, &p.ReconciliationStatus)
+	if err != nil {
+		return fmt.Errorf("kesalahan saat memperbarui status rekonsiliasi pembayaran %d: %w", paymentID, err)
+	}
+	return writeOutbox(ctx, tx, events.PaymentUpdated, p)
+}
+
+// ListStatements mengambil semua mutasi rekening milik organisasi, terbaru lebih dulu.
+func (s *PostgresReconciliationStore) ListStatements(ctx context.Context, orgID string) ([]model.BankStatement, error) {
+	rows, err := s.DB.Query(ctx, `
+        SELECT `+bankStatementColumns+`
+        FROM bank_statements bs
+        LEFT JOIN bank_statement_lines l ON l.statement_id = bs.id
+        WHERE bs.organization_id = $1
+        GROUP BY bs.id
+        ORDER BY bs.uploaded_at DESC, bs.id DESC`, orgID)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Gagal menjalankan query untuk mengambil mutasi rekening")
+		return nil, err
+	}
+	defer rows.Close()
+
+	var statements []model.BankStatement
+	for rows.Next() {
+		st, err := scanBankStatement(rows)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("Gagal memindai baris mutasi rekening")
+			return nil, err
+		}
+		statements = append(statements, st)
+	}
+
+	return statements, rows.Err()
+}
+
+// GetStatement mengambil satu mutasi rekening beserta ringkasan pencocokannya.
+func (s *PostgresReconciliationStore) GetStatement(ctx context.Context, orgID string, id int) (model.BankStatement, error) {
+	st, err := scanBankStatement(s.DB.QueryRow(ctx, `
+        SELECT `+bankStatementColumns+`
+        FROM bank_statements bs
+        LEFT JOIN bank_statement_lines l ON l.statement_id = bs.id
+        WHERE bs.id = $1 AND bs.organization_id = $2
+        GROUP BY bs.id`, id, orgID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.BankStatement{}, storage.ErrNotFound
+		}
+		log.Ctx(ctx).Error().Err(err).Int("statement_id", id).Msg("Gagal mengambil mutasi rekening")
+		return model.BankStatement{}, err
+	}
+	return st, nil
+}
+
+// ListLines mengambil baris sebuah mutasi rekening, opsional disaring berdasarkan status.
+func (s *PostgresReconciliationStore) ListLines(ctx context.Context, orgID string, statementID int, status string) ([]model.BankStatementLine, error) {
+	return s.queryLines(ctx, `
+        SELECT `+statementLineColumns+`
+        FROM bank_statement_lines l
+        JOIN bank_statements bs ON bs.id = l.statement_id
+        WHERE bs.organization_id = $1 AND l.statement_id = $2 AND ($3 = '' OR l.status = $3)
+        ORDER BY l.line_no`, orgID, statementID, status)
+}
+
+// ListReviewQueue mengambil semua baris yang menunggu dipilih pembayarannya secara manual.
+func (s *PostgresReconciliationStore) ListReviewQueue(ctx context.Context, orgID string) ([]model.BankStatementLine, error) {
+	return s.queryLines(ctx, `
+        SELECT `+statementLineColumns+`
+        FROM bank_statement_lines l
+        JOIN bank_statements bs ON bs.id = l.statement_id
+        WHERE bs.organization_id = $1 AND l.status = 'perlu_tinjauan'
+        ORDER BY l.booking_date, l.id`, orgID)
+}
+
+// queryLines menjalankan query baris mutasi lalu melengkapi baris yang perlu
+// ditinjau dengan data pembayaran kandidatnya.
+func (s *PostgresReconciliationStore) queryLines(ctx context.Context, query string, args ...interface{}) ([]model.BankStatementLine, error) {
+	rows, err := s.DB.Query(ctx, query, args...)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Gagal menjalankan query untuk mengambil baris mutasi")
+		return nil, err
+	}
+
+	var (
+		lines        []model.BankStatementLine
+		candidateIDs [][]int
+		allIDs       []int
+	)
+	for rows.Next() {
+		var l model.BankStatementLine
+		var ids []int
+		if err := rows.Scan(&l.ID, &l.StatementID, &l.LineNo, &l.BookingDate, &l.Amount, &l.Reference, &l.Description,
+			&l.Status, &l.PaymentID, &l.MatchMethod, &l.MatchedAt, &ids); err != nil {
+			rows.Close()
+			log.Ctx(ctx).Error().Err(err).Msg("Gagal memindai baris mutasi")
+			return nil, err
+		}
+		if l.Status != model.StatementLineReview {
+			ids = nil
+		}
+		lines = append(lines, l)
+		candidateIDs = append(candidateIDs, ids)
+		allIDs = append(allIDs, ids...)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(allIDs) == 0 {
+		return lines, nil
+	}
+
+	paymentRows, err := s.DB.Query(ctx, `
+        SELECT id, customer_id, customer_name, amount, status, payment_date, due_date, reconciliation_status
+        FROM payments
+        WHERE id = ANY($1)`, allIDs)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Gagal mengambil pembayaran kandidat")
+		return nil, err
+	}
+	defer paymentRows.Close()
+
+	payments, err := scanPayments(paymentRows)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[int]model.Payment, len(payments))
+	for _, p := range payments {
+		byID[p.ID] = p
+	}
+	for i, ids := range candidateIDs {
+		for _, id := range ids {
+			if p, ok := byID[id]; ok {
+				lines[i].Candidates = append(lines[i].Candidates, p)
+			}
+		}
+	}
+
+	return lines, nil
+}
+
+// MatchLine mencocokkan baris mutasi dengan pembayaran secara manual.
+// storage.ErrConflict dikembalikan bila baris sudah cocok atau berupa dana keluar,
+// atau bila pembayaran sudah direkonsiliasi dengan baris lain, dan
+// storage.ErrMismatch bila nominal atau tanggal pembayaran tidak sesuai.
+func (s *PostgresReconciliationStore) MatchLine(ctx context.Context, orgID string, lineID, paymentID, windowDays int) (model.BankStatementLine, error) {
+	tx, err := s.DB.Begin(ctx)
+	if err != nil {
+		return model.BankStatementLine{}, fmt.Errorf("kesalahan saat memulai transaksi rekonsiliasi: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	status, _, err := lockLine(ctx, tx, orgID, lineID)
+	if err != nil {
+		return model.BankStatementLine{}, err
+	}
+	if status == model.StatementLineMatched || status == model.StatementLineIgnored {
+		return model.BankStatementLine{}, storage.ErrConflict
+	}
+
+	// Pembayaran pilihan pengguna harus memenuhi syarat yang sama dengan
+	// kandidat otomatis pada lockCandidates: nominal sama dan tanggal pembayaran
+	// atau jatuh temponya dalam rentang windowDays hari dari tanggal mutasi.
+	var (
+		reconciliation string
+		eligible       bool
+	)
+	err = tx.QueryRow(ctx, `
+        SELECT p.reconciliation_status,
+               COALESCE(p.status <> 'Dibatalkan'
+                   AND ABS(p.amount - l.amount) < 0.005
+                   AND (p.payment_date::date BETWEEN l.booking_date - $3::int AND l.booking_date + $3::int
+                        OR p.due_date BETWEEN l.booking_date - $3::int AND l.booking_date + $3::int), false)
+        FROM payments p, bank_statement_lines l
+        WHERE p.id = $1 AND l.id = $2
+        FOR UPDATE OF p`, paymentID, lineID, windowDays).Scan(&reconciliation, &eligible)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.BankStatementLine{}, storage.ErrNotFound
+		}
+		return model.BankStatementLine{}, fmt.Errorf("kesalahan saat mengambil pembayaran %d: %w", paymentID, err)
+	}
+	if reconciliation != model.ReconciliationUnreconciled {
+		return model.BankStatementLine{}, storage.ErrConflict
+	}
+	if !eligible {
+		return model.BankStatementLine{}, storage.ErrMismatch
+	}
+
+	_, err = tx.Exec(ctx, `
+        UPDATE bank_statement_lines
+        SET status = 'cocok', payment_id = $2, match_method = $3, matched_at = NOW()
+        WHERE id = $1`, lineID, paymentID, model.MatchMethodManual)
+	if err != nil {
+		return model.BankStatementLine{}, fmt.Errorf("kesalahan saat mencocokkan baris mutasi: %w", err)
+	}
+	if err := setReconciliationStatus(ctx, tx, paymentID, model.ReconciliationReconciled); err != nil {
+		return model.BankStatementLine{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return model.BankStatementLine{}, fmt.Errorf("kesalahan saat menyimpan rekonsiliasi: %w", err)
+	}
+	return s.getLine(ctx, orgID, lineID)
+}
+
+// UnmatchLine membatalkan kecocokan baris mutasi sehingga pembayarannya kembali
+// belum direkonsiliasi. storage.ErrConflict dikembalikan bila baris belum cocok.
+func (s *PostgresReconciliationStore) UnmatchLine(ctx context.Context, orgID string, lineID int) (model.BankStatementLine, error) {
+	tx, err := s.DB.Begin(ctx)
+	if err != nil {
+		return model.BankStatementLine{}, fmt.Errorf("kesalahan saat memulai transaksi rekonsiliasi: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	status, paymentID, err := lockLine(ctx, tx, orgID, lineID)
+	if err != nil {
+		return model.BankStatementLine{}, err
+	}
+	if status != model.StatementLineMatched {
+		return model.BankStatementLine{}, storage.ErrConflict
+	}
+
+	// Baris yang sebelumnya ambigu kembali ke antrean tinjauan.
+	_, err = tx.Exec(ctx, `
+        UPDATE bank_statement_lines
+        SET status = CASE WHEN cardinality(candidate_payment_ids) > 0 THEN 'perlu_tinjauan' ELSE 'belum_cocok' END,
+            payment_id = NULL, match_method = '', matched_at = NULL
+        WHERE id = $1`, lineID)
+	if err != nil {
+		return model.BankStatementLine{}, fmt.Errorf("kesalahan saat membatalkan kecocokan baris mutasi: %w", err)
+	}
+	if paymentID != nil {
+		if err := setReconciliationStatus(ctx, tx, *paymentID, model.ReconciliationUnreconciled); err != nil {
+			return model.BankStatementLine{}, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return model.BankStatementLine{}, fmt.Errorf("kesalahan saat menyimpan rekonsiliasi: %w", err)
+	}
+	return s.getLine(ctx, orgID, lineID)
+}
+
+// lockLine mengunci baris mutasi milik organisasi dan mengembalikan status serta
+// pembayaran yang dicocokkan dengannya.
+func lockLine(ctx context.Context, tx pgx.Tx, orgID string, lineID int) (string, *int, error) {
+	var (
+		status    string
+		paymentID *int
+	)
+	err := tx.QueryRow(ctx, `
+        SELECT l.status, l.payment_id
+        FROM bank_statement_lines l
+        JOIN bank_statements bs ON bs.id = l.statement_id
+        WHERE l.id = $1 AND bs.organization_id = $2
+        FOR UPDATE OF l`, lineID, orgID).Scan(&status, &paymentID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", nil, storage.ErrNotFound
+		}
+		return "", nil, fmt.Errorf("kesalahan saat mengambil baris mutasi: %w", err)
+	}
+	return status, paymentID, nil
+}
+
+func (s *PostgresReconciliationStore) getLine(ctx context.Context, orgID string, lineID int) (model.BankStatementLine, error) {
+	lines, err := s.queryLines(ctx, `
+        SELECT `+statementLineColumns+`
+        FROM bank_statement_lines l
+        JOIN bank_statements bs ON bs.id = l.statement_id
+        WHERE bs.organization_id = $1 AND l.id = $2`, orgID, lineID)
+	if err != nil {
+		return model.BankStatementLine{}, err
+	}
+	if len(lines) == 0 {
+		return model.BankStatementLine{}, storage.ErrNotFound
+	}
+	return lines[0], nil
+}
+
+func scanBankStatement(row pgx.Row) (model.BankStatement, error) {
+	var st model.BankStatement
+	err := row.Scan(&st.ID, &st.FileName, &st.Format, &st.AccountID, &st.UploadedAt,
+		&st.LineCount, &st.MatchedCount, &st.ReviewCount, &st.UnmatchedCount)
+	return st, err
+}
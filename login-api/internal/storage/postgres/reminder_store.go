@@ -0,0 +1,83 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"login-api/internal/model"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+type PostgresReminderStore struct {
+	DB *DB
+}
+
+func NewPostgresReminderStore(db *DB) *PostgresReminderStore {
+	return &PostgresReminderStore{DB: db}
+}
+
+// ListReminderCandidates mengambil pembayaran belum lunas milik pelanggan yang memiliki
+// email, yang sudah memasuki jadwal pengingat paling awal (due_date + minOffset <= today)
+// dan belum menerima pengingat terakhir (maxOffset).
+func (s *PostgresReminderStore) ListReminderCandidates(ctx context.Context, today time.Time, minOffset, maxOffset int) ([]model.ReminderCandidate, error) {
+	query := `
+        SELECT p.id, p.customer_id, p.customer_name, p.amount, p.status, p.payment_date, p.due_date, c.email
+        FROM payments p
+        JOIN customers c ON c.id = p.customer_id
+        WHERE p.status IN ('Tertunda', 'Terlambat')
+          AND p.due_date IS NOT NULL
+          AND p.due_date + $2::int <= $1::date
+          AND c.email <> ''
+          AND NOT EXISTS (
+              SELECT 1 FROM payment_reminders r
+              WHERE r.payment_id = p.id AND r.offset_days = $3
+          )
+        ORDER BY p.due_date, p.id
+    `
+	rows, err := s.DB.Query(ctx, query, today, minOffset, maxOffset)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Gagal menjalankan query untuk kandidat pengingat")
+		return nil, err
+	}
+	defer rows.Close()
+
+	var candidates []model.ReminderCandidate
+	for rows.Next() {
+		var c model.ReminderCandidate
+		p := &c.Payment
+		if err := rows.Scan(&p.ID, &p.CustomerID, &p.CustomerName, &p.Amount, &p.Status, &p.PaymentDate, &p.DueDate, &c.CustomerEmail); err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("Gagal memindai baris kandidat pengingat")
+			return nil, err
+		}
+		candidates = append(candidates, c)
+	}
+
+	return candidates, rows.Err()
+}
+
+// ClaimReminder mencatat bahwa pengingat untuk (paymentID, offsetDays) akan dikirim.
+// Mengembalikan false jika pengingat tersebut sudah pernah diklaim, sehingga beberapa
+// instance tidak mengirim email yang sama dua kali.
+func (s *PostgresReminderStore) ClaimReminder(ctx context.Context, paymentID, offsetDays int) (bool, error) {
+	tag, err := s.DB.Exec(ctx, `
+        INSERT INTO payment_reminders (payment_id, offset_days)
+        VALUES ($1, $2)
+        ON CONFLICT DO NOTHING`, paymentID, offsetDays)
+	if err != nil {
+		return false, fmt.Errorf("kesalahan saat mencatat pengingat pembayaran: %w", err)
+	}
+
+	return tag.RowsAffected() == 1, nil
+}
+
+// ReleaseReminder menghapus klaim pengingat yang gagal dikirim agar dicoba lagi nanti.
+func (s *PostgresReminderStore) ReleaseReminder(ctx context.Context, paymentID, offsetDays int) error {
+	_, err := s.DB.Exec(ctx,
+		`DELETE FROM payment_reminders WHERE payment_id = $1 AND offset_days = $2`, paymentID, offsetDays)
+	if err != nil {
+		return fmt.Errorf("kesalahan saat menghapus catatan pengingat pembayaran: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,176 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"login-api/internal/model"
+	"login-api/internal/storage"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog/log"
+)
+
+type PostgresReportStore struct {
+	DB *DB
+}
+
+func NewPostgresReportStore(db *DB) *PostgresReportStore {
+	return &PostgresReportStore{DB: db}
+}
+
+// reportSubscriptionColumns menyertakan email pemilik langganan agar dapat
+// dipakai baik pada SELECT maupun RETURNING.
+const reportSubscriptionColumns = `id, (SELECT email FROM users WHERE users.id = user_id), period, schedule,
+    timezone, active, next_run_at, last_run_at, last_error, attempts, retry_at, created_at, updated_at`
+
+// userIDByEmail adalah subquery id pengguna dengan email pada parameter $1.
+const userIDByEmail = `(SELECT id FROM users WHERE email = $1)`
+
+// ListSubscriptions mengambil langganan laporan milik pengguna email.
+func (s *PostgresReportStore) ListSubscriptions(ctx context.Context, email string) ([]model.ReportSubscription, error) {
+	rows, err := s.DB.Query(ctx, `SELECT `+reportSubscriptionColumns+`
+        FROM report_subscriptions
+        WHERE user_id = `+userIDByEmail+`
+        ORDER BY id`,
+		email)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Gagal menjalankan query untuk mengambil langganan laporan")
+		return nil, err
+	}
+	return collectReportSubscriptions(rows)
+}
+
+// ListDueSubscriptions mengambil paling banyak limit langganan aktif yang
+// next_run_at-nya, atau retry_at bila sedang menunggu percobaan ulang, tidak
+// lebih dari now.
+func (s *PostgresReportStore) ListDueSubscriptions(ctx context.Context, now time.Time, limit int) ([]model.ReportSubscription, error) {
+	rows, err := s.DB.Query(ctx, `SELECT `+reportSubscriptionColumns+`
+        FROM report_subscriptions
+        WHERE active AND COALESCE(retry_at, next_run_at) <= $1
+        ORDER BY COALESCE(retry_at, next_run_at)
+        LIMIT $2`,
+		now, limit)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Gagal menjalankan query untuk langganan laporan yang jatuh tempo")
+		return nil, err
+	}
+	return collectReportSubscriptions(rows)
+}
+
+// CreateSubscription menyimpan langganan laporan baru untuk pengguna sub.Email.
+func (s *PostgresReportStore) CreateSubscription(ctx context.Context, sub model.ReportSubscription) (model.ReportSubscription, error) {
+	created, err := scanReportSubscription(s.DB.QueryRow(ctx, `
+        INSERT INTO report_subscriptions (user_id, period, schedule, timezone, active, next_run_at)
+        SELECT id, $2, $3, $4, $5, $6 FROM users WHERE email = $1
+        RETURNING `+reportSubscriptionColumns,
+		sub.Email, sub.Period, sub.Schedule, sub.Timezone, sub.Active, sub.NextRunAt))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.ReportSubscription{}, storage.ErrNotFound
+		}
+		return model.ReportSubscription{}, fmt.Errorf("kesalahan saat menyimpan langganan laporan ke database: %w", err)
+	}
+	return created, nil
+}
+
+// UpdateSubscription memperbarui periode, jadwal, zona waktu, status aktif, dan
+// waktu jalan berikutnya langganan laporan milik pengguna sub.Email. Percobaan
+// ulang yang tertunda dibatalkan karena jadwalnya sudah dihitung ulang.
+func (s *PostgresReportStore) UpdateSubscription(ctx context.Context, sub model.ReportSubscription) (model.ReportSubscription, error) {
+	updated, err := scanReportSubscription(s.DB.QueryRow(ctx, `
+        UPDATE report_subscriptions
+        SET period = $3, schedule = $4, timezone = $5, active = $6, next_run_at = $7,
+            attempts = 0, retry_at = NULL, updated_at = NOW()
+        WHERE user_id = `+userIDByEmail+` AND id = $2
+        RETURNING `+reportSubscriptionColumns,
+		sub.Email, sub.ID, sub.Period, sub.Schedule, sub.Timezone, sub.Active, sub.NextRunAt))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.ReportSubscription{}, storage.ErrNotFound
+		}
+		return model.ReportSubscription{}, fmt.Errorf("kesalahan saat memperbarui langganan laporan di database: %w", err)
+	}
+	return updated, nil
+}
+
+// GetSubscription mengambil satu langganan laporan milik pengguna email.
+func (s *PostgresReportStore) GetSubscription(ctx context.Context, email string, id int) (model.ReportSubscription, error) {
+	sub, err := scanReportSubscription(s.DB.QueryRow(ctx, `SELECT `+reportSubscriptionColumns+`
+        FROM report_subscriptions
+        WHERE user_id = `+userIDByEmail+` AND id = $2`,
+		email, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.ReportSubscription{}, storage.ErrNotFound
+		}
+		return model.ReportSubscription{}, fmt.Errorf("kesalahan saat mengambil langganan laporan: %w", err)
+	}
+	return sub, nil
+}
+
+// DeleteSubscription menghapus langganan laporan milik pengguna email.
+func (s *PostgresReportStore) DeleteSubscription(ctx context.Context, email string, id int) error {
+	tag, err := s.DB.Exec(ctx,
+		`DELETE FROM report_subscriptions WHERE user_id = `+userIDByEmail+` AND id = $2`, email, id)
+	if err != nil {
+		return fmt.Errorf("kesalahan saat menghapus langganan laporan: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return storage.ErrNotFound
+	}
+	return nil
+}
+
+// MarkRun mencatat hasil pengiriman laporan dan menjadwalkan pengiriman berikutnya.
+// runErr kosong berarti pengiriman berhasil; bila tidak kosong, percobaan untuk
+// jadwal ini sudah dihentikan.
+func (s *PostgresReportStore) MarkRun(ctx context.Context, id int, ranAt, nextRunAt time.Time, runErr string) error {
+	_, err := s.DB.Exec(ctx, `
+        UPDATE report_subscriptions
+        SET last_run_at = $2, next_run_at = $3, last_error = $4, attempts = 0, retry_at = NULL
+        WHERE id = $1`,
+		id, ranAt, nextRunAt, runErr)
+	if err != nil {
+		return fmt.Errorf("kesalahan saat mencatat pengiriman laporan: %w", err)
+	}
+	return nil
+}
+
+// MarkRetry mencatat pengiriman laporan yang gagal dan menjadwalkan percobaan
+// ulang pada retryAt. next_run_at tidak diubah agar periode laporan tetap sama.
+func (s *PostgresReportStore) MarkRetry(ctx context.Context, id int, ranAt, retryAt time.Time, runErr string) error {
+	_, err := s.DB.Exec(ctx, `
+        UPDATE report_subscriptions
+        SET last_run_at = $2, retry_at = $3, last_error = $4, attempts = attempts + 1
+        WHERE id = $1`,
+		id, ranAt, retryAt, runErr)
+	if err != nil {
+		return fmt.Errorf("kesalahan saat menjadwalkan ulang pengiriman laporan: %w", err)
+	}
+	return nil
+}
+
+func collectReportSubscriptions(rows pgx.Rows) ([]model.ReportSubscription, error) {
+	defer rows.Close()
+
+	var subs []model.ReportSubscription
+	for rows.Next() {
+		sub, err := scanReportSubscription(rows)
+		if err != nil {
+			log.Error().Err(err).Msg("Gagal memindai baris langganan laporan")
+			return nil, err
+		}
+		subs = append(subs, sub)
+	}
+
+	return subs, rows.Err()
+}
+
+func scanReportSubscription(row pgx.Row) (model.ReportSubscription, error) {
+	var sub model.ReportSubscription
+	err := row.Scan(&sub.ID, &sub.Email, &sub.Period, &sub.Schedule, &sub.Timezone, &sub.Active,
+		&sub.NextRunAt, &sub.LastRunAt, &sub.LastError, &sub.Attempts, &sub.RetryAt, &sub.CreatedAt, &sub.UpdatedAt)
+	return sub, err
+}
@@ -0,0 +1,211 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"login-api/internal/events"
+	"login-api/internal/model"
+	"login-api/internal/storage"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog/log"
+)
+
+type PostgresSubscriptionStore struct {
+	DB *DB
+}
+
+func NewPostgresSubscriptionStore(db *DB) *PostgresSubscriptionStore {
+	return &PostgresSubscriptionStore{DB: db}
+}
+
+const subscriptionColumns = `
+    s.id, s.customer_id, c.name, s.description, s.amount, s.interval_unit, s.interval_count,
+    s.start_date, s.generated_count, s.next_due_date, s.status, s.created_at, s.updated_at`
+
+// ListSubscriptions mengambil semua langganan.
+func (s *PostgresSubscriptionStore) ListSubscriptions(ctx context.Context) ([]model.Subscription, error) {
+	query := `SELECT ` + subscriptionColumns + `
+        FROM subscriptions s
+        JOIN customers c ON c.id = s.customer_id
+        ORDER BY s.next_due_date, s.id`
+
+	return s.querySubscriptions(ctx, query)
+}
+
+// ListActiveSubscriptionsDueBy mengambil langganan aktif yang jatuh tempo paling lambat pada tanggal until.
+func (s *PostgresSubscriptionStore) ListActiveSubscriptionsDueBy(ctx context.Context, until time.Time) ([]model.Subscription, error) {
+	query := `SELECT ` + subscriptionColumns + `
+        FROM subscriptions s
+        JOIN customers c ON c.id = s.customer_id
+        WHERE s.status = 'Aktif' AND s.next_due_date <= $1::date
+        ORDER BY s.next_due_date, s.id`
+
+	return s.querySubscriptions(ctx, query, until)
+}
+
+func (s *PostgresSubscriptionStore) querySubscriptions(ctx context.Context, query string, args ...interface{}) ([]model.Subscription, error) {
+	rows, err := s.DB.Query(ctx, query, args...)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Gagal menjalankan query untuk mengambil langganan")
+		return nil, err
+	}
+	defer rows.Close()
+
+	var subs []model.Subscription
+	for rows.Next() {
+		sub, err := scanSubscription(rows)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("Gagal memindai baris langganan")
+			return nil, err
+		}
+		subs = append(subs, sub)
+	}
+
+	return subs, rows.Err()
+}
+
+// GetSubscriptionByID mengambil satu langganan berdasarkan ID.
+func (s *PostgresSubscriptionStore) GetSubscriptionByID(ctx context.Context, id int) (model.Subscription, error) {
+	query := `SELECT ` + subscriptionColumns + `
+        FROM subscriptions s
+        JOIN customers c ON c.id = s.customer_id
+        WHERE s.id = $1`
+
+	sub, err := scanSubscription(s.DB.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.Subscription{}, storage.ErrNotFound
+		}
+		log.Ctx(ctx).Error().Err(err).Int("subscription_id", id).Msg("Gagal mengambil data langganan")
+		return model.Subscription{}, err
+	}
+
+	return sub, nil
+}
+
+// CreateSubscription menyimpan langganan baru. storage.ErrNotFound dikembalikan
+// jika pelanggan yang dirujuk tidak ada.
+func (s *PostgresSubscriptionStore) CreateSubscription(ctx context.Context, sub model.Subscription) (model.Subscription, error) {
+	var id int
+	err := s.DB.QueryRow(ctx, `
+        INSERT INTO subscriptions (customer_id, description, amount, interval_unit, interval_count,
+                                   start_date, generated_count, next_due_date, status)
+        SELECT c.id, $2, $3, $4, $5, $6, 0, $6, 'Aktif'
+        FROM customers c
+        WHERE c.id = $1
+        RETURNING id`,
+		sub.CustomerID, sub.Description, sub.Amount, sub.IntervalUnit, sub.IntervalCount, sub.StartDate,
+	).Scan(&id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.Subscription{}, storage.ErrNotFound
+		}
+		return model.Subscription{}, fmt.Errorf("kesalahan saat menyimpan langganan ke database: %w", err)
+	}
+
+	return s.GetSubscriptionByID(ctx, id)
+}
+
+// UpdateSubscriptionSchedule menyimpan status dan posisi jadwal langganan.
+func (s *PostgresSubscriptionStore) UpdateSubscriptionSchedule(ctx context.Context, sub model.Subscription) error {
+	tag, err := s.DB.Exec(ctx, `
+        UPDATE subscriptions
+        SET status = $1, generated_count = $2, next_due_date = $3, updated_at = NOW()
+        WHERE id = $4`,
+		sub.Status, sub.GeneratedCount, sub.NextDueDate, sub.ID)
+	if err != nil {
+		return fmt.Errorf("kesalahan saat memperbarui langganan di database: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return storage.ErrNotFound
+	}
+
+	return nil
+}
+
+// GenerateDuePayments membuat pembayaran berstatus Tertunda untuk setiap jatuh tempo
+// langganan aktif hingga tanggal asOf, lalu memajukan jadwalnya. Seluruh proses
+// berjalan dalam satu transaksi dengan FOR UPDATE SKIP LOCKED sehingga aman
+// dijalankan oleh beberapa instance sekaligus. Event payment.created ditulis ke
+// outbox dalam transaksi yang sama. Mengembalikan pembayaran yang dibuat.
+func (s *PostgresSubscriptionStore) GenerateDuePayments(ctx context.Context, asOf time.Time) ([]model.Payment, error) {
+	tx, err := s.DB.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("kesalahan saat memulai transaksi penjadwal: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, `SELECT `+subscriptionColumns+`
+        FROM subscriptions s
+        JOIN customers c ON c.id = s.customer_id
+        WHERE s.status = 'Aktif' AND s.next_due_date <= $1::date
+        ORDER BY s.id
+        FOR UPDATE OF s SKIP LOCKED`, asOf)
+	if err != nil {
+		return nil, fmt.Errorf("kesalahan saat mengambil langganan jatuh tempo: %w", err)
+	}
+	var due []model.Subscription
+	for rows.Next() {
+		sub, err := scanSubscription(rows)
+		if err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("kesalahan saat memindai langganan jatuh tempo: %w", err)
+		}
+		due = append(due, sub)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("kesalahan saat mengambil langganan jatuh tempo: %w", err)
+	}
+
+	var created []model.Payment
+	for _, sub := range due {
+		for !sub.NextDueDate.After(asOf) {
+			var p model.Payment
+			err := tx.QueryRow(ctx, `
+                INSERT INTO payments (customer_id, customer_name, amount, status, payment_date, due_date, subscription_id)
+                VALUES ($1, $2, $3, 'Tertunda', $4, $4, $5)
+                ON CONFLICT DO NOTHING
+                RETURNING id, customer_id, customer_name, amount, status, payment_date, due_date, reconciliation_status`,
+				sub.CustomerID, sub.CustomerName, sub.Amount, sub.NextDueDate, sub.ID,
+			).Scan(&p.ID, &p.CustomerID, &p.CustomerName, &p.Amount, &p.Status, &p.PaymentDate, &p.DueDate, &p.ReconciliationStatus)
+			switch {
+			case err == nil:
+				if err := writeOutbox(ctx, tx, events.PaymentCreated, p); err != nil {
+					return nil, err
+				}
+				created = append(created, p)
+			case !errors.Is(err, pgx.ErrNoRows):
+				return nil, fmt.Errorf("kesalahan saat membuat pembayaran langganan %d: %w", sub.ID, err)
+			}
+			sub.GeneratedCount++
+			sub.NextDueDate = sub.Occurrence(sub.GeneratedCount)
+		}
+
+		_, err := tx.Exec(ctx, `
+            UPDATE subscriptions
+            SET generated_count = $1, next_due_date = $2, updated_at = NOW()
+            WHERE id = $3`,
+			sub.GeneratedCount, sub.NextDueDate, sub.ID)
+		if err != nil {
+			return nil, fmt.Errorf("kesalahan saat memajukan jadwal langganan %d: %w", sub.ID, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("kesalahan saat menyimpan pembayaran langganan: %w", err)
+	}
+
+	return created, nil
+}
+
+func scanSubscription(row pgx.Row) (model.Subscription, error) {
+	var sub model.Subscription
+	err := row.Scan(&sub.ID, &sub.CustomerID, &sub.CustomerName, &sub.Description, &sub.Amount,
+		&sub.IntervalUnit, &sub.IntervalCount, &sub.StartDate, &sub.GeneratedCount, &sub.NextDueDate,
+		&sub.Status, &sub.CreatedAt, &sub.UpdatedAt)
+	return sub, err
+}
@@ -0,0 +1,92 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"login-api/internal/events"
+	"login-api/internal/model"
+	"login-api/internal/storage"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const userColumns = `email, password_hash, role, disabled_at, sessions_revoked_at, created_at`
+
+func userFields(u *model.User) []interface{} {
+	return []interface{}{&u.Email, &u.PasswordHash, &u.Role, &u.DisabledAt, &u.SessionsRevokedAt, &u.CreatedAt}
+}
+
+// ListUsers mengambil semua pengguna, terurut berdasarkan email.
+func (s *PostgresUserStore) ListUsers(ctx context.Context) ([]model.User, error) {
+	rows, err := s.DB.Query(ctx, `SELECT `+userColumns+` FROM users ORDER BY email`)
+	if err != nil {
+		return nil, fmt.Errorf("kesalahan saat mengambil daftar pengguna: %w", err)
+	}
+	defer rows.Close()
+
+	var users []model.User
+	for rows.Next() {
+		var u model.User
+		if err := rows.Scan(userFields(&u)...); err != nil {
+			return nil, fmt.Errorf("kesalahan saat memindai baris pengguna: %w", err)
+		}
+		users = append(users, u)
+	}
+
+	return users, rows.Err()
+}
+
+// ResetUserPassword mengganti hash kata sandi dan mencabut semua sesi pengguna.
+func (s *PostgresUserStore) ResetUserPassword(ctx context.Context, email, passwordHash string) (model.User, error) {
+	return s.updateUser(ctx, `UPDATE users SET password_hash = $2, sessions_revoked_at = NOW() WHERE email = $1`, email, passwordHash)
+}
+
+// SetUserRole mengubah peran pengguna.
+func (s *PostgresUserStore) SetUserRole(ctx context.Context, email, role string) (model.User, error) {
+	return s.updateUser(ctx, `UPDATE users SET role = $2 WHERE email = $1`, email, role)
+}
+
+// SetUserDisabled menonaktifkan atau mengaktifkan kembali akun. Menonaktifkan
+// akun sekaligus mencabut semua sesinya.
+func (s *PostgresUserStore) SetUserDisabled(ctx context.Context, email string, disabled bool) (model.User, error) {
+	if disabled {
+		return s.updateUser(ctx, `
+            UPDATE users
+            SET disabled_at = COALESCE(disabled_at, NOW()), sessions_revoked_at = NOW()
+            WHERE email = $1`, email)
+	}
+	return s.updateUser(ctx, `UPDATE users SET disabled_at = NULL WHERE email = $1`, email)
+}
+
+// RevokeUserSessions menolak semua refresh token pengguna yang sudah diterbitkan.
+func (s *PostgresUserStore) RevokeUserSessions(ctx context.Context, email string) (model.User, error) {
+	return s.updateUser(ctx, `UPDATE users SET sessions_revoked_at = NOW() WHERE email = $1`, email)
+}
+
+// updateUser menjalankan UPDATE pada satu pengguna (email sebagai $1) lalu menulis
+// event user.updated ke outbox dalam transaksi yang sama.
+func (s *PostgresUserStore) updateUser(ctx context.Context, query string, args ...interface{}) (model.User, error) {
+	tx, err := s.DB.Begin(ctx)
+	if err != nil {
+		return model.User{}, fmt.Errorf("kesalahan saat memulai transaksi pengguna: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var user model.User
+	if err := tx.QueryRow(ctx, query+` RETURNING `+userColumns, args...).Scan(userFields(&user)...); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.User{}, storage.ErrNotFound
+		}
+		return model.User{}, fmt.Errorf("kesalahan saat memperbarui pengguna di database: %w", err)
+	}
+
+	if err := writeOutbox(ctx, tx, events.UserUpdated, user); err != nil {
+		return model.User{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return model.User{}, fmt.Errorf("kesalahan saat memperbarui pengguna di database: %w", err)
+	}
+
+	return user, nil
+}
@@ -0,0 +1,36 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"login-api/internal/storage"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// GetUserTimezone mengambil zona waktu pilihan pengguna. String kosong berarti
+// pengguna belum memilih.
+func (s *PostgresUserStore) GetUserTimezone(ctx context.Context, email string) (string, error) {
+	var tz string
+	err := s.DB.QueryRow(ctx, `SELECT timezone FROM users WHERE email = $1`, email).Scan(&tz)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", storage.ErrNotFound
+		}
+		return "", fmt.Errorf("kesalahan saat mengambil zona waktu pengguna: %w", err)
+	}
+	return tz, nil
+}
+
+// SetUserTimezone menyimpan zona waktu pilihan pengguna.
+func (s *PostgresUserStore) SetUserTimezone(ctx context.Context, email, tz string) error {
+	tag, err := s.DB.Exec(ctx, `UPDATE users SET timezone = $1 WHERE email = $2`, tz, email)
+	if err != nil {
+		return fmt.Errorf("kesalahan saat menyimpan zona waktu pengguna: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return storage.ErrNotFound
+	}
+	return nil
+}
@@ -3,58 +3,84 @@ package postgres
 import (
 	"context"
 	"fmt"
-	"log"
+	"login-api/internal/events"
 	"login-api/internal/model"
+	"login-api/internal/storage"
 
 	"github.com/jackc/pgx/v5"
-	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog/log"
 )
 
 type PostgresUserStore struct {
-	DB *pgxpool.Pool
+	DB *DB
 }
 
-func NewPostgresUserStore(db *pgxpool.Pool) *PostgresUserStore {
+func NewPostgresUserStore(db *DB) *PostgresUserStore {
 	return &PostgresUserStore{DB: db}
 }
 
 // GetUser mengambil data pengguna dari database berdasarkan email.
-func (s *PostgresUserStore) GetUser(email string) (model.User, bool) {
+func (s *PostgresUserStore) GetUser(ctx context.Context, email string) (model.User, error) {
 	var user model.User
-	query := "SELECT email, password_hash FROM users WHERE email = $1"
+	query := "SELECT " + userColumns + " FROM users WHERE email = $1"
 
-	err := s.DB.QueryRow(context.Background(), query, email).Scan(&user.Email, &user.PasswordHash)
+	err := s.DB.QueryRow(ctx, query, email).Scan(userFields(&user)...)
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return model.User{}, false
+			return model.User{}, storage.ErrNotFound
 		}
-		log.Printf("ERROR: Terjadi kesalahan saat mengambil data pengguna '%s': %v", email, err)
-		return model.User{}, false
+		log.Ctx(ctx).Error().Err(err).Str("email", email).Msg("Gagal mengambil data pengguna")
+		return model.User{}, fmt.Errorf("kesalahan saat mengambil pengguna: %w", err)
 	}
 
-	return user, true
+	return user, nil
 }
 
-// CreateUser memasukkan data pengguna baru ke dalam database.
-func (s *PostgresUserStore) CreateUser(user model.User) error {
-	query := "INSERT INTO users (email, password_hash) VALUES ($1, $2)"
+// CreateUser memasukkan data pengguna baru ke dalam database beserta event
+// user.created di outbox.
+func (s *PostgresUserStore) CreateUser(ctx context.Context, user model.User) error {
+	tx, err := s.DB.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("kesalahan saat memulai transaksi pengguna: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if user.Role == "" {
+		user.Role = model.RoleUser
+	}
+
+	query := "INSERT INTO users (email, password_hash, role) VALUES ($1, $2, $3)"
 
-	_, err := s.DB.Exec(context.Background(), query, user.Email, user.PasswordHash)
+	_, err = tx.Exec(ctx, query, user.Email, user.PasswordHash, user.Role)
 	if err != nil {
 		return fmt.Errorf("kesalahan saat menyimpan pengguna ke database: %w", err)
 	}
 
-	return nil
+	if err := writeOutbox(ctx, tx, events.UserCreated, user); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
 }
 
-// UpdateUser memperbarui data pengguna di database.
-func (s *PostgresUserStore) UpdateUser(oldEmail string, user model.User) error {
+// UpdateUser memperbarui data pengguna di database beserta event user.updated di outbox.
+func (s *PostgresUserStore) UpdateUser(ctx context.Context, oldEmail string, user model.User) error {
+	tx, err := s.DB.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("kesalahan saat memulai transaksi pengguna: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
 	query := "UPDATE users SET email = $1, password_hash = $2 WHERE email = $3"
 
-	_, err := s.DB.Exec(context.Background(), query, user.Email, user.PasswordHash, oldEmail)
+	_, err = tx.Exec(ctx, query, user.Email, user.PasswordHash, oldEmail)
 	if err != nil {
 		return fmt.Errorf("kesalahan saat memperbarui pengguna di database: %w", err)
 	}
 
-	return nil
+	if err := writeOutbox(ctx, tx, events.UserUpdated, user); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
 }
\ No newline at end of file
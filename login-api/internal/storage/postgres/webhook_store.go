@@ -0,0 +1,247 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"login-api/internal/events"
+	"login-api/internal/model"
+	"login-api/internal/storage"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog/log"
+)
+
+type PostgresWebhookStore struct {
+	DB *DB
+}
+
+func NewPostgresWebhookStore(db *DB) *PostgresWebhookStore {
+	return &PostgresWebhookStore{DB: db}
+}
+
+const webhookSubscriptionColumns = `id, organization_id, url, secret, event_types, active, created_at, updated_at`
+
+const webhookDeliveryColumns = `id, subscription_id, event_id, event_type, payload, status, attempts,
+    next_attempt_at, last_status_code, last_error, created_at, delivered_at`
+
+// ListSubscriptions mengambil semua langganan webhook milik organisasi.
+func (s *PostgresWebhookStore) ListSubscriptions(ctx context.Context, orgID string) ([]model.WebhookSubscription, error) {
+	rows, err := s.DB.Query(ctx,
+		`SELECT `+webhookSubscriptionColumns+` FROM webhook_subscriptions WHERE organization_id = $1 ORDER BY id`, orgID)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Gagal menjalankan query untuk mengambil langganan webhook")
+		return nil, err
+	}
+	defer rows.Close()
+
+	var subs []model.WebhookSubscription
+	for rows.Next() {
+		sub, err := scanWebhookSubscription(rows)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("Gagal memindai baris langganan webhook")
+			return nil, err
+		}
+		subs = append(subs, sub)
+	}
+
+	return subs, rows.Err()
+}
+
+// CreateSubscription menyimpan langganan webhook baru.
+func (s *PostgresWebhookStore) CreateSubscription(ctx context.Context, sub model.WebhookSubscription) (model.WebhookSubscription, error) {
+	created, err := scanWebhookSubscription(s.DB.QueryRow(ctx, `
+        INSERT INTO webhook_subscriptions (organization_id, url, secret, event_types, active)
+        VALUES ($1, $2, $3, $4, $5)
+        RETURNING `+webhookSubscriptionColumns,
+		sub.OrganizationID, sub.URL, sub.Secret, sub.EventTypes, sub.Active))
+	if err != nil {
+		return model.WebhookSubscription{}, fmt.Errorf("kesalahan saat menyimpan langganan webhook ke database: %w", err)
+	}
+	return created, nil
+}
+
+// UpdateSubscription memperbarui URL, jenis event, dan status aktif langganan webhook.
+func (s *PostgresWebhookStore) UpdateSubscription(ctx context.Context, sub model.WebhookSubscription) (model.WebhookSubscription, error) {
+	updated, err := scanWebhookSubscription(s.DB.QueryRow(ctx, `
+        UPDATE webhook_subscriptions
+        SET url = $1, event_types = $2, active = $3, updated_at = NOW()
+        WHERE id = $4 AND organization_id = $5
+        RETURNING `+webhookSubscriptionColumns,
+		sub.URL, sub.EventTypes, sub.Active, sub.ID, sub.OrganizationID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.WebhookSubscription{}, storage.ErrNotFound
+		}
+		return model.WebhookSubscription{}, fmt.Errorf("kesalahan saat memperbarui langganan webhook di database: %w", err)
+	}
+	return updated, nil
+}
+
+// DeleteSubscription menghapus langganan webhook beserta log pengirimannya.
+func (s *PostgresWebhookStore) DeleteSubscription(ctx context.Context, orgID string, id int) error {
+	tag, err := s.DB.Exec(ctx,
+		`DELETE FROM webhook_subscriptions WHERE id = $1 AND organization_id = $2`, id, orgID)
+	if err != nil {
+		return fmt.Errorf("kesalahan saat menghapus langganan webhook: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return storage.ErrNotFound
+	}
+	return nil
+}
+
+// EnqueueDeliveries membuat satu pengiriman tertunda untuk setiap langganan aktif
+// milik organisasi yang berminat pada jenis event ev. Langganan yang sudah memiliki
+// pengiriman untuk event yang sama dilewati, sehingga aman dipanggil ulang.
+func (s *PostgresWebhookStore) EnqueueDeliveries(ctx context.Context, orgID string, ev events.Event) (int64, error) {
+	payload, err := json.Marshal(ev)
+	if err != nil {
+		return 0, err
+	}
+
+	tag, err := s.DB.Exec(ctx, `
+        INSERT INTO webhook_deliveries (subscription_id, event_id, event_type, payload)
+        SELECT ws.id, $2, $3, $4
+        FROM webhook_subscriptions ws
+        WHERE ws.organization_id = $1 AND ws.active
+          AND ($3 = ANY(ws.event_types) OR '*' = ANY(ws.event_types))
+          AND NOT EXISTS (
+              SELECT 1 FROM webhook_deliveries d WHERE d.subscription_id = ws.id AND d.event_id = $2
+          )`,
+		orgID, ev.ID, ev.Type, payload)
+	if err != nil {
+		return 0, fmt.Errorf("kesalahan saat mengantrikan pengiriman webhook: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// ClaimDueDeliveries mengambil paling banyak limit pengiriman yang sudah waktunya
+// dikirim dan menunda next_attempt_at selama lease, sehingga pekerja lain tidak
+// mengambil pengiriman yang sama selama pengiriman berlangsung.
+func (s *PostgresWebhookStore) ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]model.WebhookDelivery, error) {
+	rows, err := s.DB.Query(ctx, `
+        WITH claimed AS (
+            UPDATE webhook_deliveries
+            SET next_attempt_at = NOW() + make_interval(secs => $2::float8)
+            WHERE id IN (
+                SELECT id FROM webhook_deliveries
+                WHERE status = 'tertunda' AND next_attempt_at <= NOW()
+                ORDER BY next_attempt_at
+                LIMIT $1
+                FOR UPDATE SKIP LOCKED
+            )
+            RETURNING `+webhookDeliveryColumns+`
+        )
+        SELECT claimed.*, ws.url, ws.secret
+        FROM claimed
+        JOIN webhook_subscriptions ws ON ws.id = claimed.subscription_id`,
+		limit, lease.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("kesalahan saat mengklaim pengiriman webhook: %w", err)
+	}
+	defer rows.Close()
+
+	var deliveries []model.WebhookDelivery
+	for rows.Next() {
+		var d model.WebhookDelivery
+		if err := rows.Scan(&d.ID, &d.SubscriptionID, &d.EventID, &d.EventType, &d.Payload, &d.Status, &d.Attempts,
+			&d.NextAttemptAt, &d.LastStatusCode, &d.LastError, &d.CreatedAt, &d.DeliveredAt, &d.URL, &d.Secret); err != nil {
+			return nil, fmt.Errorf("kesalahan saat memindai pengiriman webhook: %w", err)
+		}
+		deliveries = append(deliveries, d)
+	}
+
+	return deliveries, rows.Err()
+}
+
+// MarkDelivered mencatat pengiriman yang berhasil.
+func (s *PostgresWebhookStore) MarkDelivered(ctx context.Context, id, statusCode int) error {
+	_, err := s.DB.Exec(ctx, `
+        UPDATE webhook_deliveries
+        SET status = 'terkirim', attempts = attempts + 1, last_status_code = $2, last_error = '', delivered_at = NOW()
+        WHERE id = $1`, id, statusCode)
+	if err != nil {
+		return fmt.Errorf("kesalahan saat mencatat pengiriman webhook: %w", err)
+	}
+	return nil
+}
+
+// MarkAttemptFailed mencatat percobaan yang gagal. nextAttempt nil berarti batas
+// percobaan habis dan pengiriman ditandai gagal permanen.
+func (s *PostgresWebhookStore) MarkAttemptFailed(ctx context.Context, id int, statusCode *int, errMsg string, nextAttempt *time.Time) error {
+	_, err := s.DB.Exec(ctx, `
+        UPDATE webhook_deliveries
+        SET attempts = attempts + 1,
+            last_status_code = $2,
+            last_error = $3,
+            status = CASE WHEN $4::timestamptz IS NULL THEN 'gagal' ELSE 'tertunda' END,
+            next_attempt_at = COALESCE($4::timestamptz, next_attempt_at)
+        WHERE id = $1`, id, statusCode, errMsg, nextAttempt)
+	if err != nil {
+		return fmt.Errorf("kesalahan saat mencatat kegagalan webhook: %w", err)
+	}
+	return nil
+}
+
+// ListDeliveries mengambil log pengiriman terbaru untuk satu langganan webhook.
+func (s *PostgresWebhookStore) ListDeliveries(ctx context.Context, orgID string, subscriptionID, limit int) ([]model.WebhookDelivery, error) {
+	rows, err := s.DB.Query(ctx, `
+        SELECT d.id, d.subscription_id, d.event_id, d.event_type, d.payload, d.status, d.attempts,
+               d.next_attempt_at, d.last_status_code, d.last_error, d.created_at, d.delivered_at
+        FROM webhook_deliveries d
+        JOIN webhook_subscriptions ws ON ws.id = d.subscription_id
+        WHERE d.subscription_id = $1 AND ws.organization_id = $2
+        ORDER BY d.created_at DESC, d.id DESC
+        LIMIT $3`, subscriptionID, orgID, limit)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Gagal menjalankan query untuk log pengiriman webhook")
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []model.WebhookDelivery
+	for rows.Next() {
+		d, err := scanWebhookDelivery(rows)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("Gagal memindai baris pengiriman webhook")
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+
+	return deliveries, rows.Err()
+}
+
+// Redeliver mengantrikan ulang payload dari sebuah pengiriman sebagai pengiriman baru.
+func (s *PostgresWebhookStore) Redeliver(ctx context.Context, orgID string, deliveryID int) (model.WebhookDelivery, error) {
+	d, err := scanWebhookDelivery(s.DB.QueryRow(ctx, `
+        INSERT INTO webhook_deliveries (subscription_id, event_id, event_type, payload)
+        SELECT d.subscription_id, d.event_id, d.event_type, d.payload
+        FROM webhook_deliveries d
+        JOIN webhook_subscriptions ws ON ws.id = d.subscription_id
+        WHERE d.id = $1 AND ws.organization_id = $2
+        RETURNING `+webhookDeliveryColumns, deliveryID, orgID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.WebhookDelivery{}, storage.ErrNotFound
+		}
+		return model.WebhookDelivery{}, fmt.Errorf("kesalahan saat mengantrikan ulang webhook: %w", err)
+	}
+	return d, nil
+}
+
+func scanWebhookSubscription(row pgx.Row) (model.WebhookSubscription, error) {
+	var sub model.WebhookSubscription
+	err := row.Scan(&sub.ID, &sub.OrganizationID, &sub.URL, &sub.Secret, &sub.EventTypes, &sub.Active, &sub.CreatedAt, &sub.UpdatedAt)
+	return sub, err
+}
+
+func scanWebhookDelivery(row pgx.Row) (model.WebhookDelivery, error) {
+	var d model.WebhookDelivery
+	err := row.Scan(&d.ID, &d.SubscriptionID, &d.EventID, &d.EventType, &d.Payload, &d.Status, &d.Attempts,
+		&d.NextAttemptAt, &d.LastStatusCode, &d.LastError, &d.CreatedAt, &d.DeliveredAt)
+	return d, err
+}
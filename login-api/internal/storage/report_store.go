@@ -0,0 +1,24 @@
+package storage
+
+import (
+	"context"
+	"login-api/internal/model"
+	"time"
+)
+
+// ReportStore menyimpan langganan laporan dashboard per pengguna. Operasi pada
+// satu langganan mengembalikan ErrNotFound bila langganan tidak ada atau bukan
+// milik pengguna email.
+type ReportStore interface {
+	ListSubscriptions(ctx context.Context, email string) ([]model.ReportSubscription, error)
+	// ListDueSubscriptions mengambil paling banyak limit langganan aktif yang
+	// jadwal atau percobaan ulangnya sudah tiba pada now.
+	ListDueSubscriptions(ctx context.Context, now time.Time, limit int) ([]model.ReportSubscription, error)
+	CreateSubscription(ctx context.Context, sub model.ReportSubscription) (model.ReportSubscription, error)
+	UpdateSubscription(ctx context.Context, sub model.ReportSubscription) (model.ReportSubscription, error)
+	GetSubscription(ctx context.Context, email string, id int) (model.ReportSubscription, error)
+	DeleteSubscription(ctx context.Context, email string, id int) error
+	// MarkRun mencatat hasil pengiriman; runErr kosong berarti berhasil.
+	MarkRun(ctx context.Context, id int, ranAt, nextRunAt time.Time, runErr string) error
+	MarkRetry(ctx context.Context, id int, ranAt, retryAt time.Time, runErr string) error
+}
@@ -0,0 +1,97 @@
+// Package sqlite menyediakan implementasi store pengguna dan pembayaran di atas
+// SQLite (driver Go murni modernc.org/sqlite) untuk deployment kecil dan demo
+// offline tanpa PostgreSQL. Event outbox tidak ditulis.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	_ "embed"
+	"fmt"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+//go:embed schema.sql
+var schema string
+
+// timeLayout menyimpan waktu sebagai teks UTC dengan lebar tetap sehingga
+// perbandingan teks di SQL sama dengan perbandingan waktu.
+const timeLayout = "2006-01-02T15:04:05.000000Z"
+
+// dateLayout dipakai untuk kolom tanggal tanpa jam, misalnya due_date.
+const dateLayout = "2006-01-02"
+
+// DB membungkus sql.DB untuk dipakai store. Setiap method store dibatasi
+// QueryTimeout di samping tenggat ctx pemanggil; nol atau negatif berarti hanya
+// dibatasi ctx pemanggil.
+type DB struct {
+	*sql.DB
+	QueryTimeout time.Duration
+}
+
+// withTimeout menurunkan ctx dengan tenggat QueryTimeout.
+func (db *DB) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if db.QueryTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, db.QueryTimeout)
+}
+
+// Open membuka database SQLite di path lalu membuat tabel yang belum ada. Path
+// ":memory:" membuat database sementara di memori.
+func Open(path string, queryTimeout time.Duration) (*DB, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("kesalahan saat membuka database SQLite: %w", err)
+	}
+	// Satu koneksi menyerialkan penulisan sehingga tidak terjadi SQLITE_BUSY, dan
+	// database :memory: tetap sama untuk semua query.
+	db.SetMaxOpenConns(1)
+
+	ctx := context.Background()
+	for _, pragma := range []string{"PRAGMA journal_mode = WAL", "PRAGMA busy_timeout = 5000", "PRAGMA foreign_keys = ON"} {
+		if _, err := db.ExecContext(ctx, pragma); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("kesalahan saat mengatur database SQLite: %w", err)
+		}
+	}
+	if _, err := db.ExecContext(ctx, schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("kesalahan saat membuat skema SQLite: %w", err)
+	}
+	return &DB{DB: db, QueryTimeout: queryTimeout}, nil
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(timeLayout)
+}
+
+// optionalTime mengubah t menjadi parameter query; nil tetap NULL.
+func optionalTime(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return formatTime(*t)
+}
+
+func parseTime(s string) (time.Time, error) {
+	t, err := time.Parse(timeLayout, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("format waktu %q tidak valid: %w", s, err)
+	}
+	return t.Local(), nil
+}
+
+// parseOptionalTime mengubah kolom waktu yang boleh NULL.
+func parseOptionalTime(s sql.NullString) (*time.Time, error) {
+	if !s.Valid {
+		return nil, nil
+	}
+	t, err := parseTime(s.String)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
@@ -0,0 +1,368 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"login-api/internal/model"
+	"login-api/internal/storage"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+type SQLitePaymentStore struct {
+	DB *DB
+}
+
+func NewSQLitePaymentStore(db *DB) *SQLitePaymentStore {
+	return &SQLitePaymentStore{DB: db}
+}
+
+const paymentColumns = `id, customer_id, customer_name, amount, status, payment_date, due_date, reconciliation_status`
+
+// scanner adalah *sql.Row atau *sql.Rows.
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanPayment(row scanner) (model.Payment, error) {
+	var (
+		p           model.Payment
+		customerID  sql.NullInt64
+		paymentDate string
+		dueDate     sql.NullString
+	)
+	if err := row.Scan(&p.ID, &customerID, &p.CustomerName, &p.Amount, &p.Status, &paymentDate, &dueDate, &p.ReconciliationStatus); err != nil {
+		return model.Payment{}, err
+	}
+	if customerID.Valid {
+		id := int(customerID.Int64)
+		p.CustomerID = &id
+	}
+	var err error
+	if p.PaymentDate, err = parseTime(paymentDate); err != nil {
+		return model.Payment{}, err
+	}
+	if dueDate.Valid {
+		d, err := time.Parse(dateLayout, dueDate.String)
+		if err != nil {
+			return model.Payment{}, fmt.Errorf("format tanggal %q tidak valid: %w", dueDate.String, err)
+		}
+		p.DueDate = &d
+	}
+	return p, nil
+}
+
+func (s *SQLitePaymentStore) queryPayments(ctx context.Context, query string, args ...interface{}) ([]model.Payment, error) {
+	ctx, cancel := s.DB.withTimeout(ctx)
+	defer cancel()
+	rows, err := s.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Gagal menjalankan query untuk mengambil pembayaran")
+		return nil, err
+	}
+	defer rows.Close()
+
+	var payments []model.Payment
+	for rows.Next() {
+		p, err := scanPayment(rows)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("Gagal memindai baris pembayaran")
+			return nil, err
+		}
+		payments = append(payments, p)
+	}
+	return payments, rows.Err()
+}
+
+// GetPayments mengambil semua data pembayaran.
+func (s *SQLitePaymentStore) GetPayments(ctx context.Context) ([]model.Payment, error) {
+	return s.queryPayments(ctx, `SELECT `+paymentColumns+` FROM payments ORDER BY payment_date DESC`)
+}
+
+// GetPaymentsByCustomer mengambil semua pembayaran milik satu pelanggan.
+func (s *SQLitePaymentStore) GetPaymentsByCustomer(ctx context.Context, customerID int) ([]model.Payment, error) {
+	return s.queryPayments(ctx, `SELECT `+paymentColumns+` FROM payments WHERE customer_id = ? ORDER BY payment_date DESC`, customerID)
+}
+
+// GetPaymentByID mengambil satu data pembayaran berdasarkan ID.
+func (s *SQLitePaymentStore) GetPaymentByID(ctx context.Context, id int) (model.Payment, error) {
+	ctx, cancel := s.DB.withTimeout(ctx)
+	defer cancel()
+	p, err := scanPayment(s.DB.QueryRowContext(ctx, `SELECT `+paymentColumns+` FROM payments WHERE id = ?`, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Payment{}, storage.ErrNotFound
+		}
+		log.Ctx(ctx).Error().Err(err).Int("payment_id", id).Msg("Gagal mengambil data pembayaran")
+		return model.Payment{}, err
+	}
+	return p, nil
+}
+
+// ListPaymentsBetween mengambil pembayaran dengan payment_date dalam rentang [from, to).
+func (s *SQLitePaymentStore) ListPaymentsBetween(ctx context.Context, from, to time.Time) ([]model.Payment, error) {
+	return s.queryPayments(ctx, `
+        SELECT `+paymentColumns+`
+        FROM payments
+        WHERE payment_date >= ? AND payment_date < ?
+        ORDER BY payment_date, id`,
+		formatTime(from), formatTime(to))
+}
+
+// InsertPayments menyimpan payments dalam satu transaksi dan mengembalikan
+// jumlah yang disimpan. ID dan status rekonsiliasi diisi oleh database.
+func (s *SQLitePaymentStore) InsertPayments(ctx context.Context, payments []model.Payment) (int, error) {
+	ctx, cancel := s.DB.withTimeout(ctx)
+	defer cancel()
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("kesalahan saat memulai transaksi pembayaran: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, `
+        INSERT INTO payments (customer_id, customer_name, amount, status, payment_date, due_date)
+        VALUES (?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return 0, fmt.Errorf("kesalahan saat menyiapkan penyimpanan pembayaran: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, p := range payments {
+		var dueDate interface{}
+		if p.DueDate != nil {
+			dueDate = p.DueDate.Format(dateLayout)
+		}
+		if _, err := stmt.ExecContext(ctx, p.CustomerID, p.CustomerName, p.Amount, p.Status, formatTime(p.PaymentDate), dueDate); err != nil {
+			return 0, fmt.Errorf("kesalahan saat menyimpan pembayaran: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("kesalahan saat menyimpan pembayaran: %w", err)
+	}
+	return len(payments), nil
+}
+
+// CountPayments menghitung seluruh pembayaran yang tersimpan.
+func (s *SQLitePaymentStore) CountPayments(ctx context.Context) (int, error) {
+	ctx, cancel := s.DB.withTimeout(ctx)
+	defer cancel()
+	var n int
+	if err := s.DB.QueryRowContext(ctx, `SELECT COUNT(*) FROM payments`).Scan(&n); err != nil {
+		return 0, fmt.Errorf("kesalahan saat menghitung pembayaran: %w", err)
+	}
+	return n, nil
+}
+
+// summaryColumns adalah agregat yang membentuk model.DashboardSummary.
+const summaryColumns = `
+            COALESCE(SUM(CASE WHEN status = 'Lunas' THEN amount ELSE 0 END), 0),
+            COALESCE(SUM(CASE WHEN status = 'Lunas' THEN 1 ELSE 0 END), 0),
+            COALESCE(SUM(CASE WHEN status = 'Tertunda' THEN 1 ELSE 0 END), 0),
+            COALESCE(SUM(CASE WHEN status = 'Terlambat' THEN 1 ELSE 0 END), 0)`
+
+// GetDashboardSummary menghitung data ringkasan untuk seluruh pembayaran.
+func (s *SQLitePaymentStore) GetDashboardSummary(ctx context.Context) (model.DashboardSummary, error) {
+	return s.querySummary(ctx, `SELECT `+summaryColumns+` FROM payments`)
+}
+
+// GetPeriodSummary menghitung data ringkasan untuk pembayaran dengan payment_date
+// dalam rentang [from, to).
+func (s *SQLitePaymentStore) GetPeriodSummary(ctx context.Context, from, to time.Time) (model.DashboardSummary, error) {
+	return s.querySummary(ctx, `SELECT `+summaryColumns+` FROM payments WHERE payment_date >= ? AND payment_date < ?`,
+		formatTime(from), formatTime(to))
+}
+
+func (s *SQLitePaymentStore) querySummary(ctx context.Context, query string, args ...interface{}) (model.DashboardSummary, error) {
+	ctx, cancel := s.DB.withTimeout(ctx)
+	defer cancel()
+	var summary model.DashboardSummary
+	err := s.DB.QueryRowContext(ctx, query, args...).Scan(
+		&summary.TotalRevenue,
+		&summary.CompletedPayments,
+		&summary.PendingPayments,
+		&summary.OverduePayments,
+	)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Gagal menjalankan query untuk ringkasan dashboard")
+		return model.DashboardSummary{}, err
+	}
+	return summary, nil
+}
+
+// GetStatusBreakdown menghitung jumlah dan nilai pembayaran per status. from dan
+// to bersifat opsional dan membatasi payment_date pada rentang [from, to).
+func (s *SQLitePaymentStore) GetStatusBreakdown(ctx context.Context, from, to *time.Time) ([]model.StatusBreakdown, error) {
+	ctx, cancel := s.DB.withTimeout(ctx)
+	defer cancel()
+	rows, err := s.DB.QueryContext(ctx, `
+        SELECT status, COUNT(*), COALESCE(SUM(amount), 0)
+        FROM payments
+        WHERE (?1 IS NULL OR payment_date >= ?1)
+          AND (?2 IS NULL OR payment_date < ?2)
+        GROUP BY status
+        ORDER BY COUNT(*) DESC, status`,
+		optionalTime(from), optionalTime(to))
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Gagal menjalankan query untuk rincian status pembayaran")
+		return nil, err
+	}
+	defer rows.Close()
+
+	var breakdown []model.StatusBreakdown
+	for rows.Next() {
+		var b model.StatusBreakdown
+		if err := rows.Scan(&b.Status, &b.Count, &b.Amount); err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("Gagal memindai baris rincian status pembayaran")
+			return nil, err
+		}
+		breakdown = append(breakdown, b)
+	}
+	return breakdown, rows.Err()
+}
+
+// GetTopCustomers mengambil limit pelanggan dengan pendapatan lunas terbesar. from
+// dan to bersifat opsional dan membatasi payment_date pada rentang [from, to).
+func (s *SQLitePaymentStore) GetTopCustomers(ctx context.Context, from, to *time.Time, limit int) ([]model.CustomerBreakdown, error) {
+	ctx, cancel := s.DB.withTimeout(ctx)
+	defer cancel()
+	rows, err := s.DB.QueryContext(ctx, `
+        SELECT customer_id, customer_name, SUM(amount), COUNT(*)
+        FROM payments
+        WHERE status = 'Lunas'
+          AND (?1 IS NULL OR payment_date >= ?1)
+          AND (?2 IS NULL OR payment_date < ?2)
+        GROUP BY customer_id, customer_name
+        ORDER BY SUM(amount) DESC, customer_name
+        LIMIT ?3`,
+		optionalTime(from), optionalTime(to), limit)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Gagal menjalankan query untuk pelanggan teratas")
+		return nil, err
+	}
+	defer rows.Close()
+
+	var breakdown []model.CustomerBreakdown
+	for rows.Next() {
+		var (
+			b          model.CustomerBreakdown
+			customerID sql.NullInt64
+		)
+		if err := rows.Scan(&customerID, &b.CustomerName, &b.Revenue, &b.PaymentCount); err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("Gagal memindai baris pelanggan teratas")
+			return nil, err
+		}
+		if customerID.Valid {
+			id := int(customerID.Int64)
+			b.CustomerID = &id
+		}
+		breakdown = append(breakdown, b)
+	}
+	return breakdown, rows.Err()
+}
+
+// GetChartData mengambil pendapatan lunas yang diagregasi per interval dalam
+// rentang r. Interval tanpa pembayaran tetap muncul dengan nilai 0. SQLite tidak
+// memiliki generate_series maupun zona waktu IANA, sehingga batas setiap
+// interval dihitung di Go dari waktu lokal r.Location lalu dikirim sebagai
+// larik JSON [awal, akhir] yang dijadikan deret oleh json_each.
+func (s *SQLitePaymentStore) GetChartData(ctx context.Context, r model.ChartRange) ([]model.ChartData, error) {
+	ctx, cancel := s.DB.withTimeout(ctx)
+	defer cancel()
+	if !r.ValidInterval() {
+		return nil, fmt.Errorf("interval grafik %q tidak dikenali", r.Interval)
+	}
+	buckets := r.Buckets()
+	bounds := make([][2]string, len(buckets))
+	for i, b := range buckets {
+		bounds[i] = [2]string{formatTime(b), formatTime(r.Next(b))}
+	}
+	series, err := json.Marshal(bounds)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.DB.QueryContext(ctx, `
+        SELECT bucket.key, COALESCE(SUM(p.amount), 0)
+        FROM json_each(?) AS bucket
+        LEFT JOIN payments p
+            ON p.payment_date >= json_extract(bucket.value, '$[0]')
+           AND p.payment_date < json_extract(bucket.value, '$[1]')
+           AND p.status = 'Lunas'
+        GROUP BY bucket.key
+        ORDER BY bucket.key`,
+		string(series))
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Gagal menjalankan query untuk data grafik")
+		return nil, err
+	}
+	defer rows.Close()
+
+	var chartData []model.ChartData
+	for rows.Next() {
+		var (
+			i     int
+			value float64
+		)
+		if err := rows.Scan(&i, &value); err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("Gagal memindai baris data grafik")
+			return nil, err
+		}
+		chartData = append(chartData, model.ChartData{Label: r.Label(buckets[i]), Value: value})
+	}
+	return chartData, rows.Err()
+}
+
+// MarkOverduePayments mengubah status pembayaran tertunda yang due_date-nya sudah
+// lewat dari tanggal today menjadi Terlambat. Mengembalikan pembayaran yang diubah.
+func (s *SQLitePaymentStore) MarkOverduePayments(ctx context.Context, today time.Time) ([]model.Payment, error) {
+	marked, err := s.queryPayments(ctx, `
+        UPDATE payments
+        SET status = 'Terlambat'
+        WHERE status = 'Tertunda' AND due_date < ?
+        RETURNING `+paymentColumns,
+		today.Format(dateLayout))
+	if err != nil {
+		return nil, fmt.Errorf("kesalahan saat menandai pembayaran terlambat: %w", err)
+	}
+	return marked, nil
+}
+
+// GetAgingReport mengelompokkan pembayaran belum lunas yang sudah lewat jatuh
+// tempo berdasarkan umur keterlambatannya per tanggal today. Seperti
+// MarkOverduePayments, pembayaran yang jatuh tempo hari ini belum terhitung.
+func (s *SQLitePaymentStore) GetAgingReport(ctx context.Context, today time.Time) (model.AgingReport, error) {
+	ctx, cancel := s.DB.withTimeout(ctx)
+	defer cancel()
+	report := model.NewAgingReport(today)
+
+	rows, err := s.DB.QueryContext(ctx, `
+        SELECT CAST(julianday(?1) - julianday(due_date) AS INTEGER) AS days, COUNT(*), COALESCE(SUM(amount), 0)
+        FROM payments
+        WHERE status IN ('Tertunda', 'Terlambat') AND due_date < ?1
+        GROUP BY days`,
+		today.Format(dateLayout))
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Gagal menjalankan query untuk laporan umur piutang")
+		return model.AgingReport{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			days   int
+			count  int64
+			amount float64
+		)
+		if err := rows.Scan(&days, &count, &amount); err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("Gagal memindai baris laporan umur piutang")
+			return model.AgingReport{}, err
+		}
+		report.Add(days, count, amount)
+	}
+	return report, rows.Err()
+}
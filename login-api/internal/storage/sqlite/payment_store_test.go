@@ -0,0 +1,240 @@
+package sqlite
+
+import (
+	"context"
+	"login-api/internal/model"
+	"login-api/internal/storage/memory"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var newYork = mustLoadLocation("America/New_York")
+
+func mustLoadLocation(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		panic(err)
+	}
+	return loc
+}
+
+// newTestStores membuat SQLitePaymentStore di memori dan MemoryPaymentStore yang
+// berisi payments yang sama.
+func newTestStores(t *testing.T, payments []model.Payment) (*SQLitePaymentStore, *memory.MemoryPaymentStore) {
+	t.Helper()
+	db, err := Open(":memory:", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	store := NewSQLitePaymentStore(db)
+	if _, err := store.InsertPayments(context.Background(), payments); err != nil {
+		t.Fatal(err)
+	}
+	return store, memory.NewMemoryPaymentStore(payments)
+}
+
+// dstPayments mengelilingi awal DST New York pada Minggu, 8 Maret 2026 pukul
+// 02.00, saat jam melompat ke 03.00 sehingga hari itu hanya 23 jam.
+func dstPayments() []model.Payment {
+	at := func(day, hour, min int) time.Time {
+		return time.Date(2026, time.March, day, hour, min, 0, 0, newYork)
+	}
+	customer := func(id int) *int { return &id }
+	return []model.Payment{
+		{CustomerID: customer(1), CustomerName: "Andi", Amount: 10, Status: model.PaymentStatusPaid, PaymentDate: at(7, 23, 59)},
+		{CustomerID: customer(1), CustomerName: "Andi", Amount: 20, Status: model.PaymentStatusPaid, PaymentDate: at(8, 0, 0)},
+		{CustomerID: customer(2), CustomerName: "Budi", Amount: 40, Status: model.PaymentStatusPaid, PaymentDate: at(8, 1, 30)},
+		{CustomerID: customer(2), CustomerName: "Budi", Amount: 80, Status: model.PaymentStatusPaid, PaymentDate: at(8, 3, 0)},
+		{CustomerID: customer(2), CustomerName: "Budi", Amount: 160, Status: model.PaymentStatusPaid, PaymentDate: at(8, 23, 30)},
+		{CustomerID: customer(3), CustomerName: "Citra", Amount: 320, Status: model.PaymentStatusPaid, PaymentDate: at(9, 0, 0)},
+		{CustomerID: customer(3), CustomerName: "Citra", Amount: 640, Status: model.PaymentStatusPending, PaymentDate: at(8, 12, 0)},
+		{CustomerName: "Tanpa pelanggan", Amount: 1280, Status: model.PaymentStatusPaid, PaymentDate: at(2, 9, 0)},
+	}
+}
+
+func TestSQLitePaymentStoreChartData(t *testing.T) {
+	store, mem := newTestStores(t, dstPayments())
+
+	tests := []struct {
+		name     string
+		from, to time.Time
+		interval string
+		want     []model.ChartData
+	}{
+		{
+			// Jam 02.00 tidak ada pada hari pergantian DST.
+			name:     "jam melewati DST",
+			from:     time.Date(2026, 3, 8, 0, 0, 0, 0, newYork),
+			to:       time.Date(2026, 3, 8, 3, 59, 0, 0, newYork),
+			interval: model.ChartIntervalHour,
+			want: []model.ChartData{
+				{Label: "2026-03-08T00:00", Value: 20},
+				{Label: "2026-03-08T01:00", Value: 40},
+				{Label: "2026-03-08T03:00", Value: 80},
+			},
+		},
+		{
+			// Hari 8 Maret hanya 23 jam tetapi tetap berakhir tepat pada tengah malam lokal.
+			name:     "hari melewati DST",
+			from:     time.Date(2026, 3, 7, 0, 0, 0, 0, newYork),
+			to:       time.Date(2026, 3, 9, 23, 59, 59, 0, newYork),
+			interval: model.ChartIntervalDay,
+			want: []model.ChartData{
+				{Label: "2026-03-07", Value: 10},
+				{Label: "2026-03-08", Value: 300},
+				{Label: "2026-03-09", Value: 320},
+			},
+		},
+		{
+			// 8 Maret 2026 hari Minggu, sehingga masih termasuk minggu yang dimulai Senin 2 Maret.
+			name:     "minggu",
+			from:     time.Date(2026, 3, 1, 0, 0, 0, 0, newYork),
+			to:       time.Date(2026, 3, 15, 0, 0, 0, 0, newYork),
+			interval: model.ChartIntervalWeek,
+			want: []model.ChartData{
+				{Label: "2026-02-23", Value: 0},
+				{Label: "2026-03-02", Value: 1590},
+				{Label: "2026-03-09", Value: 320},
+			},
+		},
+		{
+			name:     "bulan",
+			from:     time.Date(2026, 1, 15, 0, 0, 0, 0, newYork),
+			to:       time.Date(2026, 3, 31, 0, 0, 0, 0, newYork),
+			interval: model.ChartIntervalMonth,
+			want: []model.ChartData{
+				{Label: "2026-01", Value: 0},
+				{Label: "2026-02", Value: 0},
+				{Label: "2026-03", Value: 1910},
+			},
+		},
+		{
+			name:     "tanpa pembayaran",
+			from:     time.Date(2026, 4, 1, 0, 0, 0, 0, newYork),
+			to:       time.Date(2026, 4, 3, 12, 0, 0, 0, newYork),
+			interval: model.ChartIntervalDay,
+			want: []model.ChartData{
+				{Label: "2026-04-01", Value: 0},
+				{Label: "2026-04-02", Value: 0},
+				{Label: "2026-04-03", Value: 0},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := model.ChartRange{From: tt.from, To: tt.to, Interval: tt.interval, Location: newYork}
+
+			got, err := store.GetChartData(context.Background(), r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SQLite GetChartData() = %v, want %v", got, tt.want)
+			}
+
+			fromMemory, err := mem.GetChartData(context.Background(), r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, fromMemory) {
+				t.Errorf("SQLite GetChartData() = %v, memory = %v", got, fromMemory)
+			}
+		})
+	}
+}
+
+func TestSQLitePaymentStoreChartDataInvalidInterval(t *testing.T) {
+	store, _ := newTestStores(t, nil)
+	r := model.ChartRange{From: time.Now(), To: time.Now(), Interval: "year", Location: time.UTC}
+	if _, err := store.GetChartData(context.Background(), r); err == nil {
+		t.Error("GetChartData() error = nil, want error untuk interval tidak dikenal")
+	}
+}
+
+func TestSQLitePaymentStoreAgingReport(t *testing.T) {
+	today := time.Date(2026, time.March, 31, 0, 0, 0, 0, time.UTC)
+	var payments []model.Payment
+	// Jatuh tempo 1 Desember sampai 2 April melewati batas setiap bucket, termasuk
+	// hari ini dan yang belum jatuh tempo.
+	for d := time.Date(2025, time.December, 1, 0, 0, 0, 0, time.UTC); !d.After(today.AddDate(0, 0, 2)); d = d.AddDate(0, 0, 1) {
+		due := d
+		status := model.PaymentStatusPending
+		switch d.Day() % 3 {
+		case 1:
+			status = model.PaymentStatusOverdue
+		case 2:
+			status = model.PaymentStatusPaid
+		}
+		payments = append(payments, model.Payment{
+			CustomerName: "Andi", Amount: float64(d.YearDay()), Status: status,
+			PaymentDate: d.AddDate(0, 0, -30), DueDate: &due,
+		})
+	}
+	store, mem := newTestStores(t, payments)
+
+	got, err := store.GetAgingReport(context.Background(), today)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := mem.GetAgingReport(context.Background(), today)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SQLite GetAgingReport() = %+v, memory = %+v", got, want)
+	}
+	if got.TotalCount == 0 {
+		t.Fatal("laporan kosong; data uji tidak mencakup piutang terlambat")
+	}
+	for _, b := range got.Buckets {
+		if b.Count == 0 {
+			t.Errorf("bucket %s kosong", b.Label)
+		}
+	}
+}
+
+func TestSQLitePaymentStoreTimeEncoding(t *testing.T) {
+	jakarta := mustLoadLocation("Asia/Jakarta")
+	paidAt := time.Date(2026, time.March, 9, 6, 30, 15, 123456789, jakarta)
+	store, _ := newTestStores(t, []model.Payment{
+		{CustomerName: "Andi", Amount: 1, Status: model.PaymentStatusPaid, PaymentDate: paidAt},
+		// Lebih awal secara waktu meskipun jam lokalnya lebih akhir.
+		{CustomerName: "Budi", Amount: 2, Status: model.PaymentStatusPaid, PaymentDate: time.Date(2026, time.March, 9, 7, 0, 0, 0, time.FixedZone("UTC+14", 14*3600))},
+	})
+
+	var raw string
+	if err := store.DB.QueryRow(`SELECT payment_date FROM payments WHERE customer_name = 'Andi'`).Scan(&raw); err != nil {
+		t.Fatal(err)
+	}
+	if want := "2026-03-08T23:30:15.123456Z"; raw != want {
+		t.Errorf("payment_date tersimpan sebagai %q, want %q", raw, want)
+	}
+
+	p, err := store.GetPaymentByID(context.Background(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := paidAt.Truncate(time.Microsecond); !p.PaymentDate.Equal(want) {
+		t.Errorf("PaymentDate = %v, want %v", p.PaymentDate, want)
+	}
+
+	// Rentang [from, to) dibandingkan sebagai teks UTC: batas bawah inklusif,
+	// batas atas eksklusif, dan urutan mengikuti waktu sebenarnya.
+	list, err := store.ListPaymentsBetween(context.Background(), time.Date(2026, time.March, 8, 17, 0, 0, 0, time.UTC), paidAt.Truncate(time.Microsecond).Add(time.Microsecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 || list[0].CustomerName != "Budi" || list[1].CustomerName != "Andi" {
+		t.Errorf("ListPaymentsBetween() = %+v, want Budi lalu Andi", list)
+	}
+	list, err = store.ListPaymentsBetween(context.Background(), time.Date(2026, time.March, 8, 17, 0, 0, 1000, time.UTC), paidAt.Truncate(time.Microsecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 0 {
+		t.Errorf("ListPaymentsBetween() = %+v, want kosong", list)
+	}
+}
@@ -0,0 +1,28 @@
+-- Skema SQLite untuk deployment kecil dan demo offline. Hanya mencakup tabel
+-- yang dipakai oleh SQLiteUserStore dan SQLitePaymentStore. Waktu disimpan
+-- sebagai teks UTC berformat tetap agar urutan teks sama dengan urutan waktu.
+
+CREATE TABLE IF NOT EXISTS users (
+    email               TEXT PRIMARY KEY,
+    password_hash       TEXT NOT NULL,
+    role                TEXT NOT NULL DEFAULT 'user',
+    timezone            TEXT NOT NULL DEFAULT '',
+    disabled_at         TEXT,
+    sessions_revoked_at TEXT,
+    created_at          TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS payments (
+    id                    INTEGER PRIMARY KEY AUTOINCREMENT,
+    customer_id           INTEGER,
+    customer_name         TEXT NOT NULL,
+    amount                REAL NOT NULL,
+    status                TEXT NOT NULL,
+    payment_date          TEXT NOT NULL,
+    due_date              TEXT,
+    reconciliation_status TEXT NOT NULL DEFAULT 'belum'
+);
+
+CREATE INDEX IF NOT EXISTS payments_payment_date_idx ON payments (payment_date);
+CREATE INDEX IF NOT EXISTS payments_customer_id_idx ON payments (customer_id);
+CREATE INDEX IF NOT EXISTS payments_status_due_date_idx ON payments (status, due_date);
@@ -0,0 +1,114 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"login-api/internal/model"
+	"login-api/internal/storage"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+type SQLiteUserStore struct {
+	DB *DB
+}
+
+func NewSQLiteUserStore(db *DB) *SQLiteUserStore {
+	return &SQLiteUserStore{DB: db}
+}
+
+// GetUser mengambil data pengguna berdasarkan email.
+func (s *SQLiteUserStore) GetUser(ctx context.Context, email string) (model.User, error) {
+	ctx, cancel := s.DB.withTimeout(ctx)
+	defer cancel()
+	var (
+		user                  model.User
+		disabledAt, revokedAt sql.NullString
+		createdAt             string
+	)
+	err := s.DB.QueryRowContext(ctx, `
+        SELECT email, password_hash, role, disabled_at, sessions_revoked_at, created_at
+        FROM users WHERE email = ?`, email,
+	).Scan(&user.Email, &user.PasswordHash, &user.Role, &disabledAt, &revokedAt, &createdAt)
+	if err == nil {
+		if user.DisabledAt, err = parseOptionalTime(disabledAt); err == nil {
+			if user.SessionsRevokedAt, err = parseOptionalTime(revokedAt); err == nil {
+				user.CreatedAt, err = parseTime(createdAt)
+			}
+		}
+	}
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.User{}, storage.ErrNotFound
+		}
+		log.Ctx(ctx).Error().Err(err).Str("email", email).Msg("Gagal mengambil data pengguna")
+		return model.User{}, fmt.Errorf("kesalahan saat mengambil pengguna: %w", err)
+	}
+	return user, nil
+}
+
+// CreateUser memasukkan data pengguna baru.
+func (s *SQLiteUserStore) CreateUser(ctx context.Context, user model.User) error {
+	ctx, cancel := s.DB.withTimeout(ctx)
+	defer cancel()
+	if user.Role == "" {
+		user.Role = model.RoleUser
+	}
+	_, err := s.DB.ExecContext(ctx,
+		`INSERT INTO users (email, password_hash, role, created_at) VALUES (?, ?, ?, ?)`,
+		user.Email, user.PasswordHash, user.Role, formatTime(time.Now()))
+	if err != nil {
+		if strings.Contains(err.Error(), "UNIQUE constraint failed") {
+			return storage.ErrDuplicate
+		}
+		return fmt.Errorf("kesalahan saat menyimpan pengguna ke database: %w", err)
+	}
+	return nil
+}
+
+// UpdateUser memperbarui email dan hash kata sandi pengguna oldEmail.
+func (s *SQLiteUserStore) UpdateUser(ctx context.Context, oldEmail string, user model.User) error {
+	ctx, cancel := s.DB.withTimeout(ctx)
+	defer cancel()
+	_, err := s.DB.ExecContext(ctx,
+		`UPDATE users SET email = ?, password_hash = ? WHERE email = ?`,
+		user.Email, user.PasswordHash, oldEmail)
+	if err != nil {
+		return fmt.Errorf("kesalahan saat memperbarui pengguna di database: %w", err)
+	}
+	return nil
+}
+
+// GetUserTimezone mengambil zona waktu pilihan pengguna. String kosong berarti
+// pengguna belum memilih.
+func (s *SQLiteUserStore) GetUserTimezone(ctx context.Context, email string) (string, error) {
+	ctx, cancel := s.DB.withTimeout(ctx)
+	defer cancel()
+	var tz string
+	err := s.DB.QueryRowContext(ctx, `SELECT timezone FROM users WHERE email = ?`, email).Scan(&tz)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", storage.ErrNotFound
+		}
+		return "", fmt.Errorf("kesalahan saat mengambil zona waktu pengguna: %w", err)
+	}
+	return tz, nil
+}
+
+// SetUserTimezone menyimpan zona waktu pilihan pengguna.
+func (s *SQLiteUserStore) SetUserTimezone(ctx context.Context, email, tz string) error {
+	ctx, cancel := s.DB.withTimeout(ctx)
+	defer cancel()
+	res, err := s.DB.ExecContext(ctx, `UPDATE users SET timezone = ? WHERE email = ?`, tz, email)
+	if err != nil {
+		return fmt.Errorf("kesalahan saat menyimpan zona waktu pengguna: %w", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return storage.ErrNotFound
+	}
+	return nil
+}
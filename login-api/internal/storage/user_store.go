@@ -1,9 +1,31 @@
 package storage
 
-import "login-api/internal/model"
+import (
+    "context"
+    "login-api/internal/model"
+)
 
 type UserStore interface {
-    GetUser(email string) (model.User, bool)
-    CreateUser(user model.User) error
-    UpdateUser(oldEmail string, user model.User) error
+    // GetUser mengembalikan ErrNotFound bila email tidak terdaftar.
+    GetUser(ctx context.Context, email string) (model.User, error)
+    CreateUser(ctx context.Context, user model.User) error
+    UpdateUser(ctx context.Context, oldEmail string, user model.User) error
+}
+
+// UserPreferenceStore menyimpan preferensi pengguna. String kosong berarti
+// pengguna belum memilih zona waktu.
+type UserPreferenceStore interface {
+    GetUserTimezone(ctx context.Context, email string) (string, error)
+    SetUserTimezone(ctx context.Context, email, tz string) error
+}
+
+// UserAdminStore menyediakan operasi administrasi akun pengguna. Operasi yang
+// mengubah pengguna mengembalikan ErrNotFound bila email tidak terdaftar.
+type UserAdminStore interface {
+    UserStore
+    ListUsers(ctx context.Context) ([]model.User, error)
+    ResetUserPassword(ctx context.Context, email, passwordHash string) (model.User, error)
+    SetUserRole(ctx context.Context, email, role string) (model.User, error)
+    SetUserDisabled(ctx context.Context, email string, disabled bool) (model.User, error)
+    RevokeUserSessions(ctx context.Context, email string) (model.User, error)
 }
\ No newline at end of file
@@ -58,12 +58,127 @@ export async function getPayments() {
     return handleResponse(response);
 }
 
-export async function getDashboardSummary() {
-    const response = await fetchWithAuth(`${API_BASE_URL}/dashboard/summary`);
+export async function getDashboardSummary(params = {}) {
+    const query = new URLSearchParams(params).toString();
+    const response = await fetchWithAuth(`${API_BASE_URL}/dashboard/summary${query ? `?${query}` : ''}`);
     return handleResponse(response);
 }
 
-export async function getChartData() {
-    const response = await fetchWithAuth(`${API_BASE_URL}/dashboard/chart`);
+export async function getChartData(params = {}) {
+    const query = new URLSearchParams(params).toString();
+    const response = await fetchWithAuth(`${API_BASE_URL}/dashboard/chart${query ? `?${query}` : ''}`);
     return handleResponse(response);
-}
\ No newline at end of file
+}
+
+export async function getStatusBreakdown(params = {}) {
+    const query = new URLSearchParams(params).toString();
+    const response = await fetchWithAuth(`${API_BASE_URL}/dashboard/breakdown/status${query ? `?${query}` : ''}`);
+    return handleResponse(response);
+}
+
+export async function getTopCustomers(params = {}) {
+    const query = new URLSearchParams(params).toString();
+    const response = await fetchWithAuth(`${API_BASE_URL}/dashboard/breakdown/customers${query ? `?${query}` : ''}`);
+    return handleResponse(response);
+}
+
+export async function getUpcomingPayments(days = 30) {
+    const response = await fetchWithAuth(`${API_BASE_URL}/dashboard/upcoming?days=${days}`);
+    return handleResponse(response);
+}
+
+// Berlangganan perubahan dashboard secara live. EventSource otomatis tersambung
+// kembali dan mengirim Last-Event-ID, tetapi tidak dapat memperbarui token akses.
+// Saat server mengirim auth.expired, atau sambungan ulang ditolak, token
+// diperbarui lewat /refresh lalu stream dibuka kembali dari event terakhir.
+// Kembalikan fungsi untuk menutup stream.
+export function subscribeDashboard({ onSnapshot, onDelta, onResync } = {}) {
+    let source;
+    let lastEventId = '';
+    let closed = false;
+    let retryTimer;
+
+    const track = (handler) => (e) => {
+        if (e.lastEventId) lastEventId = e.lastEventId;
+        if (handler) handler(JSON.parse(e.data));
+    };
+
+    const reopen = async () => {
+        source.close();
+        if (closed) return;
+        const response = await fetch(`${API_BASE_URL}/refresh`, { method: 'POST', credentials: 'include' }).catch(() => null);
+        // Sesi dicabut atau akun dinonaktifkan: berhenti, permintaan lain akan mengarahkan ke login.
+        if (!response || !response.ok) return;
+        if (!closed) open();
+    };
+
+    const open = () => {
+        const query = lastEventId ? `?last_event_id=${encodeURIComponent(lastEventId)}` : '';
+        source = new EventSource(`${API_BASE_URL}/dashboard/stream${query}`, { withCredentials: true });
+        source.addEventListener('dashboard.snapshot', track(onSnapshot));
+        source.addEventListener('dashboard.delta', track(onDelta));
+        source.addEventListener('dashboard.resync', (e) => {
+            if (e.lastEventId) lastEventId = e.lastEventId;
+            if (onResync) onResync();
+        });
+        source.addEventListener('auth.expired', reopen);
+        // EventSource berhenti mencoba bila sambungan ulang ditolak, misalnya 401.
+        source.onerror = () => {
+            if (source.readyState === EventSource.CLOSED && !closed) {
+                clearTimeout(retryTimer);
+                retryTimer = setTimeout(reopen, 3000);
+            }
+        };
+    };
+
+    open();
+    return () => {
+        closed = true;
+        clearTimeout(retryTimer);
+        source.close();
+    };
+}
+
+export async function getRevenueForecast(params = {}) {
+    const query = new URLSearchParams(params).toString();
+    const response = await fetchWithAuth(`${API_BASE_URL}/dashboard/forecast${query ? `?${query}` : ''}`);
+    return handleResponse(response);
+}
+
+export async function getAlerts(status = 'open') {
+    const response = await fetchWithAuth(`${API_BASE_URL}/alerts?status=${status}`);
+    return handleResponse(response);
+}
+
+export async function acknowledgeAlert(id) {
+    const response = await fetchWithAuth(`${API_BASE_URL}/alerts/${id}/acknowledge`, { method: "POST" });
+    return handleResponse(response);
+}
+
+export async function getReportSubscriptions() {
+    const response = await fetchWithAuth(`${API_BASE_URL}/reports/subscriptions`);
+    return handleResponse(response);
+}
+
+export async function createReportSubscription(subscription) {
+    const response = await fetchWithAuth(`${API_BASE_URL}/reports/subscriptions`, {
+        method: "POST",
+        headers: { "Content-Type": "application/json" },
+        body: JSON.stringify(subscription),
+    });
+    return handleResponse(response);
+}
+
+export async function updateReportSubscription(id, subscription) {
+    const response = await fetchWithAuth(`${API_BASE_URL}/reports/subscriptions/${id}`, {
+        method: "PUT",
+        headers: { "Content-Type": "application/json" },
+        body: JSON.stringify(subscription),
+    });
+    return handleResponse(response);
+}
+
+export async function deleteReportSubscription(id) {
+    const response = await fetchWithAuth(`${API_BASE_URL}/reports/subscriptions/${id}`, { method: "DELETE" });
+    return handleResponse(response);
+}